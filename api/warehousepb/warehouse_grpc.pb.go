@@ -19,8 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	WarehouseService_GetProducts_FullMethodName   = "/warehouse.WarehouseService/GetProducts"
-	WarehouseService_RemoveProduct_FullMethodName = "/warehouse.WarehouseService/RemoveProduct"
+	WarehouseService_GetProducts_FullMethodName           = "/warehouse.WarehouseService/GetProducts"
+	WarehouseService_RemoveProduct_FullMethodName         = "/warehouse.WarehouseService/RemoveProduct"
+	WarehouseService_AddArticleStock_FullMethodName       = "/warehouse.WarehouseService/AddArticleStock"
+	WarehouseService_GetArticle_FullMethodName            = "/warehouse.WarehouseService/GetArticle"
+	WarehouseService_TraceSerial_FullMethodName           = "/warehouse.WarehouseService/TraceSerial"
+	WarehouseService_PlanProductionMix_FullMethodName     = "/warehouse.WarehouseService/PlanProductionMix"
+	WarehouseService_SimulateAvailability_FullMethodName  = "/warehouse.WarehouseService/SimulateAvailability"
+	WarehouseService_UpdateReorderPolicy_FullMethodName   = "/warehouse.WarehouseService/UpdateReorderPolicy"
+	WarehouseService_UpdateArticle_FullMethodName         = "/warehouse.WarehouseService/UpdateArticle"
+	WarehouseService_UpdateProduct_FullMethodName         = "/warehouse.WarehouseService/UpdateProduct"
+	WarehouseService_ListLowStock_FullMethodName          = "/warehouse.WarehouseService/ListLowStock"
+	WarehouseService_SuggestReplenishment_FullMethodName  = "/warehouse.WarehouseService/SuggestReplenishment"
+	WarehouseService_CreateAssemblyOrder_FullMethodName   = "/warehouse.WarehouseService/CreateAssemblyOrder"
+	WarehouseService_CompleteAssemblyOrder_FullMethodName = "/warehouse.WarehouseService/CompleteAssemblyOrder"
+	WarehouseService_DisassembleProduct_FullMethodName    = "/warehouse.WarehouseService/DisassembleProduct"
+	WarehouseService_ListBackorders_FullMethodName        = "/warehouse.WarehouseService/ListBackorders"
+	WarehouseService_GeneratePickList_FullMethodName      = "/warehouse.WarehouseService/GeneratePickList"
 )
 
 // WarehouseServiceClient is the client API for WarehouseService service.
@@ -29,6 +44,22 @@ const (
 type WarehouseServiceClient interface {
 	GetProducts(ctx context.Context, in *GetProductsRequest, opts ...grpc.CallOption) (*GetProductsResponse, error)
 	RemoveProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*RemoveProductResponse, error)
+	AddArticleStock(ctx context.Context, in *AddArticleStockRequest, opts ...grpc.CallOption) (*AddArticleStockResponse, error)
+	GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error)
+	TraceSerial(ctx context.Context, in *TraceSerialRequest, opts ...grpc.CallOption) (*TraceSerialResponse, error)
+	PlanProductionMix(ctx context.Context, in *PlanProductionMixRequest, opts ...grpc.CallOption) (*PlanProductionMixResponse, error)
+	SimulateAvailability(ctx context.Context, in *SimulateAvailabilityRequest, opts ...grpc.CallOption) (*SimulateAvailabilityResponse, error)
+	UpdateReorderPolicy(ctx context.Context, in *UpdateReorderPolicyRequest, opts ...grpc.CallOption) (*UpdateReorderPolicyResponse, error)
+	// Update RPCs require the etag read last and fail with ABORTED if the item has changed since
+	UpdateArticle(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*UpdateArticleResponse, error)
+	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error)
+	ListLowStock(ctx context.Context, in *ListLowStockRequest, opts ...grpc.CallOption) (*ListLowStockResponse, error)
+	SuggestReplenishment(ctx context.Context, in *SuggestReplenishmentRequest, opts ...grpc.CallOption) (*SuggestReplenishmentResponse, error)
+	CreateAssemblyOrder(ctx context.Context, in *CreateAssemblyOrderRequest, opts ...grpc.CallOption) (*CreateAssemblyOrderResponse, error)
+	CompleteAssemblyOrder(ctx context.Context, in *CompleteAssemblyOrderRequest, opts ...grpc.CallOption) (*CompleteAssemblyOrderResponse, error)
+	DisassembleProduct(ctx context.Context, in *DisassembleProductRequest, opts ...grpc.CallOption) (*DisassembleProductResponse, error)
+	ListBackorders(ctx context.Context, in *ListBackordersRequest, opts ...grpc.CallOption) (*ListBackordersResponse, error)
+	GeneratePickList(ctx context.Context, in *GeneratePickListRequest, opts ...grpc.CallOption) (*GeneratePickListResponse, error)
 }
 
 type warehouseServiceClient struct {
@@ -57,12 +88,163 @@ func (c *warehouseServiceClient) RemoveProduct(ctx context.Context, in *RemovePr
 	return out, nil
 }
 
+func (c *warehouseServiceClient) AddArticleStock(ctx context.Context, in *AddArticleStockRequest, opts ...grpc.CallOption) (*AddArticleStockResponse, error) {
+	out := new(AddArticleStockResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_AddArticleStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error) {
+	out := new(GetArticleResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_GetArticle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) TraceSerial(ctx context.Context, in *TraceSerialRequest, opts ...grpc.CallOption) (*TraceSerialResponse, error) {
+	out := new(TraceSerialResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_TraceSerial_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) PlanProductionMix(ctx context.Context, in *PlanProductionMixRequest, opts ...grpc.CallOption) (*PlanProductionMixResponse, error) {
+	out := new(PlanProductionMixResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_PlanProductionMix_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) SimulateAvailability(ctx context.Context, in *SimulateAvailabilityRequest, opts ...grpc.CallOption) (*SimulateAvailabilityResponse, error) {
+	out := new(SimulateAvailabilityResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_SimulateAvailability_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) UpdateReorderPolicy(ctx context.Context, in *UpdateReorderPolicyRequest, opts ...grpc.CallOption) (*UpdateReorderPolicyResponse, error) {
+	out := new(UpdateReorderPolicyResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_UpdateReorderPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) UpdateArticle(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*UpdateArticleResponse, error) {
+	out := new(UpdateArticleResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_UpdateArticle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error) {
+	out := new(UpdateProductResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_UpdateProduct_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) ListLowStock(ctx context.Context, in *ListLowStockRequest, opts ...grpc.CallOption) (*ListLowStockResponse, error) {
+	out := new(ListLowStockResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_ListLowStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) SuggestReplenishment(ctx context.Context, in *SuggestReplenishmentRequest, opts ...grpc.CallOption) (*SuggestReplenishmentResponse, error) {
+	out := new(SuggestReplenishmentResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_SuggestReplenishment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) CreateAssemblyOrder(ctx context.Context, in *CreateAssemblyOrderRequest, opts ...grpc.CallOption) (*CreateAssemblyOrderResponse, error) {
+	out := new(CreateAssemblyOrderResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_CreateAssemblyOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) CompleteAssemblyOrder(ctx context.Context, in *CompleteAssemblyOrderRequest, opts ...grpc.CallOption) (*CompleteAssemblyOrderResponse, error) {
+	out := new(CompleteAssemblyOrderResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_CompleteAssemblyOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) DisassembleProduct(ctx context.Context, in *DisassembleProductRequest, opts ...grpc.CallOption) (*DisassembleProductResponse, error) {
+	out := new(DisassembleProductResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_DisassembleProduct_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) ListBackorders(ctx context.Context, in *ListBackordersRequest, opts ...grpc.CallOption) (*ListBackordersResponse, error) {
+	out := new(ListBackordersResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_ListBackorders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *warehouseServiceClient) GeneratePickList(ctx context.Context, in *GeneratePickListRequest, opts ...grpc.CallOption) (*GeneratePickListResponse, error) {
+	out := new(GeneratePickListResponse)
+	err := c.cc.Invoke(ctx, WarehouseService_GeneratePickList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WarehouseServiceServer is the server API for WarehouseService service.
 // All implementations must embed UnimplementedWarehouseServiceServer
 // for forward compatibility
 type WarehouseServiceServer interface {
 	GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error)
 	RemoveProduct(context.Context, *RemoveProductRequest) (*RemoveProductResponse, error)
+	AddArticleStock(context.Context, *AddArticleStockRequest) (*AddArticleStockResponse, error)
+	GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error)
+	TraceSerial(context.Context, *TraceSerialRequest) (*TraceSerialResponse, error)
+	PlanProductionMix(context.Context, *PlanProductionMixRequest) (*PlanProductionMixResponse, error)
+	SimulateAvailability(context.Context, *SimulateAvailabilityRequest) (*SimulateAvailabilityResponse, error)
+	UpdateReorderPolicy(context.Context, *UpdateReorderPolicyRequest) (*UpdateReorderPolicyResponse, error)
+	// Update RPCs require the etag read last and fail with ABORTED if the item has changed since
+	UpdateArticle(context.Context, *UpdateArticleRequest) (*UpdateArticleResponse, error)
+	UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error)
+	ListLowStock(context.Context, *ListLowStockRequest) (*ListLowStockResponse, error)
+	SuggestReplenishment(context.Context, *SuggestReplenishmentRequest) (*SuggestReplenishmentResponse, error)
+	CreateAssemblyOrder(context.Context, *CreateAssemblyOrderRequest) (*CreateAssemblyOrderResponse, error)
+	CompleteAssemblyOrder(context.Context, *CompleteAssemblyOrderRequest) (*CompleteAssemblyOrderResponse, error)
+	DisassembleProduct(context.Context, *DisassembleProductRequest) (*DisassembleProductResponse, error)
+	ListBackorders(context.Context, *ListBackordersRequest) (*ListBackordersResponse, error)
+	GeneratePickList(context.Context, *GeneratePickListRequest) (*GeneratePickListResponse, error)
 	mustEmbedUnimplementedWarehouseServiceServer()
 }
 
@@ -76,6 +258,51 @@ func (UnimplementedWarehouseServiceServer) GetProducts(context.Context, *GetProd
 func (UnimplementedWarehouseServiceServer) RemoveProduct(context.Context, *RemoveProductRequest) (*RemoveProductResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveProduct not implemented")
 }
+func (UnimplementedWarehouseServiceServer) AddArticleStock(context.Context, *AddArticleStockRequest) (*AddArticleStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddArticleStock not implemented")
+}
+func (UnimplementedWarehouseServiceServer) GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticle not implemented")
+}
+func (UnimplementedWarehouseServiceServer) TraceSerial(context.Context, *TraceSerialRequest) (*TraceSerialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceSerial not implemented")
+}
+func (UnimplementedWarehouseServiceServer) PlanProductionMix(context.Context, *PlanProductionMixRequest) (*PlanProductionMixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanProductionMix not implemented")
+}
+func (UnimplementedWarehouseServiceServer) SimulateAvailability(context.Context, *SimulateAvailabilityRequest) (*SimulateAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateAvailability not implemented")
+}
+func (UnimplementedWarehouseServiceServer) UpdateReorderPolicy(context.Context, *UpdateReorderPolicyRequest) (*UpdateReorderPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReorderPolicy not implemented")
+}
+func (UnimplementedWarehouseServiceServer) UpdateArticle(context.Context, *UpdateArticleRequest) (*UpdateArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateArticle not implemented")
+}
+func (UnimplementedWarehouseServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedWarehouseServiceServer) ListLowStock(context.Context, *ListLowStockRequest) (*ListLowStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLowStock not implemented")
+}
+func (UnimplementedWarehouseServiceServer) SuggestReplenishment(context.Context, *SuggestReplenishmentRequest) (*SuggestReplenishmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestReplenishment not implemented")
+}
+func (UnimplementedWarehouseServiceServer) CreateAssemblyOrder(context.Context, *CreateAssemblyOrderRequest) (*CreateAssemblyOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAssemblyOrder not implemented")
+}
+func (UnimplementedWarehouseServiceServer) CompleteAssemblyOrder(context.Context, *CompleteAssemblyOrderRequest) (*CompleteAssemblyOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteAssemblyOrder not implemented")
+}
+func (UnimplementedWarehouseServiceServer) DisassembleProduct(context.Context, *DisassembleProductRequest) (*DisassembleProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisassembleProduct not implemented")
+}
+func (UnimplementedWarehouseServiceServer) ListBackorders(context.Context, *ListBackordersRequest) (*ListBackordersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackorders not implemented")
+}
+func (UnimplementedWarehouseServiceServer) GeneratePickList(context.Context, *GeneratePickListRequest) (*GeneratePickListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePickList not implemented")
+}
 func (UnimplementedWarehouseServiceServer) mustEmbedUnimplementedWarehouseServiceServer() {}
 
 // UnsafeWarehouseServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -125,20 +352,1651 @@ func _WarehouseService_RemoveProduct_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
-// WarehouseService_ServiceDesc is the grpc.ServiceDesc for WarehouseService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var WarehouseService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "warehouse.WarehouseService",
-	HandlerType: (*WarehouseServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetProducts",
-			Handler:    _WarehouseService_GetProducts_Handler,
-		},
-		{
-			MethodName: "RemoveProduct",
-			Handler:    _WarehouseService_RemoveProduct_Handler,
+func _WarehouseService_AddArticleStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddArticleStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).AddArticleStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_AddArticleStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).AddArticleStock(ctx, req.(*AddArticleStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_GetArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).GetArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_GetArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).GetArticle(ctx, req.(*GetArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_TraceSerial_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceSerialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).TraceSerial(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_TraceSerial_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).TraceSerial(ctx, req.(*TraceSerialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_PlanProductionMix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanProductionMixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).PlanProductionMix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_PlanProductionMix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).PlanProductionMix(ctx, req.(*PlanProductionMixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_SimulateAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).SimulateAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_SimulateAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).SimulateAvailability(ctx, req.(*SimulateAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_UpdateReorderPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReorderPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).UpdateReorderPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_UpdateReorderPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).UpdateReorderPolicy(ctx, req.(*UpdateReorderPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_UpdateArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).UpdateArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_UpdateArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).UpdateArticle(ctx, req.(*UpdateArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_UpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_ListLowStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLowStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).ListLowStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_ListLowStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).ListLowStock(ctx, req.(*ListLowStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_SuggestReplenishment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestReplenishmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).SuggestReplenishment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_SuggestReplenishment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).SuggestReplenishment(ctx, req.(*SuggestReplenishmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_CreateAssemblyOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAssemblyOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).CreateAssemblyOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_CreateAssemblyOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).CreateAssemblyOrder(ctx, req.(*CreateAssemblyOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_CompleteAssemblyOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteAssemblyOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).CompleteAssemblyOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_CompleteAssemblyOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).CompleteAssemblyOrder(ctx, req.(*CompleteAssemblyOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_DisassembleProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisassembleProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).DisassembleProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_DisassembleProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).DisassembleProduct(ctx, req.(*DisassembleProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_ListBackorders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackordersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).ListBackorders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_ListBackorders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).ListBackorders(ctx, req.(*ListBackordersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WarehouseService_GeneratePickList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeneratePickListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WarehouseServiceServer).GeneratePickList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WarehouseService_GeneratePickList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WarehouseServiceServer).GeneratePickList(ctx, req.(*GeneratePickListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WarehouseService_ServiceDesc is the grpc.ServiceDesc for WarehouseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WarehouseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.WarehouseService",
+	HandlerType: (*WarehouseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProducts",
+			Handler:    _WarehouseService_GetProducts_Handler,
+		},
+		{
+			MethodName: "RemoveProduct",
+			Handler:    _WarehouseService_RemoveProduct_Handler,
+		},
+		{
+			MethodName: "AddArticleStock",
+			Handler:    _WarehouseService_AddArticleStock_Handler,
+		},
+		{
+			MethodName: "GetArticle",
+			Handler:    _WarehouseService_GetArticle_Handler,
+		},
+		{
+			MethodName: "TraceSerial",
+			Handler:    _WarehouseService_TraceSerial_Handler,
+		},
+		{
+			MethodName: "PlanProductionMix",
+			Handler:    _WarehouseService_PlanProductionMix_Handler,
+		},
+		{
+			MethodName: "SimulateAvailability",
+			Handler:    _WarehouseService_SimulateAvailability_Handler,
+		},
+		{
+			MethodName: "UpdateReorderPolicy",
+			Handler:    _WarehouseService_UpdateReorderPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateArticle",
+			Handler:    _WarehouseService_UpdateArticle_Handler,
+		},
+		{
+			MethodName: "UpdateProduct",
+			Handler:    _WarehouseService_UpdateProduct_Handler,
+		},
+		{
+			MethodName: "ListLowStock",
+			Handler:    _WarehouseService_ListLowStock_Handler,
+		},
+		{
+			MethodName: "SuggestReplenishment",
+			Handler:    _WarehouseService_SuggestReplenishment_Handler,
+		},
+		{
+			MethodName: "CreateAssemblyOrder",
+			Handler:    _WarehouseService_CreateAssemblyOrder_Handler,
+		},
+		{
+			MethodName: "CompleteAssemblyOrder",
+			Handler:    _WarehouseService_CompleteAssemblyOrder_Handler,
+		},
+		{
+			MethodName: "DisassembleProduct",
+			Handler:    _WarehouseService_DisassembleProduct_Handler,
+		},
+		{
+			MethodName: "ListBackorders",
+			Handler:    _WarehouseService_ListBackorders_Handler,
+		},
+		{
+			MethodName: "GeneratePickList",
+			Handler:    _WarehouseService_GeneratePickList_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/warehouse.proto",
+}
+
+const (
+	SupplierService_CreateSupplier_FullMethodName       = "/warehouse.SupplierService/CreateSupplier"
+	SupplierService_ListSuppliers_FullMethodName        = "/warehouse.SupplierService/ListSuppliers"
+	SupplierService_SetSupplierArticle_FullMethodName   = "/warehouse.SupplierService/SetSupplierArticle"
+	SupplierService_ListSupplierArticles_FullMethodName = "/warehouse.SupplierService/ListSupplierArticles"
+)
+
+// SupplierServiceClient is the client API for SupplierService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SupplierServiceClient interface {
+	CreateSupplier(ctx context.Context, in *CreateSupplierRequest, opts ...grpc.CallOption) (*CreateSupplierResponse, error)
+	ListSuppliers(ctx context.Context, in *ListSuppliersRequest, opts ...grpc.CallOption) (*ListSuppliersResponse, error)
+	SetSupplierArticle(ctx context.Context, in *SetSupplierArticleRequest, opts ...grpc.CallOption) (*SetSupplierArticleResponse, error)
+	ListSupplierArticles(ctx context.Context, in *ListSupplierArticlesRequest, opts ...grpc.CallOption) (*ListSupplierArticlesResponse, error)
+}
+
+type supplierServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSupplierServiceClient(cc grpc.ClientConnInterface) SupplierServiceClient {
+	return &supplierServiceClient{cc}
+}
+
+func (c *supplierServiceClient) CreateSupplier(ctx context.Context, in *CreateSupplierRequest, opts ...grpc.CallOption) (*CreateSupplierResponse, error) {
+	out := new(CreateSupplierResponse)
+	err := c.cc.Invoke(ctx, SupplierService_CreateSupplier_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supplierServiceClient) ListSuppliers(ctx context.Context, in *ListSuppliersRequest, opts ...grpc.CallOption) (*ListSuppliersResponse, error) {
+	out := new(ListSuppliersResponse)
+	err := c.cc.Invoke(ctx, SupplierService_ListSuppliers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supplierServiceClient) SetSupplierArticle(ctx context.Context, in *SetSupplierArticleRequest, opts ...grpc.CallOption) (*SetSupplierArticleResponse, error) {
+	out := new(SetSupplierArticleResponse)
+	err := c.cc.Invoke(ctx, SupplierService_SetSupplierArticle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *supplierServiceClient) ListSupplierArticles(ctx context.Context, in *ListSupplierArticlesRequest, opts ...grpc.CallOption) (*ListSupplierArticlesResponse, error) {
+	out := new(ListSupplierArticlesResponse)
+	err := c.cc.Invoke(ctx, SupplierService_ListSupplierArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SupplierServiceServer is the server API for SupplierService service.
+// All implementations must embed UnimplementedSupplierServiceServer
+// for forward compatibility
+type SupplierServiceServer interface {
+	CreateSupplier(context.Context, *CreateSupplierRequest) (*CreateSupplierResponse, error)
+	ListSuppliers(context.Context, *ListSuppliersRequest) (*ListSuppliersResponse, error)
+	SetSupplierArticle(context.Context, *SetSupplierArticleRequest) (*SetSupplierArticleResponse, error)
+	ListSupplierArticles(context.Context, *ListSupplierArticlesRequest) (*ListSupplierArticlesResponse, error)
+	mustEmbedUnimplementedSupplierServiceServer()
+}
+
+// UnimplementedSupplierServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSupplierServiceServer struct {
+}
+
+func (UnimplementedSupplierServiceServer) CreateSupplier(context.Context, *CreateSupplierRequest) (*CreateSupplierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSupplier not implemented")
+}
+func (UnimplementedSupplierServiceServer) ListSuppliers(context.Context, *ListSuppliersRequest) (*ListSuppliersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuppliers not implemented")
+}
+func (UnimplementedSupplierServiceServer) SetSupplierArticle(context.Context, *SetSupplierArticleRequest) (*SetSupplierArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSupplierArticle not implemented")
+}
+func (UnimplementedSupplierServiceServer) ListSupplierArticles(context.Context, *ListSupplierArticlesRequest) (*ListSupplierArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSupplierArticles not implemented")
+}
+func (UnimplementedSupplierServiceServer) mustEmbedUnimplementedSupplierServiceServer() {}
+
+// UnsafeSupplierServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SupplierServiceServer will
+// result in compilation errors.
+type UnsafeSupplierServiceServer interface {
+	mustEmbedUnimplementedSupplierServiceServer()
+}
+
+func RegisterSupplierServiceServer(s grpc.ServiceRegistrar, srv SupplierServiceServer) {
+	s.RegisterService(&SupplierService_ServiceDesc, srv)
+}
+
+func _SupplierService_CreateSupplier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSupplierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupplierServiceServer).CreateSupplier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SupplierService_CreateSupplier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupplierServiceServer).CreateSupplier(ctx, req.(*CreateSupplierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SupplierService_ListSuppliers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuppliersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupplierServiceServer).ListSuppliers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SupplierService_ListSuppliers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupplierServiceServer).ListSuppliers(ctx, req.(*ListSuppliersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SupplierService_SetSupplierArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSupplierArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupplierServiceServer).SetSupplierArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SupplierService_SetSupplierArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupplierServiceServer).SetSupplierArticle(ctx, req.(*SetSupplierArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SupplierService_ListSupplierArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSupplierArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SupplierServiceServer).ListSupplierArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SupplierService_ListSupplierArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SupplierServiceServer).ListSupplierArticles(ctx, req.(*ListSupplierArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SupplierService_ServiceDesc is the grpc.ServiceDesc for SupplierService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SupplierService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.SupplierService",
+	HandlerType: (*SupplierServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSupplier",
+			Handler:    _SupplierService_CreateSupplier_Handler,
+		},
+		{
+			MethodName: "ListSuppliers",
+			Handler:    _SupplierService_ListSuppliers_Handler,
+		},
+		{
+			MethodName: "SetSupplierArticle",
+			Handler:    _SupplierService_SetSupplierArticle_Handler,
+		},
+		{
+			MethodName: "ListSupplierArticles",
+			Handler:    _SupplierService_ListSupplierArticles_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/warehouse.proto",
+}
+
+const (
+	PurchaseOrderService_CreatePurchaseOrder_FullMethodName  = "/warehouse.PurchaseOrderService/CreatePurchaseOrder"
+	PurchaseOrderService_GetPurchaseOrder_FullMethodName     = "/warehouse.PurchaseOrderService/GetPurchaseOrder"
+	PurchaseOrderService_ListPurchaseOrders_FullMethodName   = "/warehouse.PurchaseOrderService/ListPurchaseOrders"
+	PurchaseOrderService_SendPurchaseOrder_FullMethodName    = "/warehouse.PurchaseOrderService/SendPurchaseOrder"
+	PurchaseOrderService_ReceivePurchaseOrder_FullMethodName = "/warehouse.PurchaseOrderService/ReceivePurchaseOrder"
+	PurchaseOrderService_ClosePurchaseOrder_FullMethodName   = "/warehouse.PurchaseOrderService/ClosePurchaseOrder"
+)
+
+// PurchaseOrderServiceClient is the client API for PurchaseOrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PurchaseOrderServiceClient interface {
+	CreatePurchaseOrder(ctx context.Context, in *CreatePurchaseOrderRequest, opts ...grpc.CallOption) (*CreatePurchaseOrderResponse, error)
+	GetPurchaseOrder(ctx context.Context, in *GetPurchaseOrderRequest, opts ...grpc.CallOption) (*GetPurchaseOrderResponse, error)
+	ListPurchaseOrders(ctx context.Context, in *ListPurchaseOrdersRequest, opts ...grpc.CallOption) (*ListPurchaseOrdersResponse, error)
+	SendPurchaseOrder(ctx context.Context, in *SendPurchaseOrderRequest, opts ...grpc.CallOption) (*SendPurchaseOrderResponse, error)
+	ReceivePurchaseOrder(ctx context.Context, in *ReceivePurchaseOrderRequest, opts ...grpc.CallOption) (*ReceivePurchaseOrderResponse, error)
+	ClosePurchaseOrder(ctx context.Context, in *ClosePurchaseOrderRequest, opts ...grpc.CallOption) (*ClosePurchaseOrderResponse, error)
+}
+
+type purchaseOrderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPurchaseOrderServiceClient(cc grpc.ClientConnInterface) PurchaseOrderServiceClient {
+	return &purchaseOrderServiceClient{cc}
+}
+
+func (c *purchaseOrderServiceClient) CreatePurchaseOrder(ctx context.Context, in *CreatePurchaseOrderRequest, opts ...grpc.CallOption) (*CreatePurchaseOrderResponse, error) {
+	out := new(CreatePurchaseOrderResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_CreatePurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) GetPurchaseOrder(ctx context.Context, in *GetPurchaseOrderRequest, opts ...grpc.CallOption) (*GetPurchaseOrderResponse, error) {
+	out := new(GetPurchaseOrderResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_GetPurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) ListPurchaseOrders(ctx context.Context, in *ListPurchaseOrdersRequest, opts ...grpc.CallOption) (*ListPurchaseOrdersResponse, error) {
+	out := new(ListPurchaseOrdersResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_ListPurchaseOrders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) SendPurchaseOrder(ctx context.Context, in *SendPurchaseOrderRequest, opts ...grpc.CallOption) (*SendPurchaseOrderResponse, error) {
+	out := new(SendPurchaseOrderResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_SendPurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) ReceivePurchaseOrder(ctx context.Context, in *ReceivePurchaseOrderRequest, opts ...grpc.CallOption) (*ReceivePurchaseOrderResponse, error) {
+	out := new(ReceivePurchaseOrderResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_ReceivePurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purchaseOrderServiceClient) ClosePurchaseOrder(ctx context.Context, in *ClosePurchaseOrderRequest, opts ...grpc.CallOption) (*ClosePurchaseOrderResponse, error) {
+	out := new(ClosePurchaseOrderResponse)
+	err := c.cc.Invoke(ctx, PurchaseOrderService_ClosePurchaseOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PurchaseOrderServiceServer is the server API for PurchaseOrderService service.
+// All implementations must embed UnimplementedPurchaseOrderServiceServer
+// for forward compatibility
+type PurchaseOrderServiceServer interface {
+	CreatePurchaseOrder(context.Context, *CreatePurchaseOrderRequest) (*CreatePurchaseOrderResponse, error)
+	GetPurchaseOrder(context.Context, *GetPurchaseOrderRequest) (*GetPurchaseOrderResponse, error)
+	ListPurchaseOrders(context.Context, *ListPurchaseOrdersRequest) (*ListPurchaseOrdersResponse, error)
+	SendPurchaseOrder(context.Context, *SendPurchaseOrderRequest) (*SendPurchaseOrderResponse, error)
+	ReceivePurchaseOrder(context.Context, *ReceivePurchaseOrderRequest) (*ReceivePurchaseOrderResponse, error)
+	ClosePurchaseOrder(context.Context, *ClosePurchaseOrderRequest) (*ClosePurchaseOrderResponse, error)
+	mustEmbedUnimplementedPurchaseOrderServiceServer()
+}
+
+// UnimplementedPurchaseOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPurchaseOrderServiceServer struct {
+}
+
+func (UnimplementedPurchaseOrderServiceServer) CreatePurchaseOrder(context.Context, *CreatePurchaseOrderRequest) (*CreatePurchaseOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) GetPurchaseOrder(context.Context, *GetPurchaseOrderRequest) (*GetPurchaseOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) ListPurchaseOrders(context.Context, *ListPurchaseOrdersRequest) (*ListPurchaseOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPurchaseOrders not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) SendPurchaseOrder(context.Context, *SendPurchaseOrderRequest) (*SendPurchaseOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) ReceivePurchaseOrder(context.Context, *ReceivePurchaseOrderRequest) (*ReceivePurchaseOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceivePurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) ClosePurchaseOrder(context.Context, *ClosePurchaseOrderRequest) (*ClosePurchaseOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosePurchaseOrder not implemented")
+}
+func (UnimplementedPurchaseOrderServiceServer) mustEmbedUnimplementedPurchaseOrderServiceServer() {}
+
+// UnsafePurchaseOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PurchaseOrderServiceServer will
+// result in compilation errors.
+type UnsafePurchaseOrderServiceServer interface {
+	mustEmbedUnimplementedPurchaseOrderServiceServer()
+}
+
+func RegisterPurchaseOrderServiceServer(s grpc.ServiceRegistrar, srv PurchaseOrderServiceServer) {
+	s.RegisterService(&PurchaseOrderService_ServiceDesc, srv)
+}
+
+func _PurchaseOrderService_CreatePurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).CreatePurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_CreatePurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).CreatePurchaseOrder(ctx, req.(*CreatePurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_GetPurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).GetPurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_GetPurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).GetPurchaseOrder(ctx, req.(*GetPurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_ListPurchaseOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPurchaseOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).ListPurchaseOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_ListPurchaseOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).ListPurchaseOrders(ctx, req.(*ListPurchaseOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_SendPurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).SendPurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_SendPurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).SendPurchaseOrder(ctx, req.(*SendPurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_ReceivePurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceivePurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).ReceivePurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_ReceivePurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).ReceivePurchaseOrder(ctx, req.(*ReceivePurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurchaseOrderService_ClosePurchaseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosePurchaseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurchaseOrderServiceServer).ClosePurchaseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PurchaseOrderService_ClosePurchaseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurchaseOrderServiceServer).ClosePurchaseOrder(ctx, req.(*ClosePurchaseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PurchaseOrderService_ServiceDesc is the grpc.ServiceDesc for PurchaseOrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PurchaseOrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.PurchaseOrderService",
+	HandlerType: (*PurchaseOrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePurchaseOrder",
+			Handler:    _PurchaseOrderService_CreatePurchaseOrder_Handler,
+		},
+		{
+			MethodName: "GetPurchaseOrder",
+			Handler:    _PurchaseOrderService_GetPurchaseOrder_Handler,
+		},
+		{
+			MethodName: "ListPurchaseOrders",
+			Handler:    _PurchaseOrderService_ListPurchaseOrders_Handler,
+		},
+		{
+			MethodName: "SendPurchaseOrder",
+			Handler:    _PurchaseOrderService_SendPurchaseOrder_Handler,
+		},
+		{
+			MethodName: "ReceivePurchaseOrder",
+			Handler:    _PurchaseOrderService_ReceivePurchaseOrder_Handler,
+		},
+		{
+			MethodName: "ClosePurchaseOrder",
+			Handler:    _PurchaseOrderService_ClosePurchaseOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/warehouse.proto",
+}
+
+const (
+	OrderService_PlaceOrder_FullMethodName    = "/warehouse.OrderService/PlaceOrder"
+	OrderService_GetOrder_FullMethodName      = "/warehouse.OrderService/GetOrder"
+	OrderService_ListOrders_FullMethodName    = "/warehouse.OrderService/ListOrders"
+	OrderService_AllocateOrder_FullMethodName = "/warehouse.OrderService/AllocateOrder"
+	OrderService_PickOrder_FullMethodName     = "/warehouse.OrderService/PickOrder"
+	OrderService_PackOrder_FullMethodName     = "/warehouse.OrderService/PackOrder"
+	OrderService_ShipOrder_FullMethodName     = "/warehouse.OrderService/ShipOrder"
+	OrderService_CancelOrder_FullMethodName   = "/warehouse.OrderService/CancelOrder"
+)
+
+// OrderServiceClient is the client API for OrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderServiceClient interface {
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	AllocateOrder(ctx context.Context, in *AllocateOrderRequest, opts ...grpc.CallOption) (*AllocateOrderResponse, error)
+	PickOrder(ctx context.Context, in *PickOrderRequest, opts ...grpc.CallOption) (*PickOrderResponse, error)
+	PackOrder(ctx context.Context, in *PackOrderRequest, opts ...grpc.CallOption) (*PackOrderResponse, error)
+	ShipOrder(ctx context.Context, in *ShipOrderRequest, opts ...grpc.CallOption) (*ShipOrderResponse, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error) {
+	out := new(PlaceOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_PlaceOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error) {
+	out := new(GetOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListOrders_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) AllocateOrder(ctx context.Context, in *AllocateOrderRequest, opts ...grpc.CallOption) (*AllocateOrderResponse, error) {
+	out := new(AllocateOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_AllocateOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) PickOrder(ctx context.Context, in *PickOrderRequest, opts ...grpc.CallOption) (*PickOrderResponse, error) {
+	out := new(PickOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_PickOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) PackOrder(ctx context.Context, in *PackOrderRequest, opts ...grpc.CallOption) (*PackOrderResponse, error) {
+	out := new(PackOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_PackOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ShipOrder(ctx context.Context, in *ShipOrderRequest, opts ...grpc.CallOption) (*ShipOrderResponse, error) {
+	out := new(ShipOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_ShipOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_CancelOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility
+type OrderServiceServer interface {
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	AllocateOrder(context.Context, *AllocateOrderRequest) (*AllocateOrderResponse, error)
+	PickOrder(context.Context, *PickOrderRequest) (*PickOrderResponse, error)
+	PackOrder(context.Context, *PackOrderRequest) (*PackOrderResponse, error)
+	ShipOrder(context.Context, *ShipOrderRequest) (*ShipOrderResponse, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOrderServiceServer struct {
+}
+
+func (UnimplementedOrderServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) AllocateOrder(context.Context, *AllocateOrderRequest) (*AllocateOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) PickOrder(context.Context, *PickOrderRequest) (*PickOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PickOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) PackOrder(context.Context, *PackOrderRequest) (*PackOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PackOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ShipOrder(context.Context, *ShipOrderRequest) (*ShipOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShipOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_AllocateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AllocateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AllocateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AllocateOrder(ctx, req.(*AllocateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_PickOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PickOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PickOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PickOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PickOrder(ctx, req.(*PickOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_PackOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PackOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PackOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PackOrder(ctx, req.(*PackOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ShipOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShipOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ShipOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ShipOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ShipOrder(ctx, req.(*ShipOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.OrderService",
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _OrderService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OrderService_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _OrderService_ListOrders_Handler,
+		},
+		{
+			MethodName: "AllocateOrder",
+			Handler:    _OrderService_AllocateOrder_Handler,
+		},
+		{
+			MethodName: "PickOrder",
+			Handler:    _OrderService_PickOrder_Handler,
+		},
+		{
+			MethodName: "PackOrder",
+			Handler:    _OrderService_PackOrder_Handler,
+		},
+		{
+			MethodName: "ShipOrder",
+			Handler:    _OrderService_ShipOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrderService_CancelOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/warehouse.proto",
+}
+
+const (
+	StocktakeService_OpenStocktake_FullMethodName    = "/warehouse.StocktakeService/OpenStocktake"
+	StocktakeService_GetStocktake_FullMethodName     = "/warehouse.StocktakeService/GetStocktake"
+	StocktakeService_SubmitCounts_FullMethodName     = "/warehouse.StocktakeService/SubmitCounts"
+	StocktakeService_ApproveStocktake_FullMethodName = "/warehouse.StocktakeService/ApproveStocktake"
+	StocktakeService_CancelStocktake_FullMethodName  = "/warehouse.StocktakeService/CancelStocktake"
+)
+
+// StocktakeServiceClient is the client API for StocktakeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StocktakeServiceClient interface {
+	OpenStocktake(ctx context.Context, in *OpenStocktakeRequest, opts ...grpc.CallOption) (*OpenStocktakeResponse, error)
+	GetStocktake(ctx context.Context, in *GetStocktakeRequest, opts ...grpc.CallOption) (*GetStocktakeResponse, error)
+	SubmitCounts(ctx context.Context, in *SubmitCountsRequest, opts ...grpc.CallOption) (*SubmitCountsResponse, error)
+	ApproveStocktake(ctx context.Context, in *ApproveStocktakeRequest, opts ...grpc.CallOption) (*ApproveStocktakeResponse, error)
+	CancelStocktake(ctx context.Context, in *CancelStocktakeRequest, opts ...grpc.CallOption) (*CancelStocktakeResponse, error)
+}
+
+type stocktakeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStocktakeServiceClient(cc grpc.ClientConnInterface) StocktakeServiceClient {
+	return &stocktakeServiceClient{cc}
+}
+
+func (c *stocktakeServiceClient) OpenStocktake(ctx context.Context, in *OpenStocktakeRequest, opts ...grpc.CallOption) (*OpenStocktakeResponse, error) {
+	out := new(OpenStocktakeResponse)
+	err := c.cc.Invoke(ctx, StocktakeService_OpenStocktake_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stocktakeServiceClient) GetStocktake(ctx context.Context, in *GetStocktakeRequest, opts ...grpc.CallOption) (*GetStocktakeResponse, error) {
+	out := new(GetStocktakeResponse)
+	err := c.cc.Invoke(ctx, StocktakeService_GetStocktake_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stocktakeServiceClient) SubmitCounts(ctx context.Context, in *SubmitCountsRequest, opts ...grpc.CallOption) (*SubmitCountsResponse, error) {
+	out := new(SubmitCountsResponse)
+	err := c.cc.Invoke(ctx, StocktakeService_SubmitCounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stocktakeServiceClient) ApproveStocktake(ctx context.Context, in *ApproveStocktakeRequest, opts ...grpc.CallOption) (*ApproveStocktakeResponse, error) {
+	out := new(ApproveStocktakeResponse)
+	err := c.cc.Invoke(ctx, StocktakeService_ApproveStocktake_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stocktakeServiceClient) CancelStocktake(ctx context.Context, in *CancelStocktakeRequest, opts ...grpc.CallOption) (*CancelStocktakeResponse, error) {
+	out := new(CancelStocktakeResponse)
+	err := c.cc.Invoke(ctx, StocktakeService_CancelStocktake_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StocktakeServiceServer is the server API for StocktakeService service.
+// All implementations must embed UnimplementedStocktakeServiceServer
+// for forward compatibility
+type StocktakeServiceServer interface {
+	OpenStocktake(context.Context, *OpenStocktakeRequest) (*OpenStocktakeResponse, error)
+	GetStocktake(context.Context, *GetStocktakeRequest) (*GetStocktakeResponse, error)
+	SubmitCounts(context.Context, *SubmitCountsRequest) (*SubmitCountsResponse, error)
+	ApproveStocktake(context.Context, *ApproveStocktakeRequest) (*ApproveStocktakeResponse, error)
+	CancelStocktake(context.Context, *CancelStocktakeRequest) (*CancelStocktakeResponse, error)
+	mustEmbedUnimplementedStocktakeServiceServer()
+}
+
+// UnimplementedStocktakeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStocktakeServiceServer struct {
+}
+
+func (UnimplementedStocktakeServiceServer) OpenStocktake(context.Context, *OpenStocktakeRequest) (*OpenStocktakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenStocktake not implemented")
+}
+func (UnimplementedStocktakeServiceServer) GetStocktake(context.Context, *GetStocktakeRequest) (*GetStocktakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStocktake not implemented")
+}
+func (UnimplementedStocktakeServiceServer) SubmitCounts(context.Context, *SubmitCountsRequest) (*SubmitCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitCounts not implemented")
+}
+func (UnimplementedStocktakeServiceServer) ApproveStocktake(context.Context, *ApproveStocktakeRequest) (*ApproveStocktakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveStocktake not implemented")
+}
+func (UnimplementedStocktakeServiceServer) CancelStocktake(context.Context, *CancelStocktakeRequest) (*CancelStocktakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelStocktake not implemented")
+}
+func (UnimplementedStocktakeServiceServer) mustEmbedUnimplementedStocktakeServiceServer() {}
+
+// UnsafeStocktakeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StocktakeServiceServer will
+// result in compilation errors.
+type UnsafeStocktakeServiceServer interface {
+	mustEmbedUnimplementedStocktakeServiceServer()
+}
+
+func RegisterStocktakeServiceServer(s grpc.ServiceRegistrar, srv StocktakeServiceServer) {
+	s.RegisterService(&StocktakeService_ServiceDesc, srv)
+}
+
+func _StocktakeService_OpenStocktake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenStocktakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StocktakeServiceServer).OpenStocktake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StocktakeService_OpenStocktake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StocktakeServiceServer).OpenStocktake(ctx, req.(*OpenStocktakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StocktakeService_GetStocktake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStocktakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StocktakeServiceServer).GetStocktake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StocktakeService_GetStocktake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StocktakeServiceServer).GetStocktake(ctx, req.(*GetStocktakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StocktakeService_SubmitCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StocktakeServiceServer).SubmitCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StocktakeService_SubmitCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StocktakeServiceServer).SubmitCounts(ctx, req.(*SubmitCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StocktakeService_ApproveStocktake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveStocktakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StocktakeServiceServer).ApproveStocktake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StocktakeService_ApproveStocktake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StocktakeServiceServer).ApproveStocktake(ctx, req.(*ApproveStocktakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StocktakeService_CancelStocktake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelStocktakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StocktakeServiceServer).CancelStocktake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StocktakeService_CancelStocktake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StocktakeServiceServer).CancelStocktake(ctx, req.(*CancelStocktakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StocktakeService_ServiceDesc is the grpc.ServiceDesc for StocktakeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StocktakeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.StocktakeService",
+	HandlerType: (*StocktakeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "OpenStocktake",
+			Handler:    _StocktakeService_OpenStocktake_Handler,
+		},
+		{
+			MethodName: "GetStocktake",
+			Handler:    _StocktakeService_GetStocktake_Handler,
+		},
+		{
+			MethodName: "SubmitCounts",
+			Handler:    _StocktakeService_SubmitCounts_Handler,
+		},
+		{
+			MethodName: "ApproveStocktake",
+			Handler:    _StocktakeService_ApproveStocktake_Handler,
+		},
+		{
+			MethodName: "CancelStocktake",
+			Handler:    _StocktakeService_CancelStocktake_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/warehouse.proto",
+}
+
+const (
+	WebhookService_CreateWebhookSubscription_FullMethodName = "/warehouse.WebhookService/CreateWebhookSubscription"
+	WebhookService_ListWebhookSubscriptions_FullMethodName  = "/warehouse.WebhookService/ListWebhookSubscriptions"
+	WebhookService_DeleteWebhookSubscription_FullMethodName = "/warehouse.WebhookService/DeleteWebhookSubscription"
+	WebhookService_ListWebhookDeadLetters_FullMethodName    = "/warehouse.WebhookService/ListWebhookDeadLetters"
+	WebhookService_ReplayWebhookDeliveries_FullMethodName   = "/warehouse.WebhookService/ReplayWebhookDeliveries"
+)
+
+// WebhookServiceClient is the client API for WebhookService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WebhookServiceClient interface {
+	CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CreateWebhookSubscriptionResponse, error)
+	ListWebhookSubscriptions(ctx context.Context, in *ListWebhookSubscriptionsRequest, opts ...grpc.CallOption) (*ListWebhookSubscriptionsResponse, error)
+	DeleteWebhookSubscription(ctx context.Context, in *DeleteWebhookSubscriptionRequest, opts ...grpc.CallOption) (*DeleteWebhookSubscriptionResponse, error)
+	ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error)
+	ReplayWebhookDeliveries(ctx context.Context, in *ReplayWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveriesResponse, error)
+}
+
+type webhookServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWebhookServiceClient(cc grpc.ClientConnInterface) WebhookServiceClient {
+	return &webhookServiceClient{cc}
+}
+
+func (c *webhookServiceClient) CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CreateWebhookSubscriptionResponse, error) {
+	out := new(CreateWebhookSubscriptionResponse)
+	err := c.cc.Invoke(ctx, WebhookService_CreateWebhookSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListWebhookSubscriptions(ctx context.Context, in *ListWebhookSubscriptionsRequest, opts ...grpc.CallOption) (*ListWebhookSubscriptionsResponse, error) {
+	out := new(ListWebhookSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListWebhookSubscriptions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) DeleteWebhookSubscription(ctx context.Context, in *DeleteWebhookSubscriptionRequest, opts ...grpc.CallOption) (*DeleteWebhookSubscriptionResponse, error) {
+	out := new(DeleteWebhookSubscriptionResponse)
+	err := c.cc.Invoke(ctx, WebhookService_DeleteWebhookSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error) {
+	out := new(ListWebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListWebhookDeadLetters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ReplayWebhookDeliveries(ctx context.Context, in *ReplayWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveriesResponse, error) {
+	out := new(ReplayWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ReplayWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WebhookServiceServer is the server API for WebhookService service.
+// All implementations must embed UnimplementedWebhookServiceServer
+// for forward compatibility
+type WebhookServiceServer interface {
+	CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CreateWebhookSubscriptionResponse, error)
+	ListWebhookSubscriptions(context.Context, *ListWebhookSubscriptionsRequest) (*ListWebhookSubscriptionsResponse, error)
+	DeleteWebhookSubscription(context.Context, *DeleteWebhookSubscriptionRequest) (*DeleteWebhookSubscriptionResponse, error)
+	ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error)
+	ReplayWebhookDeliveries(context.Context, *ReplayWebhookDeliveriesRequest) (*ReplayWebhookDeliveriesResponse, error)
+	mustEmbedUnimplementedWebhookServiceServer()
+}
+
+// UnimplementedWebhookServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWebhookServiceServer struct {
+}
+
+func (UnimplementedWebhookServiceServer) CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CreateWebhookSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhookSubscription not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListWebhookSubscriptions(context.Context, *ListWebhookSubscriptionsRequest) (*ListWebhookSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookSubscriptions not implemented")
+}
+func (UnimplementedWebhookServiceServer) DeleteWebhookSubscription(context.Context, *DeleteWebhookSubscriptionRequest) (*DeleteWebhookSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhookSubscription not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeadLetters not implemented")
+}
+func (UnimplementedWebhookServiceServer) ReplayWebhookDeliveries(context.Context, *ReplayWebhookDeliveriesRequest) (*ReplayWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhookDeliveries not implemented")
+}
+func (UnimplementedWebhookServiceServer) mustEmbedUnimplementedWebhookServiceServer() {}
+
+// UnsafeWebhookServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WebhookServiceServer will
+// result in compilation errors.
+type UnsafeWebhookServiceServer interface {
+	mustEmbedUnimplementedWebhookServiceServer()
+}
+
+func RegisterWebhookServiceServer(s grpc.ServiceRegistrar, srv WebhookServiceServer) {
+	s.RegisterService(&WebhookService_ServiceDesc, srv)
+}
+
+func _WebhookService_CreateWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).CreateWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_CreateWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).CreateWebhookSubscription(ctx, req.(*CreateWebhookSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListWebhookSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListWebhookSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListWebhookSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListWebhookSubscriptions(ctx, req.(*ListWebhookSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_DeleteWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).DeleteWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_DeleteWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).DeleteWebhookSubscription(ctx, req.(*DeleteWebhookSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListWebhookDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListWebhookDeadLetters(ctx, req.(*ListWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ReplayWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ReplayWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ReplayWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ReplayWebhookDeliveries(ctx, req.(*ReplayWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WebhookService_ServiceDesc is the grpc.ServiceDesc for WebhookService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WebhookService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "warehouse.WebhookService",
+	HandlerType: (*WebhookServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWebhookSubscription",
+			Handler:    _WebhookService_CreateWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "ListWebhookSubscriptions",
+			Handler:    _WebhookService_ListWebhookSubscriptions_Handler,
+		},
+		{
+			MethodName: "DeleteWebhookSubscription",
+			Handler:    _WebhookService_DeleteWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeadLetters",
+			Handler:    _WebhookService_ListWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayWebhookDeliveries",
+			Handler:    _WebhookService_ReplayWebhookDeliveries_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
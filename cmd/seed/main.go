@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
 	"strconv"
@@ -12,6 +13,13 @@ import (
 
 	"warehouse/internal/config"
 	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/uom"
+)
+
+const (
+	defaultUnit     = "pcs"
+	defaultCurrency = "EUR"
 )
 
 func main() {
@@ -28,11 +36,11 @@ func main() {
 				return err
 			}
 
-			err = SeedProducts(appCtx, db, cfg.DataDir)
+			err = SeedArticles(appCtx, db, cfg.DataDir)
 			if err != nil {
 				return err
 			}
-			err = SeedArticles(appCtx, db, cfg.DataDir)
+			err = SeedProducts(appCtx, db, cfg.DataDir)
 			if err != nil {
 				return err
 			}
@@ -71,6 +79,8 @@ func NewDatabase(lc fx.Lifecycle, appCtx context.Context, appCfg config.Config)
 	return conn, nil
 }
 
+// SeedProducts loads products, article quantities may be expressed in any unit compatible with the article.
+// Fractional quantities are converted to the article unit.
 func SeedProducts(ctx context.Context, db *pgx.Conn, datadir string) error {
 	f, err := os.Open(path.Join(datadir, "products.json"))
 	if err != nil {
@@ -85,7 +95,8 @@ func SeedProducts(ctx context.Context, db *pgx.Conn, datadir string) error {
 				ArtId    string `json:"art_id"`
 				AmountOf string `json:"amount_of"`
 			} `json:"contain_articles"`
-			Price int `json:"price"`
+			Price    json.Number `json:"price"`
+			Currency string      `json:"currency"`
 		} `json:"products"`
 	}
 	err = json.NewDecoder(f).Decode(&content)
@@ -93,34 +104,78 @@ func SeedProducts(ctx context.Context, db *pgx.Conn, datadir string) error {
 		return err
 	}
 
+	conv, err := loadConverter(ctx, db)
+	if err != nil {
+		return err
+	}
+	inventory, err := loadArticles(ctx, db)
+	if err != nil {
+		return err
+	}
+
 	table := "products"
-	columns := []string{"name", "price", "articles"}
+	columns := []string{"name", "price_minor", "currency", "articles"}
 	var rows [][]any
 	for _, item := range content.Products {
-		var articles []any
+		var articles []models.ProductArticle
 		for _, a := range item.ContainArticles {
 			id, err := strconv.Atoi(a.ArtId)
 			if err != nil {
 				return err
 			}
-			quantity, err := strconv.Atoi(a.AmountOf)
+			quantity, err := uom.ParseQuantity(a.AmountOf)
 			if err != nil {
 				return err
 			}
-			articles = append(articles, struct {
-				ID       int32
-				Quantity int32
-			}{
-				ID:       int32(id),
-				Quantity: int32(quantity),
-			})
+			article, err := toProductArticle(conv, inventory, int32(id), quantity)
+			if err != nil {
+				return fmt.Errorf("product %q: %w", item.Name, err)
+			}
+			articles = append(articles, article)
+		}
+		if item.Price == "" {
+			item.Price = "0"
 		}
-		rows = append(rows, []any{item.Name, item.Price, articles})
+		if item.Currency == "" {
+			item.Currency = defaultCurrency
+		}
+		price, err := models.ParseMoney(item.Price.String(), item.Currency)
+		if err != nil {
+			return fmt.Errorf("product %q: %w", item.Name, err)
+		}
+		rows = append(rows, []any{item.Name, price.Amount, price.Currency, articles})
 	}
 	_, err = db.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
 	return err
 }
 
+// toProductArticle normalizes the quantity to the article unit, an unknown article is assumed to be stocked
+// in the base unit of the quantity
+func toProductArticle(conv *uom.Converter, inventory map[int32]models.Article, id int32, quantity uom.Quantity) (models.ProductArticle, error) {
+	article, ok := inventory[id]
+	if !ok {
+		article = models.Article{ID: id}
+		if quantity.Unit != "" {
+			base, _, err := conv.Base(quantity.Unit)
+			if err != nil {
+				return models.ProductArticle{}, fmt.Errorf("unknown article %d: %w", id, err)
+			}
+			article.Unit = base
+		}
+	}
+
+	base, err := conv.ToBase(article, quantity)
+	if err != nil {
+		return models.ProductArticle{}, err
+	}
+	return models.ProductArticle{
+		ID:       id,
+		Quantity: base,
+	}, nil
+}
+
+// SeedArticles loads articles with their packaging units, stock may be expressed in any compatible unit.
+// Articles are stocked in the base unit of the declared unit, e.g. mm for an article declared in m.
 func SeedArticles(ctx context.Context, db *pgx.Conn, datadir string) error {
 	f, err := os.Open(path.Join(datadir, "inventory.json"))
 	if err != nil {
@@ -130,9 +185,18 @@ func SeedArticles(ctx context.Context, db *pgx.Conn, datadir string) error {
 
 	var content struct {
 		Articles []struct {
-			ArtId string `json:"art_id"`
-			Name  string `json:"name"`
-			Stock string `json:"stock"`
+			ArtId      string `json:"art_id"`
+			Name       string `json:"name"`
+			Stock      string `json:"stock"`
+			Unit       string `json:"unit"`
+			Serialized bool   `json:"serialized"`
+			// Reorder point and quantity are optional and may be expressed in any compatible unit
+			ReorderPoint    string `json:"reorder_point"`
+			ReorderQuantity string `json:"reorder_quantity"`
+			Units           []struct {
+				Code   string `json:"code"`
+				Factor string `json:"factor"`
+			} `json:"units"`
 		} `json:"inventory"`
 	}
 	err = json.NewDecoder(f).Decode(&content)
@@ -140,12 +204,124 @@ func SeedArticles(ctx context.Context, db *pgx.Conn, datadir string) error {
 		return err
 	}
 
+	// articles are stocked in the base unit of their declared unit, packaging unit factors are converted
+	// to the base unit and copied before any quantity is converted
+	conv, err := loadConverter(ctx, db)
+	if err != nil {
+		return err
+	}
+	articles := make([]models.Article, len(content.Articles))
+	declared := make([]string, len(content.Articles))
+	var unitRows [][]any
+	for i, item := range content.Articles {
+		id, err := strconv.Atoi(item.ArtId)
+		if err != nil {
+			return err
+		}
+		declared[i] = item.Unit
+		if declared[i] == "" {
+			declared[i] = defaultUnit
+		}
+		base, factor, err := conv.Base(declared[i])
+		if err != nil {
+			return fmt.Errorf("article %q: %w", item.Name, err)
+		}
+		articles[i] = models.Article{
+			ID:   int32(id),
+			Unit: base,
+		}
+
+		for _, u := range item.Units {
+			unitFactor, err := strconv.ParseInt(u.Factor, 10, 64)
+			if err != nil {
+				return err
+			}
+			unitRows = append(unitRows, []any{articles[i].ID, u.Code, unitFactor * factor})
+		}
+	}
+	_, err = db.CopyFrom(ctx, pgx.Identifier{"article_units"}, []string{"article_id", "code", "factor"}, pgx.CopyFromRows(unitRows))
+	if err != nil {
+		return err
+	}
+
+	conv, err = loadConverter(ctx, db)
+	if err != nil {
+		return err
+	}
+
 	table := "articles"
-	columns := []string{"id", "name", "stock"}
+	columns := []string{"id", "name", "stock", "unit", "serialized", "reorder_point", "reorder_quantity"}
 	var rows [][]any
-	for _, item := range content.Articles {
-		rows = append(rows, []any{item.ArtId, item.Name, item.Stock})
+	for i, item := range content.Articles {
+		article := articles[i]
+		stock, err := toArticleQuantity(conv, article, declared[i], item.Stock)
+		if err != nil {
+			return fmt.Errorf("article %q: %w", item.Name, err)
+		}
+		var reorderPoint *int32
+		if item.ReorderPoint != "" {
+			point, err := toArticleQuantity(conv, article, declared[i], item.ReorderPoint)
+			if err != nil {
+				return fmt.Errorf("article %q: %w", item.Name, err)
+			}
+			reorderPoint = &point
+		}
+		var reorderQuantity int32
+		if item.ReorderQuantity != "" {
+			reorderQuantity, err = toArticleQuantity(conv, article, declared[i], item.ReorderQuantity)
+			if err != nil {
+				return fmt.Errorf("article %q: %w", item.Name, err)
+			}
+		}
+		rows = append(rows, []any{article.ID, item.Name, stock, article.Unit, item.Serialized, reorderPoint, reorderQuantity})
 	}
 	_, err = db.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
 	return err
 }
+
+// toArticleQuantity converts the quantity to the article unit, a quantity without unit is in the declared unit
+func toArticleQuantity(conv *uom.Converter, article models.Article, declared, s string) (int32, error) {
+	quantity, err := uom.ParseQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if quantity.Unit == "" {
+		quantity.Unit = declared
+	}
+	return conv.ToBase(article, quantity)
+}
+
+func loadConverter(ctx context.Context, db *pgx.Conn) (*uom.Converter, error) {
+	rows, err := db.Query(ctx, `SELECT code, dimension, factor FROM units`)
+	if err != nil {
+		return nil, err
+	}
+	units, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.Unit])
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(ctx, `SELECT article_id, code, factor FROM article_units`)
+	if err != nil {
+		return nil, err
+	}
+	articleUnits, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.ArticleUnit])
+	if err != nil {
+		return nil, err
+	}
+	return uom.NewConverter(units, articleUnits), nil
+}
+
+func loadArticles(ctx context.Context, db *pgx.Conn) (map[int32]models.Article, error) {
+	rows, err := db.Query(ctx, `SELECT id, unit FROM articles`)
+	if err != nil {
+		return nil, err
+	}
+	articles := make(map[int32]models.Article)
+	var article models.Article
+	_, err = pgx.ForEachRow(rows, []any{&article.ID, &article.Unit}, func() error {
+		articles[article.ID] = article
+		return nil
+	})
+	return articles, err
+}
@@ -3,44 +3,140 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"warehouse/api/warehousepb"
+	"warehouse/internal/auth"
 	"warehouse/internal/config"
 	"warehouse/internal/db"
 	intgrpc "warehouse/internal/grpc"
+	"warehouse/internal/logging"
+	"warehouse/internal/metrics"
 	articlesrepo "warehouse/internal/repositories/articles"
+	assembliesrepo "warehouse/internal/repositories/assemblies"
+	backordersrepo "warehouse/internal/repositories/backorders"
+	ordersrepo "warehouse/internal/repositories/orders"
+	outboxrepo "warehouse/internal/repositories/outbox"
 	productsrepo "warehouse/internal/repositories/products"
+	purchaseordersrepo "warehouse/internal/repositories/purchaseorders"
+	salesrepo "warehouse/internal/repositories/sales"
+	serialsrepo "warehouse/internal/repositories/serials"
+	stocktakesrepo "warehouse/internal/repositories/stocktakes"
+	suppliersrepo "warehouse/internal/repositories/suppliers"
+	unitsrepo "warehouse/internal/repositories/units"
+	webhooksrepo "warehouse/internal/repositories/webhooks"
+	"warehouse/internal/services/articles"
+	"warehouse/internal/services/lowstock"
+	"warehouse/internal/services/orders"
+	"warehouse/internal/services/outbox"
 	"warehouse/internal/services/products"
+	"warehouse/internal/services/purchaseorders"
+	"warehouse/internal/services/replenishment"
+	"warehouse/internal/services/stocktakes"
+	"warehouse/internal/services/suppliers"
+	"warehouse/internal/services/webhooks"
+	"warehouse/internal/tracing"
 )
 
 func main() {
 	fx.New(
+		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
+			fxLogger := &fxevent.SlogLogger{Logger: logger}
+			// dependency injection events are only of interest when debugging the startup
+			fxLogger.UseLogLevel(slog.LevelDebug)
+			return fxLogger
+		}),
 		fx.Provide(NewApplicationContext),
 		fx.Provide(config.NewConfig),
+		fx.Provide(NewLogger),
+		fx.Provide(NewTracerProvider),
+		fx.Provide(NewMetrics),
+		fx.Provide(NewAuthenticator),
 		fx.Provide(NewGRPCServer),
 		fx.Provide(NewDatabase),
+		fx.Provide(NewListener),
+		fx.Provide(db.NewTransactor),
 		fx.Provide(articlesrepo.NewRepository),
 		fx.Provide(productsrepo.NewRepository),
-		fx.Provide(NewWarehouseService),
+		fx.Provide(salesrepo.NewRepository),
+		fx.Provide(serialsrepo.NewRepository),
+		fx.Provide(unitsrepo.NewRepository),
+		fx.Provide(suppliersrepo.NewRepository),
+		fx.Provide(purchaseordersrepo.NewRepository),
+		fx.Provide(assembliesrepo.NewRepository),
+		fx.Provide(ordersrepo.NewRepository),
+		fx.Provide(backordersrepo.NewRepository),
+		fx.Provide(stocktakesrepo.NewRepository),
+		fx.Provide(outboxrepo.NewRepository),
+		fx.Provide(webhooksrepo.NewRepository),
+		fx.Provide(fx.Annotate(products.NewService, fx.ResultTags(`name:"uncached"`))),
+		fx.Provide(fx.Annotate(products.NewCache, fx.ParamTags(`name:"uncached"`))),
+		fx.Provide(func(cache *products.Cache, tp trace.TracerProvider) products.Service {
+			return products.NewTracing(cache, tp)
+		}),
+		fx.Provide(articles.NewService),
+		fx.Provide(suppliers.NewService),
+		fx.Provide(purchaseorders.NewService),
+		fx.Provide(orders.NewService),
+		fx.Provide(stocktakes.NewService),
+		fx.Provide(NewWebhooksConfig),
+		fx.Provide(fx.Annotate(webhooks.NewService, fx.ParamTags(``, ``, ``, `name:"uncached"`))),
+		fx.Provide(NewReplenishmentConfig),
+		fx.Provide(replenishment.NewService),
+		fx.Provide(intgrpc.NewService),
+		fx.Provide(intgrpc.NewSupplierService),
+		fx.Provide(intgrpc.NewPurchaseOrderService),
+		fx.Provide(intgrpc.NewOrderService),
+		fx.Provide(intgrpc.NewStocktakeService),
+		fx.Provide(intgrpc.NewWebhookService),
+		fx.Provide(NewLowStockEvaluator),
+		fx.Provide(NewOutboxConfig),
+		fx.Provide(NewOutboxRelay),
 		fx.Invoke(MigrateDatabase),
-		fx.Invoke(func(server *grpc.Server, service *intgrpc.Service) {
+		fx.Invoke(RunLowStockEvaluator),
+		fx.Invoke(RunBackorderFulfilment),
+		fx.Invoke(RunProductCacheInvalidation),
+		fx.Invoke(RunOutboxRelay),
+		fx.Invoke(RunWebhooks),
+		fx.Invoke(func(
+			server *grpc.Server,
+			service *intgrpc.Service,
+			supplierService *intgrpc.SupplierService,
+			purchaseOrderService *intgrpc.PurchaseOrderService,
+			orderService *intgrpc.OrderService,
+			stocktakeService *intgrpc.StocktakeService,
+			webhookService *intgrpc.WebhookService,
+		) {
 			warehousepb.RegisterWarehouseServiceServer(server, service)
+			warehousepb.RegisterSupplierServiceServer(server, supplierService)
+			warehousepb.RegisterPurchaseOrderServiceServer(server, purchaseOrderService)
+			warehousepb.RegisterOrderServiceServer(server, orderService)
+			warehousepb.RegisterStocktakeServiceServer(server, stocktakeService)
+			warehousepb.RegisterWebhookServiceServer(server, webhookService)
 		}),
 	).Run()
 }
 
-func NewApplicationContext(lc fx.Lifecycle) context.Context {
-	ctx, cancel := context.WithCancel(context.Background())
+// NewApplicationContext is cancelled when the application stops, it carries the logger for background work
+func NewApplicationContext(lc fx.Lifecycle, logger *slog.Logger) context.Context {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logger))
 	lc.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
 			cancel()
@@ -50,14 +146,68 @@ func NewApplicationContext(lc fx.Lifecycle) context.Context {
 	return ctx
 }
 
-func NewGRPCServer(lc fx.Lifecycle, appCfg config.Config) (*grpc.Server, error) {
+// NewLogger creates the logger configured in the logging section and makes it the default logger
+func NewLogger(appCfg config.Config) (*slog.Logger, error) {
+	var cfg logging.Config
+	err := appCfg.GetConfig("logging", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := logging.New(cfg, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// NewAuthenticator creates the authenticator for the credentials and rules configured in the auth section
+func NewAuthenticator(appCfg config.Config) (*auth.Authenticator, error) {
+	var cfg auth.Config
+	err := appCfg.GetConfig("auth", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewAuthenticator(cfg)
+}
+
+// NewGRPCServer creates the gRPC server, served over TLS if certificates are configured.
+// The certificates are reloaded when they change on disk.
+func NewGRPCServer(
+	lc fx.Lifecycle,
+	appCtx context.Context,
+	appCfg config.Config,
+	logger *slog.Logger,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+	a *auth.Authenticator,
+) (*grpc.Server, error) {
 	var cfg intgrpc.Config
 	err := appCfg.GetConfig("grpc", &cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	server := grpc.NewServer()
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))),
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(logger),
+			m.UnaryServerInterceptor(),
+			a.UnaryServerInterceptor(),
+			logging.RecoveryUnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(a.StreamServerInterceptor(), logging.RecoveryStreamServerInterceptor()),
+	}
+	var certs *intgrpc.Certificates
+	if cfg.TLS.Enabled() {
+		certs, err = intgrpc.NewCertificates(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	}
+
+	server := grpc.NewServer(opts...)
 	reflection.Register(server)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -65,11 +215,19 @@ func NewGRPCServer(lc fx.Lifecycle, appCfg config.Config) (*grpc.Server, error)
 			if err != nil {
 				return err
 			}
+			if certs != nil {
+				go func() {
+					err := certs.Watch(appCtx)
+					if err != nil {
+						logger.Error("error watching tls certificates", "error", err)
+					}
+				}()
+			}
 			go func() {
-				log.Printf("grpc server listening on %s", cfg.Address())
+				logger.Info("grpc server listening", "address", cfg.Address(), "tls", certs != nil)
 				err = server.Serve(lis)
 				if err != nil {
-					log.Printf("error serving grpc server: %s", err)
+					logger.Error("error serving grpc server", "error", err)
 				}
 			}()
 			return nil
@@ -82,12 +240,87 @@ func NewGRPCServer(lc fx.Lifecycle, appCfg config.Config) (*grpc.Server, error)
 	return server, nil
 }
 
-func NewDatabase(lc fx.Lifecycle, appCtx context.Context, appCfg config.Config) (*pgxpool.Pool, error) {
+// NewTracerProvider creates the tracer provider for the exporter configured in the tracing section,
+// spans still buffered are exported on stop
+func NewTracerProvider(lc fx.Lifecycle, appCtx context.Context, appCfg config.Config) (trace.TracerProvider, error) {
+	var cfg tracing.Config
+	err := appCfg.GetConfig("tracing", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	tp, shutdown, err := tracing.NewTracerProvider(appCtx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	lc.Append(fx.Hook{
+		OnStop: shutdown,
+	})
+	return tp, nil
+}
+
+// NewMetrics collects the server metrics, they are served over HTTP if enabled
+func NewMetrics(
+	lc fx.Lifecycle,
+	appCfg config.Config,
+	logger *slog.Logger,
+	pool *pgxpool.Pool,
+	aRepo articlesrepo.Repository,
+) (*metrics.Metrics, error) {
+	var cfg metrics.Config
+	err := appCfg.GetConfig("metrics", &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := metrics.New()
+	err = m.Register(metrics.NewPoolCollector(pool), metrics.NewStockCollector(aRepo, cfg.ScrapeTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return m, nil
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, m.Handler())
+	server := &http.Server{Addr: cfg.Address(), Handler: mux}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", cfg.Address())
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Info("metrics server listening", "address", cfg.Address())
+				err := server.Serve(lis)
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("error serving metrics server", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+	return m, nil
+}
+
+func NewDatabase(lc fx.Lifecycle, appCtx context.Context, appCfg config.Config, tp trace.TracerProvider) (*pgxpool.Pool, error) {
 	cfg, err := db.ParseConfig(appCfg)
 	if err != nil {
 		return nil, err
 	}
-	pool, err := pgxpool.New(appCtx, cfg.DSN())
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig.Tracer = db.NewTracer(tp)
+	pool, err := pgxpool.NewWithConfig(appCtx, poolCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +354,236 @@ func MigrateDatabase(appCfg config.Config) error {
 	return nil
 }
 
-func NewWarehouseService(aRepo articlesrepo.Repository, pRepo productsrepo.Repository) (*intgrpc.Service, error) {
-	productsSrv := products.NewService(aRepo, pRepo)
-	return intgrpc.NewService(productsSrv), nil
+func NewReplenishmentConfig(appCfg config.Config) (replenishment.Config, error) {
+	var cfg replenishment.Config
+	err := appCfg.GetConfig("replenishment", &cfg)
+	return cfg, err
+}
+
+func NewLowStockEvaluator(appCfg config.Config, aRepo articlesrepo.Repository) (lowstock.Evaluator, error) {
+	var cfg lowstock.Config
+	err := appCfg.GetConfig("alerts", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return lowstock.NewEvaluator(aRepo, lowstock.NewSinks(cfg)), nil
+}
+
+// NewListener creates the listener all notification subscribers share, it listens on a dedicated connection
+// outside the pool
+func NewListener(lc fx.Lifecycle, appCtx context.Context, pool *pgxpool.Pool) *db.Listener {
+	listener := db.NewListener(pool.Config().ConnConfig.Copy())
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go listener.Run(appCtx)
+			return nil
+		},
+	})
+	return listener
+}
+
+// notifiedIDs parses the IDs notified on a changes channel, invalid payloads are skipped
+func notifiedIDs(ctx context.Context, channel string, payloads []string) []int32 {
+	ids := make([]int32, 0, len(payloads))
+	for _, payload := range payloads {
+		id, err := strconv.ParseInt(payload, 10, 32)
+		if err != nil {
+			logging.FromContext(ctx).Warn("invalid notification", "channel", channel, "payload", payload)
+			continue
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids
+}
+
+// RunLowStockEvaluator evaluates articles whenever their stock changes.
+// All articles are evaluated on (re)connect to catch up with changes made while not listening.
+func RunLowStockEvaluator(listener *db.Listener, evaluator lowstock.Evaluator) {
+	evaluate := func(ctx context.Context, ids []int32) {
+		err := evaluator.Evaluate(ctx, ids)
+		if err != nil {
+			logging.FromContext(ctx).Error("error evaluating low stock", "error", err)
+		}
+	}
+
+	listener.Subscribe(articlesrepo.ChangesChannel,
+		func(ctx context.Context) {
+			evaluate(ctx, nil)
+		},
+		func(ctx context.Context, payloads []string) {
+			if ids := notifiedIDs(ctx, articlesrepo.ChangesChannel, payloads); len(ids) > 0 {
+				evaluate(ctx, ids)
+			}
+		},
+	)
+}
+
+// RunBackorderFulfilment fulfils backorders whenever the available article stock increases, sales and
+// reservations do not trigger a fulfilment. All backorders are processed on (re)connect to catch up
+// with changes made while not listening.
+func RunBackorderFulfilment(listener *db.Listener, productsSrv products.Service) {
+	fulfil := func(ctx context.Context, ids []int32) {
+		items, err := productsSrv.FulfilBackorders(ctx, ids)
+		if err != nil {
+			logging.FromContext(ctx).Error("error fulfilling backorders", "error", err)
+			return
+		}
+		for _, item := range items {
+			logging.FromContext(ctx).Info("backorder fulfilled",
+				"backorder_id", item.ID,
+				"sale_id", item.SaleID,
+				"fulfilled", item.Fulfilled,
+				"quantity", item.Quantity,
+			)
+		}
+	}
+
+	listener.Subscribe(articlesrepo.RestocksChannel,
+		func(ctx context.Context) {
+			fulfil(ctx, nil)
+		},
+		func(ctx context.Context, payloads []string) {
+			if ids := notifiedIDs(ctx, articlesrepo.RestocksChannel, payloads); len(ids) > 0 {
+				fulfil(ctx, ids)
+			}
+		},
+	)
+}
+
+// RunProductCacheInvalidation invalidates cached products whenever products or the articles they are made of change,
+// notifications keep the caches of all server instances coherent. The cache is dropped on (re)connect
+// as changes might have been missed while not listening, and whenever units change.
+func RunProductCacheInvalidation(listener *db.Listener, cache *products.Cache) {
+	invalidate := func(channel string, fn func(ids []int32)) func(ctx context.Context, payloads []string) {
+		return func(ctx context.Context, payloads []string) {
+			fn(notifiedIDs(ctx, channel, payloads))
+		}
+	}
+	onConnect := func(ctx context.Context) {
+		cache.InvalidateAll()
+	}
+
+	listener.Subscribe(articlesrepo.ChangesChannel, onConnect, invalidate(articlesrepo.ChangesChannel, cache.InvalidateArticles))
+	listener.Subscribe(productsrepo.ChangesChannel, onConnect, invalidate(productsrepo.ChangesChannel, cache.InvalidateProducts))
+	listener.Subscribe(unitsrepo.ChangesChannel, onConnect, func(ctx context.Context, payloads []string) {
+		cache.InvalidateAll()
+	})
+}
+
+func NewOutboxConfig(appCfg config.Config) (outbox.Config, error) {
+	var cfg outbox.Config
+	err := appCfg.GetConfig("outbox", &cfg)
+	return cfg, err
+}
+
+// NewOutboxRelay relays events to the configured sinks, the business metrics observe the relayed events
+func NewOutboxRelay(cfg outbox.Config, oRepo outboxrepo.Repository, m *metrics.Metrics) outbox.Relay {
+	return outbox.NewRelay(cfg, oRepo, outbox.NewSinks(cfg), []outbox.Observer{m})
+}
+
+// RunOutboxRelay relays outbox events to the sinks until the outbox is drained,
+// then the outbox is polled for new events.
+func RunOutboxRelay(lc fx.Lifecycle, appCtx context.Context, cfg outbox.Config, relay outbox.Relay) {
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				for {
+					var processed int
+					var err error
+					logging.Recover(appCtx, "outbox relay", func() {
+						processed, err = relay.Relay(appCtx)
+					})
+					if err != nil {
+						logging.FromContext(appCtx).Error("error relaying outbox events", "error", err)
+					}
+					if err == nil && processed > 0 {
+						continue
+					}
+					select {
+					case <-appCtx.Done():
+						return
+					case <-time.After(interval):
+					}
+				}
+			}()
+			return nil
+		},
+	})
+}
+
+func NewWebhooksConfig(appCfg config.Config) (webhooks.Config, error) {
+	var cfg webhooks.Config
+	err := appCfg.GetConfig("webhooks", &cfg)
+	return cfg, err
+}
+
+// RunWebhooks evaluates the product stock whenever products or articles change and dispatches the queued
+// deliveries. Notifications are coalesced, a burst of changes is evaluated once. The stock is evaluated
+// on (re)connect to catch up with changes made while not listening.
+func RunWebhooks(lc fx.Lifecycle, appCtx context.Context, listener *db.Listener, cfg webhooks.Config, webhooksSrv webhooks.Service) {
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	changed := make(chan struct{}, 1)
+	notify := func(ctx context.Context) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	listener.Subscribe(articlesrepo.ChangesChannel, notify, func(ctx context.Context, _ []string) {
+		notify(ctx)
+	})
+	listener.Subscribe(productsrepo.ChangesChannel, notify, func(ctx context.Context, _ []string) {
+		notify(ctx)
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				for {
+					select {
+					case <-appCtx.Done():
+						return
+					case <-changed:
+					}
+					var err error
+					logging.Recover(appCtx, "webhook evaluation", func() {
+						err = webhooksSrv.Evaluate(appCtx)
+					})
+					if err != nil {
+						logging.FromContext(appCtx).Error("error evaluating webhook events", "error", err)
+					}
+				}
+			}()
+			go func() {
+				for {
+					var processed int
+					var err error
+					logging.Recover(appCtx, "webhook dispatch", func() {
+						processed, err = webhooksSrv.Dispatch(appCtx)
+					})
+					if err != nil {
+						logging.FromContext(appCtx).Error("error dispatching webhooks", "error", err)
+					}
+					if err == nil && processed > 0 {
+						continue
+					}
+					select {
+					case <-appCtx.Done():
+						return
+					case <-time.After(interval):
+					}
+				}
+			}()
+			return nil
+		},
+	})
 }
@@ -0,0 +1,4 @@
+DROP TABLE article_serials;
+DROP TABLE sales;
+ALTER TABLE articles
+    DROP COLUMN serialized;
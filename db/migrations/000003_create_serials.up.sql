@@ -0,0 +1,24 @@
+ALTER TABLE articles
+    ADD COLUMN serialized BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE sales
+(
+    id         SERIAL,
+    product_id INTEGER     NOT NULL,
+    quantity   INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE article_serials
+(
+    serial      TEXT        NOT NULL,
+    article_id  INTEGER     NOT NULL,
+    sale_id     INTEGER,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (serial)
+);
+
+CREATE INDEX article_serials_available_idx ON article_serials (article_id, received_at) WHERE sale_id IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE article_units;
+ALTER TABLE articles
+    DROP COLUMN unit;
+DROP TABLE units;
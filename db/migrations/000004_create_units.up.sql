@@ -0,0 +1,34 @@
+CREATE TABLE units
+(
+    code      TEXT   NOT NULL,
+    dimension TEXT   NOT NULL,
+    factor    BIGINT NOT NULL,
+
+    PRIMARY KEY (code)
+);
+
+INSERT INTO units (code, dimension, factor)
+VALUES ('pcs', 'count', 1),
+       ('pair', 'count', 2),
+       ('dozen', 'count', 12),
+       ('mm', 'length', 1),
+       ('cm', 'length', 10),
+       ('m', 'length', 1000),
+       ('g', 'mass', 1),
+       ('kg', 'mass', 1000),
+       ('ml', 'volume', 1),
+       ('l', 'volume', 1000);
+
+-- articles are stocked in the base unit of their dimension, so that a quantity in any unit of the dimension
+-- is a whole number, e.g. 50 cm of an article stocked in mm
+ALTER TABLE articles
+    ADD COLUMN unit TEXT NOT NULL DEFAULT 'pcs';
+
+CREATE TABLE article_units
+(
+    article_id INTEGER NOT NULL,
+    code       TEXT    NOT NULL,
+    factor     BIGINT  NOT NULL,
+
+    PRIMARY KEY (article_id, code)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE products
+    DROP COLUMN currency;
+ALTER TABLE products
+    RENAME COLUMN price_minor TO price;
+ALTER TABLE products
+    ALTER COLUMN price TYPE FLOAT USING price / 100.0;
@@ -0,0 +1,7 @@
+-- prices are stored in minor units of the currency
+ALTER TABLE products
+    ALTER COLUMN price TYPE BIGINT USING round(price::numeric * 100);
+ALTER TABLE products
+    RENAME COLUMN price TO price_minor;
+ALTER TABLE products
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'EUR';
@@ -0,0 +1,6 @@
+DROP TRIGGER articles_changed ON articles;
+DROP FUNCTION notify_article_changed();
+ALTER TABLE articles
+    DROP COLUMN low_stock,
+    DROP COLUMN reorder_quantity,
+    DROP COLUMN reorder_point;
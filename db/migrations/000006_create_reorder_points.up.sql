@@ -0,0 +1,18 @@
+ALTER TABLE articles
+    ADD COLUMN reorder_point    INTEGER,
+    ADD COLUMN reorder_quantity INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN low_stock        BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE FUNCTION notify_article_changed() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('article_changes', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_changed
+    AFTER INSERT OR UPDATE OF stock, reorder_point
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION notify_article_changed();
@@ -0,0 +1,4 @@
+DROP TABLE purchase_order_lines;
+DROP TABLE purchase_orders;
+DROP TABLE supplier_articles;
+DROP TABLE suppliers;
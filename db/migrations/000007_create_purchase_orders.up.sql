@@ -0,0 +1,46 @@
+CREATE TABLE suppliers
+(
+    id   SERIAL,
+    name TEXT NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE supplier_articles
+(
+    supplier_id    INTEGER NOT NULL,
+    article_id     INTEGER NOT NULL,
+    sku            TEXT    NOT NULL,
+    price_minor    BIGINT  NOT NULL,
+    currency       CHAR(3) NOT NULL,
+    lead_time_days INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (supplier_id, article_id)
+);
+
+CREATE TABLE purchase_orders
+(
+    id          SERIAL,
+    supplier_id INTEGER     NOT NULL,
+    status      TEXT        NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'sent', 'partially_received', 'closed')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE purchase_order_lines
+(
+    id                SERIAL,
+    purchase_order_id INTEGER NOT NULL,
+    article_id        INTEGER NOT NULL,
+    quantity          INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity BETWEEN 0 AND quantity),
+    price_minor       BIGINT  NOT NULL,
+    currency          CHAR(3) NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX purchase_order_lines_order_idx ON purchase_order_lines (purchase_order_id);
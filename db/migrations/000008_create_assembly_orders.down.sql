@@ -0,0 +1,3 @@
+DROP TABLE assembly_orders;
+ALTER TABLE products
+    DROP COLUMN assembled;
@@ -0,0 +1,16 @@
+-- assembled is the finished goods stock of pre-assembled products
+ALTER TABLE products
+    ADD COLUMN assembled INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE assembly_orders
+(
+    id           SERIAL,
+    product_id   INTEGER     NOT NULL,
+    quantity     INTEGER     NOT NULL CHECK (quantity > 0),
+    status       TEXT        NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'completed')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE disassembly_components;
+DROP TABLE disassemblies;
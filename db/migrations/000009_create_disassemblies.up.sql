@@ -0,0 +1,22 @@
+CREATE TABLE disassemblies
+(
+    id             SERIAL,
+    product_id     INTEGER     NOT NULL,
+    quantity       INTEGER     NOT NULL CHECK (quantity > 0),
+    from_assembled BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (id)
+);
+
+-- quantities are in the article unit
+CREATE TABLE disassembly_components
+(
+    disassembly_id INTEGER NOT NULL,
+    article_id     INTEGER NOT NULL,
+    expected       INTEGER NOT NULL,
+    recovered      INTEGER NOT NULL,
+    scrapped       INTEGER NOT NULL,
+
+    PRIMARY KEY (disassembly_id, article_id)
+);
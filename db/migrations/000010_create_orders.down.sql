@@ -0,0 +1,6 @@
+DROP TABLE order_reservations;
+DROP TABLE order_lines;
+DROP TABLE orders;
+
+ALTER TABLE articles
+    DROP COLUMN reserved;
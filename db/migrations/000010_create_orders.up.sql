@@ -0,0 +1,37 @@
+ALTER TABLE articles
+    ADD COLUMN reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0);
+
+CREATE TABLE orders
+(
+    id         SERIAL,
+    status     TEXT        NOT NULL DEFAULT 'placed'
+        CHECK (status IN ('placed', 'allocated', 'picking', 'packed', 'shipped', 'cancelled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE order_lines
+(
+    id         SERIAL,
+    order_id   INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity   INTEGER NOT NULL CHECK (quantity > 0),
+    -- units taken from the assembled stock on allocation
+    assembled  INTEGER NOT NULL DEFAULT 0 CHECK (assembled BETWEEN 0 AND quantity),
+    sale_id    INTEGER,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX order_lines_order_idx ON order_lines (order_id);
+
+CREATE TABLE order_reservations
+(
+    order_line_id INTEGER NOT NULL,
+    article_id    INTEGER NOT NULL,
+    quantity      INTEGER NOT NULL CHECK (quantity > 0),
+
+    PRIMARY KEY (order_line_id, article_id)
+);
@@ -0,0 +1,4 @@
+DROP TRIGGER articles_created ON articles;
+DROP TRIGGER articles_restocked ON articles;
+DROP FUNCTION notify_article_restocked();
+DROP TABLE backorders;
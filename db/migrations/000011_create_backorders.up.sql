@@ -0,0 +1,39 @@
+CREATE TABLE backorders
+(
+    id           SERIAL,
+    sale_id      INTEGER     NOT NULL,
+    product_id   INTEGER     NOT NULL,
+    quantity     INTEGER     NOT NULL CHECK (quantity > 0),
+    fulfilled    INTEGER     NOT NULL DEFAULT 0 CHECK (fulfilled BETWEEN 0 AND quantity),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    fulfilled_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id),
+    UNIQUE (sale_id)
+);
+
+CREATE INDEX backorders_open_idx ON backorders (created_at, id) WHERE fulfilled < quantity;
+
+-- backorders can only be fulfilled when the available stock of an article increases, other stock changes,
+-- e.g. sales, are not notified on this channel
+CREATE FUNCTION notify_article_restocked() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('article_restocks', NEW.id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_restocked
+    AFTER UPDATE OF stock, reserved
+    ON articles
+    FOR EACH ROW
+    WHEN (NEW.stock - NEW.reserved > OLD.stock - OLD.reserved)
+EXECUTE FUNCTION notify_article_restocked();
+
+CREATE TRIGGER articles_created
+    AFTER INSERT
+    ON articles
+    FOR EACH ROW
+    WHEN (NEW.stock > 0)
+EXECUTE FUNCTION notify_article_restocked();
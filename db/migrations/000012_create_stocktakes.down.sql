@@ -0,0 +1,3 @@
+DROP TABLE stocktake_counts;
+DROP TABLE stocktake_articles;
+DROP TABLE stocktakes;
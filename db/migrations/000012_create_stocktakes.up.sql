@@ -0,0 +1,34 @@
+CREATE TABLE stocktakes
+(
+    id         SERIAL,
+    status     TEXT        NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'approved', 'cancelled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at  TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE stocktake_articles
+(
+    stocktake_id INTEGER NOT NULL,
+    article_id   INTEGER NOT NULL,
+    -- stock frozen when the stocktake was opened
+    expected     INTEGER NOT NULL,
+    adjustment   INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (stocktake_id, article_id)
+);
+
+CREATE TABLE stocktake_counts
+(
+    stocktake_id INTEGER     NOT NULL,
+    article_id   INTEGER     NOT NULL,
+    counter      TEXT        NOT NULL,
+    quantity     INTEGER     NOT NULL CHECK (quantity >= 0),
+    -- stock of the article when the count was submitted, the variance is measured against it
+    stock        INTEGER     NOT NULL,
+    counted_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (stocktake_id, article_id, counter)
+);
@@ -0,0 +1,7 @@
+DROP TRIGGER products_version ON products;
+DROP TRIGGER articles_version ON articles;
+DROP FUNCTION increment_version();
+ALTER TABLE products
+    DROP COLUMN version;
+ALTER TABLE articles
+    DROP COLUMN version;
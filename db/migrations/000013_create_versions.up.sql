@@ -0,0 +1,26 @@
+-- version is incremented when a column edited by clients changes, clients pass the version they read to detect
+-- concurrent changes. Stock and reservation changes do not make a concurrent catalog edit fail
+ALTER TABLE articles
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE products
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE FUNCTION increment_version() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_version
+    BEFORE UPDATE OF name, unit, reorder_point, reorder_quantity
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION increment_version();
+
+CREATE TRIGGER products_version
+    BEFORE UPDATE OF name, price_minor, currency, articles
+    ON products
+    FOR EACH ROW
+EXECUTE FUNCTION increment_version();
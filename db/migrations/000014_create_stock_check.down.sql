@@ -0,0 +1,3 @@
+ALTER TABLE articles
+    DROP CONSTRAINT articles_stock_non_negative;
+DROP TABLE stock_corrections;
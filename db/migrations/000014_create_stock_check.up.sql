@@ -0,0 +1,31 @@
+-- negative stock found before the constraint is added is reset to zero, the corrected rows are kept for review
+CREATE TABLE stock_corrections
+(
+    article_id   INTEGER     NOT NULL,
+    stock        INTEGER     NOT NULL,
+    corrected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+DO
+$$
+    DECLARE
+        item RECORD;
+    BEGIN
+        FOR item IN SELECT id, name, stock FROM articles WHERE stock < 0 ORDER BY id
+            LOOP
+                RAISE WARNING 'article % (%) has negative stock %, resetting to 0', item.id, item.name, item.stock;
+            END LOOP;
+    END
+$$;
+
+INSERT INTO stock_corrections (article_id, stock)
+SELECT id, stock
+FROM articles
+WHERE stock < 0;
+
+UPDATE articles
+SET stock = 0
+WHERE stock < 0;
+
+ALTER TABLE articles
+    ADD CONSTRAINT articles_stock_non_negative CHECK (stock >= 0);
@@ -0,0 +1,13 @@
+DROP TRIGGER units_changed ON units;
+DROP FUNCTION notify_units_changed();
+DROP TRIGGER products_changed ON products;
+DROP FUNCTION notify_product_changed();
+DROP TRIGGER article_units_changed ON article_units;
+DROP FUNCTION notify_article_units_changed();
+
+DROP TRIGGER articles_changed ON articles;
+CREATE TRIGGER articles_changed
+    AFTER INSERT OR UPDATE OF stock, reorder_point
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION notify_article_changed();
@@ -0,0 +1,58 @@
+-- reserved stock and units change the product availability, notify those changes as well
+DROP TRIGGER articles_changed ON articles;
+CREATE TRIGGER articles_changed
+    AFTER INSERT OR UPDATE OF stock, reserved, unit, reorder_point
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION notify_article_changed();
+
+CREATE FUNCTION notify_article_units_changed() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('article_changes', OLD.article_id::text);
+    ELSE
+        PERFORM pg_notify('article_changes', NEW.article_id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER article_units_changed
+    AFTER INSERT OR UPDATE OR DELETE
+    ON article_units
+    FOR EACH ROW
+EXECUTE FUNCTION notify_article_units_changed();
+
+CREATE FUNCTION notify_product_changed() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('product_changes', OLD.id::text);
+    ELSE
+        PERFORM pg_notify('product_changes', NEW.id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_changed
+    AFTER INSERT OR UPDATE OR DELETE
+    ON products
+    FOR EACH ROW
+EXECUTE FUNCTION notify_product_changed();
+
+-- unit factors convert every BOM line, a change invalidates all products
+CREATE FUNCTION notify_units_changed() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('unit_changes', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER units_changed
+    AFTER INSERT OR UPDATE OR DELETE
+    ON units
+    FOR EACH STATEMENT
+EXECUTE FUNCTION notify_units_changed();
@@ -0,0 +1,7 @@
+DROP TRIGGER products_outbox ON products;
+DROP FUNCTION outbox_product_changed();
+DROP TRIGGER articles_outbox ON articles;
+DROP FUNCTION outbox_article_stock_changed();
+DROP TRIGGER sales_outbox ON sales;
+DROP FUNCTION outbox_product_sold();
+DROP TABLE outbox;
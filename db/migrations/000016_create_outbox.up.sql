@@ -0,0 +1,109 @@
+-- outbox holds domain events written by triggers in the transaction of the change,
+-- the payload is the warehouse.Event payload in the protobuf JSON mapping
+CREATE TABLE outbox
+(
+    id              BIGSERIAL,
+    event_type      TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ,
+    -- set for events whose payload cannot be decoded, they are never retried
+    dead_at         TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, id) WHERE delivered_at IS NULL AND dead_at IS NULL;
+
+CREATE FUNCTION outbox_product_sold() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO outbox (event_type, payload)
+    VALUES ('ProductSold', jsonb_build_object('productSold', jsonb_build_object(
+            'saleId', NEW.id,
+            'productId', NEW.product_id,
+            'quantity', NEW.quantity
+        )));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sales_outbox
+    AFTER INSERT
+    ON sales
+    FOR EACH ROW
+EXECUTE FUNCTION outbox_product_sold();
+
+CREATE FUNCTION outbox_article_stock_changed() RETURNS TRIGGER AS
+$$
+DECLARE
+    stock_before INTEGER := 0;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.stock = NEW.stock AND OLD.reserved = NEW.reserved THEN
+            RETURN NULL;
+        END IF;
+        stock_before := OLD.stock;
+    END IF;
+
+    INSERT INTO outbox (event_type, payload)
+    VALUES ('ArticleStockChanged', jsonb_build_object('articleStockChanged', jsonb_build_object(
+            'articleId', NEW.id,
+            'stockBefore', stock_before,
+            'stockAfter', NEW.stock,
+            'reserved', NEW.reserved
+        )));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_outbox
+    AFTER INSERT OR UPDATE OF stock, reserved
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION outbox_article_stock_changed();
+
+CREATE FUNCTION outbox_product_changed() RETURNS TRIGGER AS
+$$
+DECLARE
+    item products;
+    bom  JSONB := '[]';
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        item := OLD;
+    ELSE
+        item := NEW;
+    END IF;
+
+    -- product articles are stored with Go field names
+    IF jsonb_typeof(item.articles) = 'array' THEN
+        SELECT coalesce(jsonb_agg(jsonb_build_object(
+                'id', a -> 'ID',
+                'quantity', a -> 'Quantity',
+                'unit', coalesce(a ->> 'Unit', '')
+            )), '[]')
+        INTO bom
+        FROM jsonb_array_elements(item.articles) a;
+    END IF;
+
+    INSERT INTO outbox (event_type, payload)
+    VALUES ('ProductChanged', jsonb_build_object('productChanged', jsonb_build_object(
+            'productId', item.id,
+            'name', item.name,
+            'price', jsonb_build_object('currencyCode', item.currency, 'amountMinor', item.price_minor),
+            'articles', bom,
+            'assembled', item.assembled,
+            'deleted', TG_OP = 'DELETE'
+        )));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_outbox
+    AFTER INSERT OR UPDATE OR DELETE
+    ON products
+    FOR EACH ROW
+EXECUTE FUNCTION outbox_product_changed();
@@ -0,0 +1,3 @@
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_product_states;
+DROP TABLE webhook_subscriptions;
@@ -0,0 +1,42 @@
+-- webhook_subscriptions receive product stock events, empty filters match all events
+CREATE TABLE webhook_subscriptions
+(
+    id          SERIAL,
+    url         TEXT        NOT NULL,
+    secret      TEXT        NOT NULL,
+    event_types TEXT[]      NOT NULL DEFAULT '{}',
+    product_ids INTEGER[]   NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (id)
+);
+
+-- webhook_product_states holds the last known stock state of every product to detect transitions
+CREATE TABLE webhook_product_states
+(
+    product_id INTEGER NOT NULL,
+    in_stock   BOOLEAN NOT NULL,
+
+    PRIMARY KEY (product_id)
+);
+
+-- webhook_deliveries are dead letters once dead_at is set, they are only retried when replayed
+CREATE TABLE webhook_deliveries
+(
+    id              BIGSERIAL,
+    subscription_id INTEGER     NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_type      TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ,
+    dead_at         TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at, id)
+    WHERE delivered_at IS NULL AND dead_at IS NULL;
+CREATE INDEX webhook_deliveries_dead_idx ON webhook_deliveries (subscription_id, id) WHERE dead_at IS NOT NULL;
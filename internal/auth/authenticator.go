@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"warehouse/internal/logging"
+)
+
+const (
+	APIKeyHeader        = "x-api-key"
+	AuthorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+	defaultRoleClaim    = "role"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrUnknownClientCert  = errors.New("unknown client certificate")
+)
+
+// Authenticator identifies callers by API key, JWT bearer token or TLS client certificate
+// and checks their role against the rules
+type Authenticator struct {
+	enabled     bool
+	apiKeys     map[[sha256.Size]byte]Identity
+	clientCerts map[string]Identity
+	jwtKeys     map[string]crypto.PublicKey
+	hmacSecret  []byte
+	roleClaim   string
+	parser      *jwt.Parser
+	rules       rules
+}
+
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{
+		enabled:     cfg.Enabled,
+		apiKeys:     make(map[[sha256.Size]byte]Identity, len(cfg.APIKeys)),
+		clientCerts: make(map[string]Identity, len(cfg.ClientCerts)),
+		roleClaim:   cfg.JWT.RoleClaim,
+	}
+	if a.roleClaim == "" {
+		a.roleClaim = defaultRoleClaim
+	}
+
+	for _, key := range cfg.APIKeys {
+		hash, err := hex.DecodeString(key.KeySHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid sha256 hash of api key %q", key.Name)
+		}
+		if !key.Role.Valid() {
+			return nil, fmt.Errorf("invalid role %q of api key %q", key.Role, key.Name)
+		}
+		a.apiKeys[[sha256.Size]byte(hash)] = Identity{Subject: key.Name, Role: key.Role, Method: MethodAPIKey}
+	}
+
+	for _, cert := range cfg.ClientCerts {
+		if cert.Subject == "" {
+			return nil, errors.New("missing subject of client certificate")
+		}
+		if !cert.Role.Valid() {
+			return nil, fmt.Errorf("invalid role %q of client certificate %q", cert.Role, cert.Subject)
+		}
+		name := cert.Name
+		if name == "" {
+			name = cert.Subject
+		}
+		a.clientCerts[cert.Subject] = Identity{Subject: name, Role: cert.Role, Method: MethodClientCert}
+	}
+
+	methods := []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+	if cfg.JWT.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWT.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwks: %w", err)
+		}
+		a.jwtKeys = keys
+	}
+	if cfg.JWT.HMACSecret != "" {
+		a.hmacSecret = []byte(cfg.JWT.HMACSecret)
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.JWT.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWT.Issuer))
+	}
+	if cfg.JWT.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWT.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	var err error
+	a.rules, err = newRules(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Authenticate identifies the caller by the x-api-key or the authorization bearer token metadata,
+// or by the TLS client certificate if the request carries neither
+func (a *Authenticator) Authenticate(ctx context.Context) (Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(APIKeyHeader); len(keys) > 0 {
+		return a.apiKey(keys[0])
+	}
+	if values := md.Get(AuthorizationHeader); len(values) > 0 {
+		value := values[0]
+		if len(value) > len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return a.token(value[len(bearerPrefix):])
+		}
+	}
+	return a.clientCert(ctx)
+}
+
+// clientCert looks the subject of the verified client certificate up by distinguished name, then by common name.
+// Certificates are only verified if the server is configured with client CAs.
+func (a *Authenticator) clientCert(ctx context.Context) (Identity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, ErrMissingCredentials
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, ErrMissingCredentials
+	}
+
+	subject := info.State.VerifiedChains[0][0].Subject
+	if id, ok := a.clientCerts[subject.String()]; ok {
+		return id, nil
+	}
+	if id, ok := a.clientCerts[subject.CommonName]; ok && subject.CommonName != "" {
+		return id, nil
+	}
+	return Identity{}, fmt.Errorf("%w: %s", ErrUnknownClientCert, subject)
+}
+
+// apiKey looks the key up by its hash, so the lookup time does not leak the stored keys
+func (a *Authenticator) apiKey(key string) (Identity, error) {
+	id, ok := a.apiKeys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return Identity{}, ErrInvalidAPIKey
+	}
+	return id, nil
+}
+
+func (a *Authenticator) token(raw string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(raw, claims, a.key)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	role, ok := highestRole(claims[a.roleClaim])
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: missing role", ErrInvalidToken)
+	}
+	return Identity{Subject: subject, Role: role, Method: MethodJWT}, nil
+}
+
+// key returns the key to verify the token with, asymmetric keys are selected by the kid header
+func (a *Authenticator) key(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.hmacSecret, nil
+	}
+	kid, _ := t.Header["kid"].(string)
+	key, ok := a.jwtKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+// highestRole reads a role or a list of roles from a claim, unknown roles are ignored
+func highestRole(claim any) (Role, bool) {
+	var values []any
+	switch v := claim.(type) {
+	case string:
+		values = []any{v}
+	case []any:
+		values = v
+	}
+
+	var role Role
+	for _, v := range values {
+		s, _ := v.(string)
+		if r := Role(s); r.Valid() && (role == "" || !role.Includes(r)) {
+			role = r
+		}
+	}
+	return role, role != ""
+}
+
+// authorize authenticates the caller of the method and checks the role required by the rules.
+// The identity is attached to the context and to the request logger.
+func (a *Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	if !a.enabled {
+		return ctx, nil
+	}
+	id, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if required := a.rules.required(method); !id.Role.Includes(required) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s role required", required)
+	}
+
+	ctx = WithIdentity(ctx, id)
+	ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("caller", id.Subject, "role", id.Role))
+	return ctx, nil
+}
+
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream passes the authorized context to stream handlers
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
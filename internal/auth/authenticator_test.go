@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	"warehouse/api/warehousepb"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwksFile := writeJWKS(t, rsaKey, ecKey)
+
+	a, err := NewAuthenticator(Config{
+		Enabled: true,
+		APIKeys: []APIKeyConfig{{Name: "pos", KeySHA256: hash("secret-key"), Role: RoleClerk}},
+		JWT: JWTConfig{
+			JWKSFile: jwksFile,
+			Issuer:   "https://id.example.com",
+			Audience: "warehouse",
+		},
+	})
+	require.NoError(t, err)
+
+	claims := func(role any) jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":  "alice",
+			"iss":  "https://id.example.com",
+			"aud":  "warehouse",
+			"exp":  time.Now().Add(time.Hour).Unix(),
+			"role": role,
+		}
+	}
+
+	t.Run("should accept api key", func(t *testing.T) {
+		id, err := a.Authenticate(incoming(APIKeyHeader, "secret-key"))
+
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Subject: "pos", Role: RoleClerk, Method: MethodAPIKey}, id)
+	})
+
+	t.Run("should reject unknown api key", func(t *testing.T) {
+		_, err := a.Authenticate(incoming(APIKeyHeader, "guess"))
+
+		require.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("should accept rsa and ec signed tokens", func(t *testing.T) {
+		id, err := a.Authenticate(incoming(AuthorizationHeader, "Bearer "+sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims("admin"))))
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Subject: "alice", Role: RoleAdmin, Method: MethodJWT}, id)
+
+		id, err = a.Authenticate(incoming(AuthorizationHeader, "bearer "+sign(t, jwt.SigningMethodES256, "ec", ecKey, claims([]any{"viewer", "clerk"}))))
+		require.NoError(t, err)
+		assert.Equal(t, RoleClerk, id.Role)
+	})
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		expired := claims("admin")
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+		wrongAudience := claims("admin")
+		wrongAudience["aud"] = "billing"
+
+		for name, token := range map[string]string{
+			"wrong key":      sign(t, jwt.SigningMethodRS256, "rsa", otherKey, claims("admin")),
+			"unknown kid":    sign(t, jwt.SigningMethodRS256, "other", rsaKey, claims("admin")),
+			"expired":        sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, expired),
+			"wrong audience": sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, wrongAudience),
+			"unknown role":   sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims("root")),
+			"hmac":           sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims("admin")),
+		} {
+			_, err := a.Authenticate(incoming(AuthorizationHeader, "Bearer "+token))
+			require.ErrorIs(t, err, ErrInvalidToken, name)
+		}
+	})
+
+	t.Run("should require credentials", func(t *testing.T) {
+		_, err := a.Authenticate(context.Background())
+
+		require.ErrorIs(t, err, ErrMissingCredentials)
+	})
+}
+
+func TestAuthenticator_AuthenticateClientCert(t *testing.T) {
+	a, err := NewAuthenticator(Config{
+		Enabled: true,
+		APIKeys: []APIKeyConfig{{Name: "dashboard", KeySHA256: hash("viewer-key"), Role: RoleViewer}},
+		ClientCerts: []ClientCertConfig{
+			{Subject: "pos", Role: RoleClerk},
+			{Subject: "CN=backoffice,O=Acme", Name: "backoffice", Role: RoleAdmin},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("should map subjects to identities", func(t *testing.T) {
+		id, err := a.Authenticate(withClientCert(context.Background(), pkix.Name{CommonName: "pos", Organization: []string{"Acme"}}, true))
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Subject: "pos", Role: RoleClerk, Method: MethodClientCert}, id)
+
+		id, err = a.Authenticate(withClientCert(context.Background(), pkix.Name{CommonName: "backoffice", Organization: []string{"Acme"}}, true))
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Subject: "backoffice", Role: RoleAdmin, Method: MethodClientCert}, id)
+	})
+
+	t.Run("should prefer explicit credentials", func(t *testing.T) {
+		ctx := withClientCert(incoming(APIKeyHeader, "viewer-key"), pkix.Name{CommonName: "pos"}, true)
+
+		id, err := a.Authenticate(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "dashboard", id.Subject)
+	})
+
+	t.Run("should reject unknown subjects", func(t *testing.T) {
+		_, err := a.Authenticate(withClientCert(context.Background(), pkix.Name{CommonName: "kiosk"}, true))
+
+		require.ErrorIs(t, err, ErrUnknownClientCert)
+	})
+
+	t.Run("should ignore unverified certificates", func(t *testing.T) {
+		_, err := a.Authenticate(withClientCert(context.Background(), pkix.Name{CommonName: "pos"}, false))
+
+		require.ErrorIs(t, err, ErrMissingCredentials)
+	})
+}
+
+func TestAuthenticator_UnaryServerInterceptor(t *testing.T) {
+	a, err := NewAuthenticator(Config{
+		Enabled: true,
+		APIKeys: []APIKeyConfig{
+			{Name: "dashboard", KeySHA256: hash("viewer-key"), Role: RoleViewer},
+			{Name: "pos", KeySHA256: hash("clerk-key"), Role: RoleClerk},
+		},
+		Rules: []RuleConfig{{Method: warehousepb.WarehouseService_ListLowStock_FullMethodName, Role: RoleClerk}},
+	})
+	require.NoError(t, err)
+	interceptor := a.UnaryServerInterceptor()
+
+	call := func(ctx context.Context, method string) (Identity, error) {
+		var id Identity
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			id, _ = IdentityFromContext(ctx)
+			return nil, nil
+		})
+		return id, err
+	}
+
+	t.Run("should attach identity", func(t *testing.T) {
+		id, err := call(incoming(APIKeyHeader, "clerk-key"), warehousepb.WarehouseService_RemoveProduct_FullMethodName)
+
+		require.NoError(t, err)
+		assert.Equal(t, "pos", id.Subject)
+	})
+
+	t.Run("should check the role of the method", func(t *testing.T) {
+		_, err := call(incoming(APIKeyHeader, "viewer-key"), warehousepb.WarehouseService_GetProducts_FullMethodName)
+		require.NoError(t, err)
+
+		_, err = call(incoming(APIKeyHeader, "viewer-key"), warehousepb.WarehouseService_RemoveProduct_FullMethodName)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(incoming(APIKeyHeader, "viewer-key"), warehousepb.WarehouseService_ListLowStock_FullMethodName)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(incoming(APIKeyHeader, "clerk-key"), warehousepb.WarehouseService_UpdateProduct_FullMethodName)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("should reject unauthenticated calls", func(t *testing.T) {
+		_, err := call(context.Background(), warehousepb.WarehouseService_GetProducts_FullMethodName)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should pass all calls if disabled", func(t *testing.T) {
+		disabled, err := NewAuthenticator(Config{})
+		require.NoError(t, err)
+
+		_, err = disabled.UnaryServerInterceptor()(context.Background(), nil,
+			&grpc.UnaryServerInfo{FullMethod: warehousepb.WarehouseService_UpdateProduct_FullMethodName},
+			func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	_, err := NewAuthenticator(Config{APIKeys: []APIKeyConfig{{Name: "pos", KeySHA256: "plain", Role: RoleClerk}}})
+	require.Error(t, err)
+
+	_, err = NewAuthenticator(Config{APIKeys: []APIKeyConfig{{Name: "pos", KeySHA256: hash("key"), Role: "root"}}})
+	require.Error(t, err)
+
+	_, err = NewAuthenticator(Config{ClientCerts: []ClientCertConfig{{Subject: "pos", Role: "root"}}})
+	require.Error(t, err)
+
+	_, err = NewAuthenticator(Config{Rules: []RuleConfig{{Method: "/warehouse.WarehouseService/GetProducts", Role: "guest"}}})
+	require.Error(t, err)
+
+	_, err = NewAuthenticator(Config{Rules: []RuleConfig{{Method: "/warehouse.WarehouseService/GetProduct", Role: RoleViewer}}})
+	require.ErrorContains(t, err, "unknown method")
+
+	_, err = NewAuthenticator(Config{Rules: []RuleConfig{
+		{Method: warehousepb.WebhookService_ListWebhookSubscriptions_FullMethodName, Role: RoleClerk},
+		{Method: grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName, Role: RoleAdmin},
+	}})
+	require.NoError(t, err)
+}
+
+func TestDefaultRules(t *testing.T) {
+	for method := range defaultRules {
+		assert.True(t, knownMethod(method), method)
+	}
+}
+
+func incoming(key, value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(key, value))
+}
+
+func withClientCert(ctx context.Context, subject pkix.Name, verified bool) context.Context {
+	cert := &x509.Certificate{Subject: subject}
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if verified {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func writeJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) string {
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(ecKey.X), "y": encode(ecKey.Y)},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+		},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
@@ -0,0 +1,45 @@
+package auth
+
+type Config struct {
+	// Enabled turns authentication on, all calls are accepted without identity otherwise
+	Enabled bool
+	APIKeys []APIKeyConfig `koanf:"api_keys"`
+	JWT     JWTConfig
+	// ClientCerts identify callers by the subject of their verified TLS client certificate
+	ClientCerts []ClientCertConfig `koanf:"client_certs"`
+	// Rules override the role required to call a method
+	Rules []RuleConfig
+}
+
+type APIKeyConfig struct {
+	// Name identifies the caller, e.g. the client application
+	Name string
+	// KeySHA256 is the hex encoded SHA-256 hash of the key, keys are not stored in the config
+	KeySHA256 string `koanf:"key_sha256"`
+	Role      Role
+}
+
+type JWTConfig struct {
+	// JWKSFile is a JSON Web Key Set with the RSA and EC keys tokens are signed with
+	JWKSFile string `koanf:"jwks_file"`
+	// HMACSecret verifies HS256 tokens, HMAC tokens are rejected if unset
+	HMACSecret string `koanf:"hmac_secret"`
+	Issuer     string
+	Audience   string
+	// RoleClaim is the claim holding the role or a list of roles, role if unset
+	RoleClaim string `koanf:"role_claim"`
+}
+
+type ClientCertConfig struct {
+	// Subject is the common name or the distinguished name of the certificate subject, e.g. CN=pos,O=Acme
+	Subject string
+	// Name identifies the caller, the subject if unset
+	Name string
+	Role Role
+}
+
+type RuleConfig struct {
+	// Method is the full gRPC method name, e.g. /warehouse.WarehouseService/GetProducts
+	Method string
+	Role   Role
+}
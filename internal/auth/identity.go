@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+// Role grants access to RPCs, every role includes the permissions of the roles below it
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleClerk  Role = "clerk"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleClerk:  2,
+	RoleAdmin:  3,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes reports whether the role grants the permissions of the other role
+func (r Role) Includes(other Role) bool {
+	return roleRanks[r] >= roleRanks[other]
+}
+
+const (
+	MethodAPIKey     = "api_key"
+	MethodJWT        = "jwt"
+	MethodClientCert = "client_cert"
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	Subject string
+	Role    Role
+	// Method is the way the caller authenticated, e.g. api_key
+	Method string
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the caller of the request, false if the request is not authenticated
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+
+	"warehouse/api/warehousepb"
+)
+
+// defaultRules is the role required per method: viewers read, clerks move stock and handle orders,
+// admins edit the catalog and the configuration. Methods not listed require admin.
+var defaultRules = map[string]Role{
+	warehousepb.WarehouseService_GetProducts_FullMethodName:            RoleViewer,
+	warehousepb.WarehouseService_GetArticle_FullMethodName:             RoleViewer,
+	warehousepb.WarehouseService_TraceSerial_FullMethodName:            RoleViewer,
+	warehousepb.WarehouseService_PlanProductionMix_FullMethodName:      RoleViewer,
+	warehousepb.WarehouseService_SimulateAvailability_FullMethodName:   RoleViewer,
+	warehousepb.WarehouseService_ListLowStock_FullMethodName:           RoleViewer,
+	warehousepb.WarehouseService_SuggestReplenishment_FullMethodName:   RoleViewer,
+	warehousepb.WarehouseService_ListBackorders_FullMethodName:         RoleViewer,
+	warehousepb.WarehouseService_GeneratePickList_FullMethodName:       RoleViewer,
+	warehousepb.SupplierService_ListSuppliers_FullMethodName:           RoleViewer,
+	warehousepb.SupplierService_ListSupplierArticles_FullMethodName:    RoleViewer,
+	warehousepb.PurchaseOrderService_GetPurchaseOrder_FullMethodName:   RoleViewer,
+	warehousepb.PurchaseOrderService_ListPurchaseOrders_FullMethodName: RoleViewer,
+	warehousepb.OrderService_GetOrder_FullMethodName:                   RoleViewer,
+	warehousepb.OrderService_ListOrders_FullMethodName:                 RoleViewer,
+	warehousepb.StocktakeService_GetStocktake_FullMethodName:           RoleViewer,
+
+	grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName:      RoleViewer,
+	grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: RoleViewer,
+
+	warehousepb.WarehouseService_RemoveProduct_FullMethodName:            RoleClerk,
+	warehousepb.WarehouseService_AddArticleStock_FullMethodName:          RoleClerk,
+	warehousepb.WarehouseService_CreateAssemblyOrder_FullMethodName:      RoleClerk,
+	warehousepb.WarehouseService_CompleteAssemblyOrder_FullMethodName:    RoleClerk,
+	warehousepb.WarehouseService_DisassembleProduct_FullMethodName:       RoleClerk,
+	warehousepb.PurchaseOrderService_ReceivePurchaseOrder_FullMethodName: RoleClerk,
+	warehousepb.OrderService_PlaceOrder_FullMethodName:                   RoleClerk,
+	warehousepb.OrderService_AllocateOrder_FullMethodName:                RoleClerk,
+	warehousepb.OrderService_PickOrder_FullMethodName:                    RoleClerk,
+	warehousepb.OrderService_PackOrder_FullMethodName:                    RoleClerk,
+	warehousepb.OrderService_ShipOrder_FullMethodName:                    RoleClerk,
+	warehousepb.OrderService_CancelOrder_FullMethodName:                  RoleClerk,
+	warehousepb.StocktakeService_OpenStocktake_FullMethodName:            RoleClerk,
+	warehousepb.StocktakeService_SubmitCounts_FullMethodName:             RoleClerk,
+}
+
+// services lists the services registered on the server, rules may only name their methods
+var services = []*grpc.ServiceDesc{
+	&warehousepb.WarehouseService_ServiceDesc,
+	&warehousepb.SupplierService_ServiceDesc,
+	&warehousepb.PurchaseOrderService_ServiceDesc,
+	&warehousepb.OrderService_ServiceDesc,
+	&warehousepb.StocktakeService_ServiceDesc,
+	&warehousepb.WebhookService_ServiceDesc,
+	&grpc_reflection_v1.ServerReflection_ServiceDesc,
+	&grpc_reflection_v1alpha.ServerReflection_ServiceDesc,
+}
+
+// knownMethod checks if the full method name belongs to a registered service
+func knownMethod(method string) bool {
+	for _, desc := range services {
+		for _, m := range desc.Methods {
+			if method == "/"+desc.ServiceName+"/"+m.MethodName {
+				return true
+			}
+		}
+		for _, s := range desc.Streams {
+			if method == "/"+desc.ServiceName+"/"+s.StreamName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rules resolves the role required to call a method
+type rules map[string]Role
+
+func newRules(overrides []RuleConfig) (rules, error) {
+	r := make(rules, len(defaultRules)+len(overrides))
+	for method, role := range defaultRules {
+		r[method] = role
+	}
+	for _, rule := range overrides {
+		if !knownMethod(rule.Method) {
+			return nil, fmt.Errorf("unknown method %q", rule.Method)
+		}
+		if !rule.Role.Valid() {
+			return nil, fmt.Errorf("invalid role %q for method %s", rule.Role, rule.Method)
+		}
+		r[rule.Method] = rule.Role
+	}
+	return r, nil
+}
+
+func (r rules) required(method string) Role {
+	if role, ok := r[method]; ok {
+		return role
+	}
+	return RoleAdmin
+}
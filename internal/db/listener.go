@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"warehouse/internal/logging"
+)
+
+const listenRetryDelay = time.Second
+
+// Listener receives Postgres notifications for all subscribers over a single dedicated connection.
+// The connection is opened outside the pool, so listening never takes connections from queries.
+type Listener struct {
+	connConfig    *pgx.ConnConfig
+	subscriptions []*subscription
+}
+
+func NewListener(connConfig *pgx.ConnConfig) *Listener {
+	return &Listener{
+		connConfig: connConfig,
+	}
+}
+
+// Subscribe calls handle with the payloads notified on the channel, subscriptions must be made before Run.
+// onConnect is called after every (re)connect so that the subscriber can catch up with changes it might have missed.
+// Every subscriber is called from its own goroutine, payloads notified while it is busy are passed
+// in one batch without duplicates.
+func (l *Listener) Subscribe(channel string, onConnect func(ctx context.Context), handle func(ctx context.Context, payloads []string)) {
+	l.subscriptions = append(l.subscriptions, &subscription{
+		channel:   channel,
+		onConnect: onConnect,
+		handle:    handle,
+		pending:   make(map[string]struct{}),
+		signal:    make(chan struct{}, 1),
+	})
+}
+
+// Run listens to the subscribed channels until the context is cancelled, the connection is re-established on errors
+func (l *Listener) Run(ctx context.Context) {
+	if len(l.subscriptions) == 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, s := range l.subscriptions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.run(ctx)
+		}()
+	}
+	defer wg.Wait()
+
+	for ctx.Err() == nil {
+		err := l.listen(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error listening to notifications", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(listenRetryDelay):
+			}
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, l.connConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	channels := make(map[string][]*subscription)
+	for _, s := range l.subscriptions {
+		channels[s.channel] = append(channels[s.channel], s)
+	}
+	for channel := range channels {
+		_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+		if err != nil {
+			return err
+		}
+	}
+	for _, s := range l.subscriptions {
+		s.reconnected()
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range channels[n.Channel] {
+			s.notify(n.Payload)
+		}
+	}
+}
+
+type subscription struct {
+	channel   string
+	onConnect func(ctx context.Context)
+	handle    func(ctx context.Context, payloads []string)
+
+	mu sync.Mutex
+	// connected is set on (re)connect, the pending payloads are covered by onConnect then
+	connected bool
+	pending   map[string]struct{}
+	signal    chan struct{}
+}
+
+func (s *subscription) reconnected() {
+	s.mu.Lock()
+	s.connected = true
+	clear(s.pending)
+	s.mu.Unlock()
+	s.wake()
+}
+
+func (s *subscription) notify(payload string) {
+	s.mu.Lock()
+	s.pending[payload] = struct{}{}
+	s.mu.Unlock()
+	s.wake()
+}
+
+func (s *subscription) wake() {
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.signal:
+		}
+
+		s.mu.Lock()
+		connected := s.connected
+		payloads := make([]string, 0, len(s.pending))
+		for payload := range s.pending {
+			payloads = append(payloads, payload)
+		}
+		s.connected = false
+		clear(s.pending)
+		s.mu.Unlock()
+
+		// a panicking subscriber must neither stop the others nor crash the process
+		task := "subscriber of " + s.channel
+		if connected && s.onConnect != nil {
+			logging.Recover(ctx, task, func() {
+				s.onConnect(ctx)
+			})
+		}
+		if len(payloads) > 0 {
+			logging.Recover(ctx, task, func() {
+				s.handle(ctx, payloads)
+			})
+		}
+	}
+}
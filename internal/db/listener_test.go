@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription(t *testing.T) {
+	type call struct {
+		connect  bool
+		payloads []string
+	}
+
+	start := func(t *testing.T) (*subscription, chan call, chan struct{}) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		calls := make(chan call, 10)
+		block := make(chan struct{})
+
+		l := NewListener(nil)
+		l.Subscribe("changes",
+			func(ctx context.Context) {
+				calls <- call{connect: true}
+			},
+			func(ctx context.Context, payloads []string) {
+				<-block
+				slices.Sort(payloads)
+				calls <- call{payloads: payloads}
+			},
+		)
+		s := l.subscriptions[0]
+		go s.run(ctx)
+		return s, calls, block
+	}
+	next := func(t *testing.T, calls chan call) call {
+		select {
+		case c := <-calls:
+			return c
+		case <-time.After(time.Second):
+			require.FailNow(t, "no call")
+			return call{}
+		}
+	}
+
+	t.Run("should batch payloads notified while busy", func(t *testing.T) {
+		s, calls, block := start(t)
+
+		s.notify("1")
+		time.Sleep(20 * time.Millisecond)
+		s.notify("2")
+		s.notify("3")
+		s.notify("2")
+		close(block)
+
+		assert.Equal(t, call{payloads: []string{"1"}}, next(t, calls))
+		assert.Equal(t, call{payloads: []string{"2", "3"}}, next(t, calls))
+	})
+
+	t.Run("should drop pending payloads on reconnect", func(t *testing.T) {
+		s, calls, block := start(t)
+
+		s.notify("1")
+		time.Sleep(20 * time.Millisecond)
+		s.notify("2")
+		s.reconnected()
+		s.notify("3")
+		close(block)
+
+		assert.Equal(t, call{payloads: []string{"1"}}, next(t, calls))
+		assert.Equal(t, call{connect: true}, next(t, calls))
+		assert.Equal(t, call{payloads: []string{"3"}}, next(t, calls))
+	})
+}
@@ -0,0 +1,2 @@
+//go:generate mockgen -source ../tx.go -destination mock.gen.go -package mockDB
+package mockDB
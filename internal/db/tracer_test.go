@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	t.Run("should trace query", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+			SQL: "\n\t\tUPDATE articles\n\t\tSET stock = stock - $2\n\t\tWHERE id = $1\n\t",
+		})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1")})
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "db UPDATE", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "UPDATE articles\n\t\tSET stock = stock - $2\n\t\tWHERE id = $1"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int64("db.rows_affected", 1))
+	})
+
+	t.Run("should record query error", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "begin"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "db BEGIN", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+}
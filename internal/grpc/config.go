@@ -4,8 +4,25 @@ import "fmt"
 
 type Config struct {
 	Port int
+	TLS  TLSConfig
 }
 
 func (c *Config) Address() string {
 	return fmt.Sprintf(":%d", c.Port)
 }
+
+type TLSConfig struct {
+	// CertFile and KeyFile enable TLS, the server accepts plaintext connections if unset
+	CertFile string `koanf:"cert_file"`
+	KeyFile  string `koanf:"key_file"`
+	// ClientCAFile enables mutual TLS, client certificates are verified against the CAs in the file
+	ClientCAFile string `koanf:"client_ca_file"`
+	// RequireClientCert rejects clients without a certificate, they may authenticate by API key or token otherwise
+	RequireClientCert bool `koanf:"require_client_cert"`
+	// MinVersion is the minimum TLS version, e.g. 1.3, 1.2 if unset
+	MinVersion string `koanf:"min_version"`
+}
+
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
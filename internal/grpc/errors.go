@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"warehouse/internal/models"
+	articlesrepo "warehouse/internal/repositories/articles"
+	assembliesrepo "warehouse/internal/repositories/assemblies"
+	ordersrepo "warehouse/internal/repositories/orders"
+	productsrepo "warehouse/internal/repositories/products"
+	purchaseordersrepo "warehouse/internal/repositories/purchaseorders"
+	salesrepo "warehouse/internal/repositories/sales"
+	serialsrepo "warehouse/internal/repositories/serials"
+	stocktakesrepo "warehouse/internal/repositories/stocktakes"
+	suppliersrepo "warehouse/internal/repositories/suppliers"
+	webhooksrepo "warehouse/internal/repositories/webhooks"
+	"warehouse/internal/services/articles"
+	"warehouse/internal/services/orders"
+	"warehouse/internal/services/products"
+	"warehouse/internal/services/purchaseorders"
+	"warehouse/internal/services/replenishment"
+	"warehouse/internal/services/stocktakes"
+	"warehouse/internal/services/suppliers"
+	"warehouse/internal/services/webhooks"
+	"warehouse/internal/uom"
+)
+
+var errorCodes = map[error]codes.Code{
+	articlesrepo.ErrNotFound:            codes.NotFound,
+	articlesrepo.ErrInsufficientStock:   codes.FailedPrecondition,
+	articlesrepo.ErrVersionConflict:     codes.Aborted,
+	productsrepo.ErrNotFound:            codes.NotFound,
+	productsrepo.ErrVersionConflict:     codes.Aborted,
+	errETagRequired:                     codes.FailedPrecondition,
+	errInvalidETag:                      codes.InvalidArgument,
+	salesrepo.ErrNotFound:               codes.NotFound,
+	serialsrepo.ErrNotFound:             codes.NotFound,
+	serialsrepo.ErrDuplicate:            codes.AlreadyExists,
+	serialsrepo.ErrUnavailable:          codes.FailedPrecondition,
+	articles.ErrInvalidQuantity:         codes.InvalidArgument,
+	articles.ErrSerialsRequired:         codes.InvalidArgument,
+	articles.ErrNotSerialized:           codes.InvalidArgument,
+	articles.ErrInvalidReorder:          codes.InvalidArgument,
+	articles.ErrInvalidName:             codes.InvalidArgument,
+	products.ErrInvalidDemandCap:        codes.InvalidArgument,
+	products.ErrInvalidQuantity:         codes.InvalidArgument,
+	products.ErrInvalidName:             codes.InvalidArgument,
+	products.ErrInvalidPrice:            codes.InvalidArgument,
+	models.ErrInvalidMoney:              codes.InvalidArgument,
+	models.ErrCurrencyMismatch:          codes.FailedPrecondition,
+	products.ErrInsufficientStock:       codes.FailedPrecondition,
+	products.ErrNotAssemblable:          codes.FailedPrecondition,
+	products.ErrAssemblyCompleted:       codes.FailedPrecondition,
+	products.ErrNotDisassemblable:       codes.FailedPrecondition,
+	products.ErrInvalidScrap:            codes.InvalidArgument,
+	products.ErrEmptyPickList:           codes.InvalidArgument,
+	assembliesrepo.ErrNotFound:          codes.NotFound,
+	products.ErrSerialMismatch:          codes.InvalidArgument,
+	suppliersrepo.ErrNotFound:           codes.NotFound,
+	suppliersrepo.ErrArticleNotFound:    codes.FailedPrecondition,
+	suppliers.ErrInvalidName:            codes.InvalidArgument,
+	suppliers.ErrInvalidTerms:           codes.InvalidArgument,
+	purchaseordersrepo.ErrNotFound:      codes.NotFound,
+	purchaseordersrepo.ErrLineNotFound:  codes.NotFound,
+	purchaseordersrepo.ErrOverReceipt:   codes.FailedPrecondition,
+	purchaseorders.ErrInvalidQuantity:   codes.InvalidArgument,
+	purchaseorders.ErrEmptyOrder:        codes.InvalidArgument,
+	purchaseorders.ErrInvalidStatus:     codes.FailedPrecondition,
+	ordersrepo.ErrNotFound:              codes.NotFound,
+	ordersrepo.ErrLineNotFound:          codes.NotFound,
+	orders.ErrInvalidQuantity:           codes.InvalidArgument,
+	orders.ErrEmptyOrder:                codes.InvalidArgument,
+	orders.ErrInvalidStatus:             codes.FailedPrecondition,
+	orders.ErrInsufficientStock:         codes.FailedPrecondition,
+	stocktakesrepo.ErrNotFound:          codes.NotFound,
+	stocktakes.ErrEmptyStocktake:        codes.InvalidArgument,
+	stocktakes.ErrSerializedArticle:     codes.InvalidArgument,
+	stocktakes.ErrInvalidStatus:         codes.FailedPrecondition,
+	stocktakes.ErrInvalidCounter:        codes.InvalidArgument,
+	stocktakes.ErrInvalidQuantity:       codes.InvalidArgument,
+	stocktakes.ErrArticleNotInStocktake: codes.InvalidArgument,
+	webhooksrepo.ErrNotFound:            codes.NotFound,
+	webhooks.ErrInvalidURL:              codes.InvalidArgument,
+	webhooks.ErrSecretRequired:          codes.InvalidArgument,
+	webhooks.ErrInvalidEventType:        codes.InvalidArgument,
+	replenishment.ErrInvalidPolicy:      codes.InvalidArgument,
+	uom.ErrUnknownUnit:                  codes.InvalidArgument,
+	uom.ErrIncompatibleUnit:             codes.InvalidArgument,
+	uom.ErrNotIntegral:                  codes.InvalidArgument,
+}
+
+// toStatus converts known domain errors to gRPC status errors
+func toStatus(err error) error {
+	for target, code := range errorCodes {
+		if errors.Is(err, target) {
+			return status.Error(code, err.Error())
+		}
+	}
+	return err
+}
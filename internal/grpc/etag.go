@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	errETagRequired = errors.New("etag is required")
+	errInvalidETag  = errors.New("invalid etag")
+)
+
+// toETag formats the version as a quoted entity tag
+func toETag(version int32) string {
+	return strconv.Quote(strconv.FormatInt(int64(version), 10))
+}
+
+// fromETag parses the version of an entity tag returned by toETag
+func fromETag(etag string) (int32, error) {
+	if etag == "" {
+		return 0, errETagRequired
+	}
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return 0, errInvalidETag
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 32)
+	if err != nil {
+		return 0, errInvalidETag
+	}
+	return int32(version), nil
+}
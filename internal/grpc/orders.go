@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/orders"
+)
+
+var orderStatuses = map[models.OrderStatus]warehousepb.OrderStatus{
+	models.OrderPlaced:    warehousepb.OrderStatus_ORDER_STATUS_PLACED,
+	models.OrderAllocated: warehousepb.OrderStatus_ORDER_STATUS_ALLOCATED,
+	models.OrderPicking:   warehousepb.OrderStatus_ORDER_STATUS_PICKING,
+	models.OrderPacked:    warehousepb.OrderStatus_ORDER_STATUS_PACKED,
+	models.OrderShipped:   warehousepb.OrderStatus_ORDER_STATUS_SHIPPED,
+	models.OrderCancelled: warehousepb.OrderStatus_ORDER_STATUS_CANCELLED,
+}
+
+type OrderService struct {
+	warehousepb.UnimplementedOrderServiceServer
+	ordersSrv orders.Service
+}
+
+func NewOrderService(ordersSrv orders.Service) *OrderService {
+	return &OrderService{
+		ordersSrv: ordersSrv,
+	}
+}
+
+func (srv *OrderService) PlaceOrder(ctx context.Context, req *warehousepb.PlaceOrderRequest) (*warehousepb.PlaceOrderResponse, error) {
+	lines := make([]models.OrderLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, models.OrderLine{
+			ProductID: line.ProductId,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	order, err := srv.ordersSrv.PlaceOrder(ctx, lines)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.PlaceOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) GetOrder(ctx context.Context, req *warehousepb.GetOrderRequest) (*warehousepb.GetOrderResponse, error) {
+	order, err := srv.ordersSrv.GetOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.GetOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) ListOrders(ctx context.Context, req *warehousepb.ListOrdersRequest) (*warehousepb.ListOrdersResponse, error) {
+	var status models.OrderStatus
+	for s, pbStatus := range orderStatuses {
+		if pbStatus == req.Status {
+			status = s
+		}
+	}
+
+	items, err := srv.ordersSrv.GetOrders(ctx, status)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListOrdersResponse{
+		Items: make([]*warehousepb.Order, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toOrder(item))
+	}
+	return resp, nil
+}
+
+func (srv *OrderService) AllocateOrder(ctx context.Context, req *warehousepb.AllocateOrderRequest) (*warehousepb.AllocateOrderResponse, error) {
+	order, err := srv.ordersSrv.AllocateOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.AllocateOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) PickOrder(ctx context.Context, req *warehousepb.PickOrderRequest) (*warehousepb.PickOrderResponse, error) {
+	order, err := srv.ordersSrv.PickOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.PickOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) PackOrder(ctx context.Context, req *warehousepb.PackOrderRequest) (*warehousepb.PackOrderResponse, error) {
+	order, err := srv.ordersSrv.PackOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.PackOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) ShipOrder(ctx context.Context, req *warehousepb.ShipOrderRequest) (*warehousepb.ShipOrderResponse, error) {
+	order, err := srv.ordersSrv.ShipOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.ShipOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func (srv *OrderService) CancelOrder(ctx context.Context, req *warehousepb.CancelOrderRequest) (*warehousepb.CancelOrderResponse, error) {
+	order, err := srv.ordersSrv.CancelOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CancelOrderResponse{
+		Order: toOrder(order),
+	}, nil
+}
+
+func toOrder(order models.Order) *warehousepb.Order {
+	item := &warehousepb.Order{
+		Id:        order.ID,
+		Status:    orderStatuses[order.Status],
+		Lines:     make([]*warehousepb.Order_Line, 0, len(order.Lines)),
+		CreatedAt: timestamppb.New(order.CreatedAt),
+		UpdatedAt: timestamppb.New(order.UpdatedAt),
+	}
+	for _, line := range order.Lines {
+		pbLine := &warehousepb.Order_Line{
+			Id:                line.ID,
+			ProductId:         line.ProductID,
+			Quantity:          line.Quantity,
+			AssembledQuantity: line.Assembled,
+			Reserved:          make([]*warehousepb.Product_Article, 0, len(line.Reserved)),
+		}
+		for _, art := range line.Reserved {
+			pbLine.Reserved = append(pbLine.Reserved, &warehousepb.Product_Article{
+				Id:       art.ID,
+				Quantity: art.Quantity,
+			})
+		}
+		if line.SaleID != nil {
+			pbLine.SaleId = *line.SaleID
+		}
+		item.Lines = append(item.Lines, pbLine)
+	}
+	return item
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/purchaseorders"
+)
+
+var purchaseOrderStatuses = map[models.PurchaseOrderStatus]warehousepb.PurchaseOrderStatus{
+	models.PurchaseOrderDraft:             warehousepb.PurchaseOrderStatus_PURCHASE_ORDER_STATUS_DRAFT,
+	models.PurchaseOrderSent:              warehousepb.PurchaseOrderStatus_PURCHASE_ORDER_STATUS_SENT,
+	models.PurchaseOrderPartiallyReceived: warehousepb.PurchaseOrderStatus_PURCHASE_ORDER_STATUS_PARTIALLY_RECEIVED,
+	models.PurchaseOrderClosed:            warehousepb.PurchaseOrderStatus_PURCHASE_ORDER_STATUS_CLOSED,
+}
+
+type PurchaseOrderService struct {
+	warehousepb.UnimplementedPurchaseOrderServiceServer
+	purchaseOrdersSrv purchaseorders.Service
+}
+
+func NewPurchaseOrderService(purchaseOrdersSrv purchaseorders.Service) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		purchaseOrdersSrv: purchaseOrdersSrv,
+	}
+}
+
+func (srv *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, req *warehousepb.CreatePurchaseOrderRequest) (*warehousepb.CreatePurchaseOrderResponse, error) {
+	lines := make([]models.PurchaseOrderLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, models.PurchaseOrderLine{
+			ArticleID: line.ArticleId,
+			Quantity:  line.Quantity,
+			Price:     fromMoney(line.Price),
+		})
+	}
+
+	order, err := srv.purchaseOrdersSrv.CreatePurchaseOrder(ctx, req.SupplierId, lines)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CreatePurchaseOrderResponse{
+		PurchaseOrder: toPurchaseOrder(order),
+	}, nil
+}
+
+func (srv *PurchaseOrderService) GetPurchaseOrder(ctx context.Context, req *warehousepb.GetPurchaseOrderRequest) (*warehousepb.GetPurchaseOrderResponse, error) {
+	order, err := srv.purchaseOrdersSrv.GetPurchaseOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.GetPurchaseOrderResponse{
+		PurchaseOrder: toPurchaseOrder(order),
+	}, nil
+}
+
+func (srv *PurchaseOrderService) ListPurchaseOrders(ctx context.Context, req *warehousepb.ListPurchaseOrdersRequest) (*warehousepb.ListPurchaseOrdersResponse, error) {
+	var status models.PurchaseOrderStatus
+	for s, pbStatus := range purchaseOrderStatuses {
+		if pbStatus == req.Status {
+			status = s
+		}
+	}
+
+	items, err := srv.purchaseOrdersSrv.GetPurchaseOrders(ctx, status)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListPurchaseOrdersResponse{
+		Items: make([]*warehousepb.PurchaseOrder, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toPurchaseOrder(item))
+	}
+	return resp, nil
+}
+
+func (srv *PurchaseOrderService) SendPurchaseOrder(ctx context.Context, req *warehousepb.SendPurchaseOrderRequest) (*warehousepb.SendPurchaseOrderResponse, error) {
+	order, err := srv.purchaseOrdersSrv.SendPurchaseOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.SendPurchaseOrderResponse{
+		PurchaseOrder: toPurchaseOrder(order),
+	}, nil
+}
+
+func (srv *PurchaseOrderService) ReceivePurchaseOrder(ctx context.Context, req *warehousepb.ReceivePurchaseOrderRequest) (*warehousepb.ReceivePurchaseOrderResponse, error) {
+	receipts := make([]models.PurchaseOrderReceipt, 0, len(req.Receipts))
+	for _, receipt := range req.Receipts {
+		receipts = append(receipts, models.PurchaseOrderReceipt{
+			LineID:   receipt.LineId,
+			Quantity: receipt.Quantity,
+			Serials:  receipt.Serials,
+		})
+	}
+
+	order, err := srv.purchaseOrdersSrv.ReceivePurchaseOrder(ctx, req.Id, receipts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.ReceivePurchaseOrderResponse{
+		PurchaseOrder: toPurchaseOrder(order),
+	}, nil
+}
+
+func (srv *PurchaseOrderService) ClosePurchaseOrder(ctx context.Context, req *warehousepb.ClosePurchaseOrderRequest) (*warehousepb.ClosePurchaseOrderResponse, error) {
+	order, err := srv.purchaseOrdersSrv.ClosePurchaseOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.ClosePurchaseOrderResponse{
+		PurchaseOrder: toPurchaseOrder(order),
+	}, nil
+}
+
+func toPurchaseOrder(order models.PurchaseOrder) *warehousepb.PurchaseOrder {
+	item := &warehousepb.PurchaseOrder{
+		Id:         order.ID,
+		SupplierId: order.SupplierID,
+		Status:     purchaseOrderStatuses[order.Status],
+		Lines:      make([]*warehousepb.PurchaseOrder_Line, 0, len(order.Lines)),
+		CreatedAt:  timestamppb.New(order.CreatedAt),
+		UpdatedAt:  timestamppb.New(order.UpdatedAt),
+	}
+	for _, line := range order.Lines {
+		item.Lines = append(item.Lines, &warehousepb.PurchaseOrder_Line{
+			Id:               line.ID,
+			ArticleId:        line.ArticleID,
+			Quantity:         line.Quantity,
+			ReceivedQuantity: line.Received,
+			Price:            toMoney(line.Price),
+		})
+	}
+	return item
+}
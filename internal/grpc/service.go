@@ -1,56 +1,509 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/articles"
 	"warehouse/internal/services/products"
+	"warehouse/internal/services/replenishment"
 )
 
 type Service struct {
 	warehousepb.UnimplementedWarehouseServiceServer
-	productsSrv products.Service
+	productsSrv      products.Service
+	articlesSrv      articles.Service
+	replenishmentSrv replenishment.Service
 }
 
-func NewService(productsSrv products.Service) *Service {
+func NewService(productsSrv products.Service, articlesSrv articles.Service, replenishmentSrv replenishment.Service) *Service {
 	return &Service{
-		productsSrv: productsSrv,
+		productsSrv:      productsSrv,
+		articlesSrv:      articlesSrv,
+		replenishmentSrv: replenishmentSrv,
 	}
 }
 
 func (srv *Service) GetProducts(ctx context.Context, _ *warehousepb.GetProductsRequest) (*warehousepb.GetProductsResponse, error) {
 	prodsWithStock, err := srv.productsSrv.GetProductsWithStock(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	resp := &warehousepb.GetProductsResponse{
 		Items: make([]*warehousepb.Product, 0, len(prodsWithStock)),
 	}
 	for _, prod := range prodsWithStock {
-		item := &warehousepb.Product{
-			Id:    prod.ID,
-			Name:  prod.Name,
-			Price: prod.Price,
-			Stock: prod.Stock,
+		resp.Items = append(resp.Items, toProduct(prod))
+	}
+
+	return resp, nil
+}
+
+func (srv *Service) RemoveProduct(ctx context.Context, req *warehousepb.RemoveProductRequest) (*warehousepb.RemoveProductResponse, error) {
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	sale, err := srv.productsSrv.RemoveProduct(ctx, req.Id, quantity, req.Serials, req.AllowBackorder)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.RemoveProductResponse{
+		SaleId:              sale.ID,
+		BackorderedQuantity: sale.Backordered,
+	}, nil
+}
+
+func (srv *Service) ListBackorders(ctx context.Context, req *warehousepb.ListBackordersRequest) (*warehousepb.ListBackordersResponse, error) {
+	items, err := srv.productsSrv.GetBackorders(ctx, req.OpenOnly)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListBackordersResponse{
+		Items: make([]*warehousepb.Backorder, 0, len(items)),
+	}
+	for _, item := range items {
+		backorder := &warehousepb.Backorder{
+			Id:                item.ID,
+			SaleId:            item.SaleID,
+			ProductId:         item.ProductID,
+			Quantity:          item.Quantity,
+			FulfilledQuantity: item.Fulfilled,
+			CreatedAt:         timestamppb.New(item.CreatedAt),
 		}
-		item.Articles = make([]*warehousepb.Product_Article, 0, len(prod.Articles))
-		for _, art := range prod.Articles {
-			item.Articles = append(item.Articles, &warehousepb.Product_Article{
-				Id:       art.ID,
-				Quantity: art.Quantity,
-			})
+		if item.FulfilledAt != nil {
+			backorder.FulfilledAt = timestamppb.New(*item.FulfilledAt)
 		}
-		resp.Items = append(resp.Items, item)
+		resp.Items = append(resp.Items, backorder)
 	}
+	return resp, nil
+}
 
+func (srv *Service) AddArticleStock(ctx context.Context, req *warehousepb.AddArticleStockRequest) (*warehousepb.AddArticleStockResponse, error) {
+	err := srv.articlesSrv.AddStock(ctx, req.Id, req.Quantity, req.Serials)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.AddArticleStockResponse{}, nil
+}
+
+func (srv *Service) TraceSerial(ctx context.Context, req *warehousepb.TraceSerialRequest) (*warehousepb.TraceSerialResponse, error) {
+	trace, err := srv.articlesSrv.TraceSerial(ctx, req.Serial)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.TraceSerialResponse{
+		Serial:     trace.Serial,
+		ArticleId:  trace.ArticleID,
+		ReceivedAt: timestamppb.New(trace.ReceivedAt),
+	}
+	if trace.Sale != nil {
+		resp.Sale = &warehousepb.Sale{
+			Id:        trace.Sale.ID,
+			ProductId: trace.Sale.ProductID,
+			Quantity:  trace.Sale.Quantity,
+			CreatedAt: timestamppb.New(trace.Sale.CreatedAt),
+		}
+	}
 	return resp, nil
 }
 
-func (srv *Service) RemoveProduct(ctx context.Context, req *warehousepb.RemoveProductRequest) (*warehousepb.RemoveProductResponse, error) {
-	err := srv.productsSrv.RemoveProduct(ctx, req.Id, 1)
+func (srv *Service) PlanProductionMix(ctx context.Context, req *warehousepb.PlanProductionMixRequest) (*warehousepb.PlanProductionMixResponse, error) {
+	caps := make(map[int32]int32, len(req.DemandCaps))
+	for _, c := range req.DemandCaps {
+		caps[c.ProductId] = c.MaxQuantity
+	}
+	objective := models.MixObjectiveRevenue
+	if req.Objective == warehousepb.MixObjective_MIX_OBJECTIVE_UNITS {
+		objective = models.MixObjectiveUnits
+	}
+
+	mix, err := srv.productsSrv.PlanProductionMix(ctx, objective, caps)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.PlanProductionMixResponse{
+		Items:            make([]*warehousepb.PlanProductionMixResponse_Item, 0, len(mix.Items)),
+		Units:            mix.Units,
+		LeftoverArticles: make([]*warehousepb.PlanProductionMixResponse_Leftover, 0, len(mix.Leftover)),
+		Optimal:          mix.Optimal,
+	}
+	if mix.Revenue.Currency != "" {
+		resp.Revenue = toMoney(mix.Revenue)
+	}
+	for _, item := range mix.Items {
+		resp.Items = append(resp.Items, &warehousepb.PlanProductionMixResponse_Item{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+			Revenue:   toMoney(item.Revenue),
+		})
+	}
+	for _, art := range mix.Leftover {
+		resp.LeftoverArticles = append(resp.LeftoverArticles, &warehousepb.PlanProductionMixResponse_Leftover{
+			ArticleId: art.ID,
+			Quantity:  art.Quantity,
+		})
+	}
+	return resp, nil
+}
+
+func (srv *Service) SimulateAvailability(ctx context.Context, req *warehousepb.SimulateAvailabilityRequest) (*warehousepb.SimulateAvailabilityResponse, error) {
+	var sim models.Simulation
+	for _, sale := range req.Sales {
+		sim.Sales = append(sim.Sales, models.ProductSale{
+			ProductID: sale.ProductId,
+			Quantity:  sale.Quantity,
+		})
+	}
+	for _, receipt := range req.Receipts {
+		sim.Receipts = append(sim.Receipts, models.ProductArticle{
+			ID:       receipt.ArticleId,
+			Quantity: receipt.Quantity,
+			Unit:     receipt.Unit,
+		})
+	}
+	for _, change := range req.BomChanges {
+		sim.BOMChanges = append(sim.BOMChanges, models.Product{
+			ID:       change.ProductId,
+			Articles: fromProductArticles(change.Articles),
+		})
+	}
+
+	result, err := srv.productsSrv.SimulateAvailability(ctx, sim)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.SimulateAvailabilityResponse{
+		Products: make([]*warehousepb.Product, 0, len(result.Products)),
+		Articles: make([]*warehousepb.SimulateAvailabilityResponse_ArticleBalance, 0, len(result.Articles)),
+	}
+	for _, prod := range result.Products {
+		resp.Products = append(resp.Products, toProduct(prod))
+	}
+	for _, art := range result.Articles {
+		resp.Articles = append(resp.Articles, &warehousepb.SimulateAvailabilityResponse_ArticleBalance{
+			ArticleId:   art.ArticleID,
+			Name:        art.Name,
+			StockBefore: art.Before,
+			StockAfter:  art.After,
+		})
+	}
+	return resp, nil
+}
+
+func (srv *Service) UpdateReorderPolicy(ctx context.Context, req *warehousepb.UpdateReorderPolicyRequest) (*warehousepb.UpdateReorderPolicyResponse, error) {
+	version, err := fromETag(req.Etag)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	art, err := srv.articlesSrv.UpdateReorderPolicy(ctx, req.ArticleId, version, req.ReorderPoint, req.ReorderQuantity)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.UpdateReorderPolicyResponse{
+		Article: toArticle(art),
+	}, nil
+}
+
+func (srv *Service) GetArticle(ctx context.Context, req *warehousepb.GetArticleRequest) (*warehousepb.GetArticleResponse, error) {
+	art, err := srv.articlesSrv.GetArticle(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.GetArticleResponse{
+		Article: toArticle(art),
+	}, nil
+}
+
+func (srv *Service) UpdateArticle(ctx context.Context, req *warehousepb.UpdateArticleRequest) (*warehousepb.UpdateArticleResponse, error) {
+	version, err := fromETag(req.Etag)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	art, err := srv.articlesSrv.UpdateArticle(ctx, models.Article{
+		ID:              req.Id,
+		Name:            req.Name,
+		ReorderPoint:    req.ReorderPoint,
+		ReorderQuantity: req.ReorderQuantity,
+		Version:         version,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.UpdateArticleResponse{
+		Article: toArticle(art),
+	}, nil
+}
+
+func (srv *Service) UpdateProduct(ctx context.Context, req *warehousepb.UpdateProductRequest) (*warehousepb.UpdateProductResponse, error) {
+	version, err := fromETag(req.Etag)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	prod, err := srv.productsSrv.UpdateProduct(ctx, models.Product{
+		ID:       req.Id,
+		Name:     req.Name,
+		Price:    fromMoney(req.Price),
+		Articles: fromProductArticles(req.Articles),
+		Version:  version,
+	})
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
+	}
+	return &warehousepb.UpdateProductResponse{
+		Product: toProduct(prod),
+	}, nil
+}
+
+func (srv *Service) ListLowStock(ctx context.Context, _ *warehousepb.ListLowStockRequest) (*warehousepb.ListLowStockResponse, error) {
+	arts, err := srv.articlesSrv.ListLowStock(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListLowStockResponse{
+		Items: make([]*warehousepb.Article, 0, len(arts)),
+	}
+	for _, art := range arts {
+		resp.Items = append(resp.Items, toArticle(art))
+	}
+	return resp, nil
+}
+
+func (srv *Service) SuggestReplenishment(ctx context.Context, req *warehousepb.SuggestReplenishmentRequest) (*warehousepb.SuggestReplenishmentResponse, error) {
+	result, err := srv.replenishmentSrv.SuggestReplenishment(ctx, models.ReplenishmentPolicy{
+		WindowDays:      req.WindowDays,
+		SafetyStockDays: req.SafetyStockDays,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.SuggestReplenishmentResponse{
+		Items:             make([]*warehousepb.SuggestReplenishmentResponse_Suggestion, 0, len(result.Suggestions)),
+		SkippedProductIds: result.SkippedProductIDs,
+	}
+	for _, item := range result.Suggestions {
+		suggestion := &warehousepb.SuggestReplenishmentResponse_Suggestion{
+			ArticleId:        item.ArticleID,
+			Name:             item.Name,
+			Unit:             item.Unit,
+			Stock:            item.Stock,
+			OnOrder:          item.OnOrder,
+			DailyConsumption: item.DailyConsumption,
+			LeadTimeDays:     item.LeadTimeDays,
+			SafetyStock:      item.SafetyStock,
+			ReorderLevel:     item.ReorderLevel,
+			Quantity:         item.Quantity,
+		}
+		if item.Source != nil {
+			suggestion.Source = toSupplierArticle(*item.Source)
+		}
+		resp.Items = append(resp.Items, suggestion)
+	}
+	if req.Csv {
+		var buf bytes.Buffer
+		err = replenishment.WriteCSV(&buf, result.Suggestions)
+		if err != nil {
+			return nil, err
+		}
+		resp.Csv = buf.String()
+	}
+	return resp, nil
+}
+
+func (srv *Service) CreateAssemblyOrder(ctx context.Context, req *warehousepb.CreateAssemblyOrderRequest) (*warehousepb.CreateAssemblyOrderResponse, error) {
+	order, err := srv.productsSrv.CreateAssemblyOrder(ctx, req.ProductId, req.Quantity)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CreateAssemblyOrderResponse{
+		AssemblyOrder: toAssemblyOrder(order),
+	}, nil
+}
+
+func (srv *Service) CompleteAssemblyOrder(ctx context.Context, req *warehousepb.CompleteAssemblyOrderRequest) (*warehousepb.CompleteAssemblyOrderResponse, error) {
+	order, err := srv.productsSrv.CompleteAssemblyOrder(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CompleteAssemblyOrderResponse{
+		AssemblyOrder: toAssemblyOrder(order),
+	}, nil
+}
+
+func (srv *Service) DisassembleProduct(ctx context.Context, req *warehousepb.DisassembleProductRequest) (*warehousepb.DisassembleProductResponse, error) {
+	disassembly, err := srv.productsSrv.DisassembleProduct(
+		ctx,
+		req.ProductId,
+		req.Quantity,
+		req.FromAssembled,
+		fromProductArticles(req.Scrap),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.DisassembleProductResponse{
+		DisassemblyId: disassembly.ID,
+		Components:    make([]*warehousepb.DisassembleProductResponse_Component, 0, len(disassembly.Components)),
+	}
+	for _, c := range disassembly.Components {
+		resp.Components = append(resp.Components, &warehousepb.DisassembleProductResponse_Component{
+			ArticleId:         c.ArticleID,
+			ExpectedQuantity:  c.Expected,
+			RecoveredQuantity: c.Recovered,
+			ScrappedQuantity:  c.Scrapped,
+		})
+	}
+	return resp, nil
+}
+
+func (srv *Service) GeneratePickList(ctx context.Context, req *warehousepb.GeneratePickListRequest) (*warehousepb.GeneratePickListResponse, error) {
+	lines := make([]models.ProductSale, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, models.ProductSale{
+			ProductID: line.ProductId,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	list, err := srv.productsSrv.GeneratePickList(ctx, lines)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.GeneratePickListResponse{
+		Items: make([]*warehousepb.GeneratePickListResponse_Item, 0, len(list.Items)),
+	}
+	for _, item := range list.Items {
+		resp.Items = append(resp.Items, &warehousepb.GeneratePickListResponse_Item{
+			ArticleId:  item.ArticleID,
+			Name:       item.Name,
+			Unit:       item.Unit,
+			Quantity:   item.Quantity,
+			OnHand:     item.OnHand,
+			Shortage:   item.Shortage,
+			Missing:    item.Missing,
+			ProductIds: item.ProductIDs,
+		})
+	}
+	if req.Text {
+		var buf bytes.Buffer
+		err = products.WritePickListText(&buf, list)
+		if err != nil {
+			return nil, err
+		}
+		resp.Text = buf.String()
+	}
+	if req.Csv {
+		var buf bytes.Buffer
+		err = products.WritePickListCSV(&buf, list)
+		if err != nil {
+			return nil, err
+		}
+		resp.Csv = buf.String()
+	}
+	return resp, nil
+}
+
+func toAssemblyOrder(order models.AssemblyOrder) *warehousepb.AssemblyOrder {
+	item := &warehousepb.AssemblyOrder{
+		Id:        order.ID,
+		ProductId: order.ProductID,
+		Quantity:  order.Quantity,
+		Status:    warehousepb.AssemblyOrderStatus_ASSEMBLY_ORDER_STATUS_OPEN,
+		CreatedAt: timestamppb.New(order.CreatedAt),
+	}
+	if order.Status == models.AssemblyOrderCompleted {
+		item.Status = warehousepb.AssemblyOrderStatus_ASSEMBLY_ORDER_STATUS_COMPLETED
+	}
+	if order.CompletedAt != nil {
+		item.CompletedAt = timestamppb.New(*order.CompletedAt)
+	}
+	return item
+}
+
+func toArticle(art models.Article) *warehousepb.Article {
+	return &warehousepb.Article{
+		Id:              art.ID,
+		Name:            art.Name,
+		Stock:           art.Stock,
+		Unit:            art.Unit,
+		Serialized:      art.Serialized,
+		ReorderPoint:    art.ReorderPoint,
+		ReorderQuantity: art.ReorderQuantity,
+		Reserved:        art.Reserved,
+		Etag:            toETag(art.Version),
+	}
+}
+
+func toProduct(prod models.ProductWithStock) *warehousepb.Product {
+	item := &warehousepb.Product{
+		Id:        prod.ID,
+		Name:      prod.Name,
+		Price:     toMoney(prod.Price),
+		Stock:     prod.Stock,
+		Assembled: prod.Assembled,
+		Buildable: prod.Buildable,
+		Etag:      toETag(prod.Version),
+	}
+	item.Articles = make([]*warehousepb.Product_Article, 0, len(prod.Articles))
+	for _, art := range prod.Articles {
+		item.Articles = append(item.Articles, &warehousepb.Product_Article{
+			Id:       art.ID,
+			Quantity: art.Quantity,
+			Unit:     art.Unit,
+		})
+	}
+	item.Components = make([]*warehousepb.Product_Component, 0, len(prod.Components))
+	for _, c := range prod.Components {
+		item.Components = append(item.Components, &warehousepb.Product_Component{
+			ArticleId:        c.ArticleID,
+			RequiredQuantity: c.Required,
+			OnHand:           c.OnHand,
+			Buildable:        c.Buildable,
+			Missing:          c.Missing,
+			Bottleneck:       c.Bottleneck,
+		})
+	}
+	item.BottleneckArticleId = prod.BottleneckArticleID
+	item.MissingArticleIds = prod.MissingArticleIDs
+	return item
+}
+
+func fromProductArticles(items []*warehousepb.Product_Article) []models.ProductArticle {
+	arts := make([]models.ProductArticle, 0, len(items))
+	for _, art := range items {
+		arts = append(arts, models.ProductArticle{
+			ID:       art.Id,
+			Quantity: art.Quantity,
+			Unit:     art.Unit,
+		})
+	}
+	return arts
+}
+
+func toMoney(m models.Money) *warehousepb.Money {
+	return &warehousepb.Money{
+		CurrencyCode: m.Currency,
+		AmountMinor:  m.Amount,
+	}
+}
+
+// fromMoney converts the message to money, a nil message is an empty amount
+func fromMoney(m *warehousepb.Money) models.Money {
+	return models.Money{
+		Amount:   m.GetAmountMinor(),
+		Currency: m.GetCurrencyCode(),
 	}
-	return &warehousepb.RemoveProductResponse{}, nil
 }
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	articlesrepo "warehouse/internal/repositories/articles"
+	productsrepo "warehouse/internal/repositories/products"
+	"warehouse/internal/services/articles"
+	"warehouse/internal/services/articles/mock"
+	"warehouse/internal/services/products"
+	"warehouse/internal/services/products/mock"
+)
+
+func TestService_UpdateArticle(t *testing.T) {
+	t.Run("should pass the etag version", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesSrv.EXPECT().UpdateArticle(fx.ctx, models.Article{ID: 1, Name: "leg", Version: 3}).
+			Return(models.Article{ID: 1, Name: "leg", Version: 4}, nil)
+
+		resp, err := fx.UpdateArticle(fx.ctx, &warehousepb.UpdateArticleRequest{Id: 1, Name: "leg", Etag: `"3"`})
+
+		require.NoError(t, err)
+		assert.Equal(t, `"4"`, resp.Article.Etag)
+	})
+
+	t.Run("should abort on stale etag", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesSrv.EXPECT().UpdateArticle(fx.ctx, gomock.Any()).Return(models.Article{}, articlesrepo.ErrVersionConflict)
+
+		_, err := fx.UpdateArticle(fx.ctx, &warehousepb.UpdateArticleRequest{Id: 1, Name: "leg", Etag: `"3"`})
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("should reject missing and invalid etags", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.UpdateArticle(fx.ctx, &warehousepb.UpdateArticleRequest{Id: 1, Name: "leg"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = fx.UpdateArticle(fx.ctx, &warehousepb.UpdateArticleRequest{Id: 1, Name: "leg", Etag: "3"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestService_UpdateProduct(t *testing.T) {
+	t.Run("should abort on stale etag", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsSrv.EXPECT().UpdateProduct(fx.ctx, gomock.Any()).
+			Return(models.ProductWithStock{}, fmt.Errorf("failed to update product: %w", productsrepo.ErrVersionConflict))
+
+		_, err := fx.UpdateProduct(fx.ctx, &warehousepb.UpdateProductRequest{Id: 1, Name: "chair", Etag: `"3"`})
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+}
+
+func TestToStatus(t *testing.T) {
+	for err, code := range map[error]codes.Code{
+		articles.ErrInvalidName:                        codes.InvalidArgument,
+		products.ErrInvalidName:                        codes.InvalidArgument,
+		products.ErrInvalidPrice:                       codes.InvalidArgument,
+		models.ErrInvalidMoney:                         codes.InvalidArgument,
+		products.ErrInvalidQuantity:                    codes.InvalidArgument,
+		articlesrepo.ErrNotFound:                       codes.NotFound,
+		fmt.Errorf("unexpected: %w", context.Canceled): codes.Unknown,
+	} {
+		assert.Equal(t, code, status.Code(toStatus(err)), err.Error())
+	}
+}
+
+type fixture struct {
+	*Service
+
+	t           *testing.T
+	ctx         context.Context
+	productsSrv *mockProductsSrv.MockService
+	articlesSrv *mockArticlesSrv.MockService
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:           t,
+		ctx:         context.Background(),
+		productsSrv: mockProductsSrv.NewMockService(ctrl),
+		articlesSrv: mockArticlesSrv.NewMockService(ctrl),
+	}
+	fx.Service = NewService(fx.productsSrv, fx.articlesSrv, nil)
+	return fx
+}
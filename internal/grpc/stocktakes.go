@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/stocktakes"
+)
+
+var stocktakeStatuses = map[models.StocktakeStatus]warehousepb.StocktakeStatus{
+	models.StocktakeOpen:      warehousepb.StocktakeStatus_STOCKTAKE_STATUS_OPEN,
+	models.StocktakeApproved:  warehousepb.StocktakeStatus_STOCKTAKE_STATUS_APPROVED,
+	models.StocktakeCancelled: warehousepb.StocktakeStatus_STOCKTAKE_STATUS_CANCELLED,
+}
+
+type StocktakeService struct {
+	warehousepb.UnimplementedStocktakeServiceServer
+	stocktakesSrv stocktakes.Service
+}
+
+func NewStocktakeService(stocktakesSrv stocktakes.Service) *StocktakeService {
+	return &StocktakeService{
+		stocktakesSrv: stocktakesSrv,
+	}
+}
+
+func (srv *StocktakeService) OpenStocktake(ctx context.Context, req *warehousepb.OpenStocktakeRequest) (*warehousepb.OpenStocktakeResponse, error) {
+	item, err := srv.stocktakesSrv.OpenStocktake(ctx, req.ArticleIds)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.OpenStocktakeResponse{
+		Stocktake: toStocktake(item),
+	}, nil
+}
+
+func (srv *StocktakeService) GetStocktake(ctx context.Context, req *warehousepb.GetStocktakeRequest) (*warehousepb.GetStocktakeResponse, error) {
+	item, err := srv.stocktakesSrv.GetStocktake(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.GetStocktakeResponse{
+		Stocktake: toStocktake(item),
+	}, nil
+}
+
+func (srv *StocktakeService) SubmitCounts(ctx context.Context, req *warehousepb.SubmitCountsRequest) (*warehousepb.SubmitCountsResponse, error) {
+	counts := make([]models.StocktakeCount, 0, len(req.Counts))
+	for _, count := range req.Counts {
+		counts = append(counts, models.StocktakeCount{
+			ArticleID: count.ArticleId,
+			Quantity:  count.Quantity,
+		})
+	}
+
+	item, err := srv.stocktakesSrv.SubmitCounts(ctx, req.Id, req.Counter, counts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.SubmitCountsResponse{
+		Stocktake: toStocktake(item),
+	}, nil
+}
+
+func (srv *StocktakeService) ApproveStocktake(ctx context.Context, req *warehousepb.ApproveStocktakeRequest) (*warehousepb.ApproveStocktakeResponse, error) {
+	item, err := srv.stocktakesSrv.ApproveStocktake(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.ApproveStocktakeResponse{
+		Stocktake: toStocktake(item),
+	}, nil
+}
+
+func (srv *StocktakeService) CancelStocktake(ctx context.Context, req *warehousepb.CancelStocktakeRequest) (*warehousepb.CancelStocktakeResponse, error) {
+	item, err := srv.stocktakesSrv.CancelStocktake(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CancelStocktakeResponse{
+		Stocktake: toStocktake(item),
+	}, nil
+}
+
+func toStocktake(stocktake models.Stocktake) *warehousepb.Stocktake {
+	item := &warehousepb.Stocktake{
+		Id:        stocktake.ID,
+		Status:    stocktakeStatuses[stocktake.Status],
+		Articles:  make([]*warehousepb.Stocktake_Article, 0, len(stocktake.Articles)),
+		CreatedAt: timestamppb.New(stocktake.CreatedAt),
+	}
+	if stocktake.ClosedAt != nil {
+		item.ClosedAt = timestamppb.New(*stocktake.ClosedAt)
+	}
+	for _, article := range stocktake.Articles {
+		pbArticle := &warehousepb.Stocktake_Article{
+			ArticleId:        article.ArticleID,
+			ExpectedQuantity: article.Expected,
+			Stock:            article.Stock,
+			Counts:           make([]*warehousepb.Stocktake_Count, 0, len(article.Counts)),
+			Variance:         article.Variance(),
+			Adjustment:       article.Adjustment,
+		}
+		for _, count := range article.Counts {
+			pbArticle.Counts = append(pbArticle.Counts, &warehousepb.Stocktake_Count{
+				Counter:   count.Counter,
+				Quantity:  count.Quantity,
+				Stock:     count.Stock,
+				CountedAt: timestamppb.New(count.CountedAt),
+			})
+		}
+		if counted, ok := article.Counted(); ok {
+			pbArticle.CountedQuantity = &counted
+		}
+		item.Articles = append(item.Articles, pbArticle)
+	}
+	return item
+}
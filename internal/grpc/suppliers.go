@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/suppliers"
+)
+
+type SupplierService struct {
+	warehousepb.UnimplementedSupplierServiceServer
+	suppliersSrv suppliers.Service
+}
+
+func NewSupplierService(suppliersSrv suppliers.Service) *SupplierService {
+	return &SupplierService{
+		suppliersSrv: suppliersSrv,
+	}
+}
+
+func (srv *SupplierService) CreateSupplier(ctx context.Context, req *warehousepb.CreateSupplierRequest) (*warehousepb.CreateSupplierResponse, error) {
+	supplier, err := srv.suppliersSrv.CreateSupplier(ctx, req.Name)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CreateSupplierResponse{
+		Supplier: toSupplier(supplier),
+	}, nil
+}
+
+func (srv *SupplierService) ListSuppliers(ctx context.Context, _ *warehousepb.ListSuppliersRequest) (*warehousepb.ListSuppliersResponse, error) {
+	items, err := srv.suppliersSrv.GetSuppliers(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListSuppliersResponse{
+		Items: make([]*warehousepb.Supplier, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toSupplier(item))
+	}
+	return resp, nil
+}
+
+func (srv *SupplierService) SetSupplierArticle(ctx context.Context, req *warehousepb.SetSupplierArticleRequest) (*warehousepb.SetSupplierArticleResponse, error) {
+	art := req.GetArticle()
+	err := srv.suppliersSrv.SetSupplierArticle(ctx, models.SupplierArticle{
+		SupplierID:   art.GetSupplierId(),
+		ArticleID:    art.GetArticleId(),
+		SKU:          art.GetSku(),
+		Price:        fromMoney(art.GetPrice()),
+		LeadTimeDays: art.GetLeadTimeDays(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.SetSupplierArticleResponse{}, nil
+}
+
+func (srv *SupplierService) ListSupplierArticles(ctx context.Context, req *warehousepb.ListSupplierArticlesRequest) (*warehousepb.ListSupplierArticlesResponse, error) {
+	items, err := srv.suppliersSrv.GetSupplierArticles(ctx, req.SupplierId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListSupplierArticlesResponse{
+		Items: make([]*warehousepb.SupplierArticle, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toSupplierArticle(item))
+	}
+	return resp, nil
+}
+
+func toSupplier(item models.Supplier) *warehousepb.Supplier {
+	return &warehousepb.Supplier{
+		Id:   item.ID,
+		Name: item.Name,
+	}
+}
+
+func toSupplierArticle(item models.SupplierArticle) *warehousepb.SupplierArticle {
+	return &warehousepb.SupplierArticle{
+		SupplierId:   item.SupplierID,
+		ArticleId:    item.ArticleID,
+		Sku:          item.SKU,
+		Price:        toMoney(item.Price),
+		LeadTimeDays: item.LeadTimeDays,
+	}
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"warehouse/internal/logging"
+)
+
+// reloadDelay lets writes to the certificate files settle before they are reloaded
+const reloadDelay = 100 * time.Millisecond
+
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Certificates serves the server TLS configuration from the certificate files,
+// every handshake uses the certificates last loaded from disk
+type Certificates struct {
+	cfg        TLSConfig
+	minVersion uint16
+	current    atomic.Pointer[tls.Config]
+
+	mu     sync.Mutex
+	loaded [][]byte
+}
+
+func NewCertificates(cfg TLSConfig) (*Certificates, error) {
+	minVersion, ok := tlsVersions[cfg.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("invalid tls min version %q", cfg.MinVersion)
+	}
+	c := &Certificates{cfg: cfg, minVersion: minVersion}
+	_, err := c.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the certificate files, reporting whether they changed since the last load.
+// The certificates in use are kept if the files are invalid.
+func (c *Certificates) Reload() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files := c.files()
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		contents[i] = data
+	}
+	if c.unchanged(contents) {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(contents[0], contents[1])
+	if err != nil {
+		return false, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   c.minVersion,
+		NextProtos:   []string{"h2"},
+	}
+	if c.cfg.ClientCAFile != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(contents[2]) {
+			return false, fmt.Errorf("no certificates found in %s", c.cfg.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if c.cfg.RequireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	c.current.Store(config)
+	c.loaded = contents
+	return true, nil
+}
+
+// TLSConfig returns the server configuration, it resolves the certificates on every handshake
+func (c *Certificates) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: c.minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return c.current.Load(), nil
+		},
+	}
+}
+
+// Watch reloads the certificates when the files change until the context is done.
+// The directories of the files are watched, so files replaced by a rename or a symlink swap,
+// e.g. mounted Kubernetes secrets, are reloaded too.
+func (c *Certificates) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dirs := make(map[string]bool)
+	for _, file := range c.files() {
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		err = watcher.Add(dir)
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	logger := logging.FromContext(ctx)
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			timer.Reset(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Error("error watching tls certificates", "error", err)
+		case <-timer.C:
+			changed, err := c.Reload()
+			if err != nil {
+				logger.Error("failed to reload tls certificates", "error", err)
+			} else if changed {
+				logger.Info("tls certificates reloaded")
+			}
+		}
+	}
+}
+
+func (c *Certificates) files() []string {
+	files := []string{c.cfg.CertFile, c.cfg.KeyFile}
+	if c.cfg.ClientCAFile != "" {
+		files = append(files, c.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (c *Certificates) unchanged(contents [][]byte) bool {
+	if len(c.loaded) != len(contents) {
+		return false
+	}
+	for i := range contents {
+		if !bytes.Equal(c.loaded[i], contents[i]) {
+			return false
+		}
+	}
+	return true
+}
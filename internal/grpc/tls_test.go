@@ -0,0 +1,214 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", nil)
+	writeTestFile(t, dir, "ca.pem", ca.certPEM)
+	server := newTestCert(t, "localhost", &ca)
+	writeTestFile(t, dir, "cert.pem", server.certPEM)
+	writeTestFile(t, dir, "key.pem", server.keyPEM)
+
+	certs, err := NewCertificates(TLSConfig{
+		CertFile:     filepath.Join(dir, "cert.pem"),
+		KeyFile:      filepath.Join(dir, "key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+		MinVersion:   "1.3",
+	})
+	require.NoError(t, err)
+	addr := serveTLS(t, certs.TLSConfig())
+
+	t.Run("should verify client certificates", func(t *testing.T) {
+		client := newTestCert(t, "pos", &ca)
+
+		state, err := handshake(addr, &client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), state.Version)
+		assert.Equal(t, "localhost", state.PeerCertificates[0].Subject.CommonName)
+	})
+
+	t.Run("should reject client certificates of other CAs", func(t *testing.T) {
+		other := newTestCert(t, "other", nil)
+		client := newTestCert(t, "pos", &other)
+
+		_, err := handshake(addr, &client)
+
+		require.Error(t, err)
+	})
+
+	t.Run("should accept clients without certificate", func(t *testing.T) {
+		_, err := handshake(addr, nil)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep certificates if files are invalid", func(t *testing.T) {
+		writeTestFile(t, dir, "key.pem", newTestCert(t, "localhost", &ca).keyPEM)
+
+		changed, err := certs.Reload()
+
+		require.Error(t, err)
+		assert.False(t, changed)
+		_, err = handshake(addr, nil)
+		require.NoError(t, err)
+		writeTestFile(t, dir, "key.pem", server.keyPEM)
+	})
+
+	t.Run("should reload changed files", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go certs.Watch(ctx)
+		time.Sleep(50 * time.Millisecond)
+
+		renewed := newTestCert(t, "renewed", &ca)
+		writeTestFile(t, dir, "key.pem", renewed.keyPEM)
+		writeTestFile(t, dir, "cert.pem", renewed.certPEM)
+
+		assert.Eventually(t, func() bool {
+			state, err := handshake(addr, nil)
+			return err == nil && state.PeerCertificates[0].Subject.CommonName == "renewed"
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func TestNewCertificates(t *testing.T) {
+	dir := t.TempDir()
+	server := newTestCert(t, "localhost", nil)
+	writeTestFile(t, dir, "cert.pem", server.certPEM)
+	writeTestFile(t, dir, "key.pem", server.keyPEM)
+	cfg := TLSConfig{CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem")}
+
+	_, err := NewCertificates(cfg)
+	require.NoError(t, err)
+
+	invalid := cfg
+	invalid.MinVersion = "1.4"
+	_, err = NewCertificates(invalid)
+	require.Error(t, err)
+
+	invalid = cfg
+	invalid.ClientCAFile = filepath.Join(dir, "key.pem")
+	_, err = NewCertificates(invalid)
+	require.Error(t, err)
+
+	invalid = cfg
+	invalid.KeyFile = filepath.Join(dir, "missing.pem")
+	_, err = NewCertificates(invalid)
+	require.Error(t, err)
+}
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert creates a CA certificate if the issuer is nil, a leaf certificate signed by the issuer otherwise
+func newTestCert(t *testing.T, cn string, issuer *testCert) testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+// serveTLS accepts connections until the test ends, completing the handshake of each
+func serveTLS(t *testing.T, cfg *tls.Config) string {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+				_, _ = conn.Read(make([]byte, 1))
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// handshake connects to the server and returns the connection state once the server accepted the handshake
+func handshake(addr string, client *testCert) (tls.ConnectionState, error) {
+	// the tests check the served certificate themselves
+	cfg := &tls.Config{InsecureSkipVerify: true}
+	if client != nil {
+		// sent even if the server does not accept its CA, tls.Config.Certificates would be filtered
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &tls.Certificate{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}, nil
+		}
+	}
+
+	conn, err := tls.Dial("tcp", addr, cfg)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	// TLS 1.3 clients finish the handshake before the server verified their certificate,
+	// a read returns the server's alert if it rejected it
+	_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, err = conn.Read(make([]byte, 1))
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		err = nil
+	}
+	return conn.ConnectionState(), err
+}
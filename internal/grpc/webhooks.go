@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/services/webhooks"
+)
+
+type WebhookService struct {
+	warehousepb.UnimplementedWebhookServiceServer
+	webhooksSrv webhooks.Service
+}
+
+func NewWebhookService(webhooksSrv webhooks.Service) *WebhookService {
+	return &WebhookService{
+		webhooksSrv: webhooksSrv,
+	}
+}
+
+func (srv *WebhookService) CreateWebhookSubscription(ctx context.Context, req *warehousepb.CreateWebhookSubscriptionRequest) (*warehousepb.CreateWebhookSubscriptionResponse, error) {
+	item, err := srv.webhooksSrv.CreateSubscription(ctx, models.WebhookSubscription{
+		URL:        req.Url,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		ProductIDs: req.ProductIds,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.CreateWebhookSubscriptionResponse{
+		Subscription: toWebhookSubscription(item),
+	}, nil
+}
+
+func (srv *WebhookService) ListWebhookSubscriptions(ctx context.Context, _ *warehousepb.ListWebhookSubscriptionsRequest) (*warehousepb.ListWebhookSubscriptionsResponse, error) {
+	items, err := srv.webhooksSrv.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListWebhookSubscriptionsResponse{}
+	for _, item := range items {
+		resp.Subscriptions = append(resp.Subscriptions, toWebhookSubscription(item))
+	}
+	return resp, nil
+}
+
+func (srv *WebhookService) DeleteWebhookSubscription(ctx context.Context, req *warehousepb.DeleteWebhookSubscriptionRequest) (*warehousepb.DeleteWebhookSubscriptionResponse, error) {
+	err := srv.webhooksSrv.DeleteSubscription(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.DeleteWebhookSubscriptionResponse{}, nil
+}
+
+func (srv *WebhookService) ListWebhookDeadLetters(ctx context.Context, req *warehousepb.ListWebhookDeadLettersRequest) (*warehousepb.ListWebhookDeadLettersResponse, error) {
+	items, err := srv.webhooksSrv.GetDeadLetters(ctx, req.SubscriptionId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &warehousepb.ListWebhookDeadLettersResponse{}
+	for _, item := range items {
+		resp.Deliveries = append(resp.Deliveries, toWebhookDelivery(item))
+	}
+	return resp, nil
+}
+
+func (srv *WebhookService) ReplayWebhookDeliveries(ctx context.Context, req *warehousepb.ReplayWebhookDeliveriesRequest) (*warehousepb.ReplayWebhookDeliveriesResponse, error) {
+	replayed, err := srv.webhooksSrv.ReplayDeliveries(ctx, req.SubscriptionId, req.DeliveryIds)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &warehousepb.ReplayWebhookDeliveriesResponse{
+		Replayed: int32(replayed),
+	}, nil
+}
+
+// toWebhookSubscription converts the subscription, the secret is never returned
+func toWebhookSubscription(sub models.WebhookSubscription) *warehousepb.WebhookSubscription {
+	return &warehousepb.WebhookSubscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: sub.EventTypes,
+		ProductIds: sub.ProductIDs,
+		CreatedAt:  timestamppb.New(sub.CreatedAt),
+	}
+}
+
+func toWebhookDelivery(delivery models.WebhookDelivery) *warehousepb.WebhookDelivery {
+	item := &warehousepb.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		Payload:        string(delivery.Payload),
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.DeadAt != nil {
+		item.DeadAt = timestamppb.New(*delivery.DeadAt)
+	}
+	return item
+}
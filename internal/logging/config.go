@@ -0,0 +1,13 @@
+package logging
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Config struct {
+	// Format is text or json, text if unset
+	Format string
+	// Level is debug, info, warn or error, info if unset
+	Level string
+}
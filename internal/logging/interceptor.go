@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key of the request ID, it is generated if the client does not send one
+const RequestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestID returns the ID of the gRPC request handled with the context
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryServerInterceptor passes a logger carrying the request ID and method to the handler through the context
+// and logs every request with its duration, status and peer. The request ID is returned in the response header.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		id := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+		reqLogger := logger.With(slog.String("request_id", id), slog.String("method", info.FullMethod))
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			reqLogger = reqLogger.With(slog.String("trace_id", span.TraceID().String()))
+		}
+		ctx = context.WithValue(ctx, requestIDKey{}, id)
+		ctx = WithLogger(ctx, reqLogger)
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		attrs := []slog.Attr{
+			slog.Duration("duration", time.Since(start)),
+			slog.String("code", code.String()),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			attrs = append(attrs, slog.String("peer", p.Addr.String()))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		reqLogger.LogAttrs(ctx, level(code), "grpc request", attrs...)
+		return resp, err
+	}
+}
+
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(RequestIDHeader); len(ids) > 0 && ids[0] != "" {
+		return ids[0]
+	}
+	return newRequestID()
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// level logs server faults as errors, client errors are part of normal operation
+func level(code codes.Code) slog.Level {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unimplemented, codes.Unavailable:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should write json above the level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(Config{Format: FormatJSON, Level: "warn"}, &buf)
+		require.NoError(t, err)
+
+		logger.Info("skipped")
+		logger.Warn("written", slog.Int("article_id", 1))
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, "written", got["msg"])
+		assert.Equal(t, "WARN", got["level"])
+		assert.Equal(t, float64(1), got["article_id"])
+	})
+
+	t.Run("should fail on invalid config", func(t *testing.T) {
+		_, err := New(Config{Level: "verbose"}, &bytes.Buffer{})
+		require.Error(t, err)
+		_, err = New(Config{Format: "xml"}, &bytes.Buffer{})
+		require.Error(t, err)
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+	assert.Equal(t, logger, FromContext(WithLogger(context.Background(), logger)))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/warehouse.WarehouseService/RemoveProduct"}
+
+	t.Run("should log request with the incoming request id", func(t *testing.T) {
+		var buf bytes.Buffer
+		interceptor := UnaryServerInterceptor(slog.New(slog.NewJSONHandler(&buf, nil)))
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "abc"))
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			assert.Equal(t, "abc", RequestID(ctx))
+			FromContext(ctx).Info("product sold")
+			return nil, status.Error(codes.NotFound, "product not found")
+		})
+
+		require.Error(t, err)
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		var handled, request map[string]any
+		require.NoError(t, json.Unmarshal(lines[0], &handled))
+		require.NoError(t, json.Unmarshal(lines[1], &request))
+		assert.Equal(t, "abc", handled["request_id"])
+		assert.Equal(t, info.FullMethod, handled["method"])
+		assert.Equal(t, "grpc request", request["msg"])
+		assert.Equal(t, "abc", request["request_id"])
+		assert.Equal(t, "NotFound", request["code"])
+		assert.Equal(t, "10.0.0.1:5000", request["peer"])
+		assert.Equal(t, "INFO", request["level"])
+		assert.Contains(t, request, "duration")
+	})
+
+	t.Run("should generate request id", func(t *testing.T) {
+		var buf bytes.Buffer
+		interceptor := UnaryServerInterceptor(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		var id string
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			id = RequestID(ctx)
+			return nil, status.Error(codes.Internal, "failed")
+		})
+
+		require.Error(t, err)
+		assert.Len(t, id, 32)
+		var request map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &request))
+		assert.Equal(t, id, request["request_id"])
+		assert.Equal(t, "ERROR", request["level"])
+	})
+}
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+	info := &grpc.UnaryServerInfo{FullMethod: "/warehouse.WarehouseService/GetProducts"}
+
+	_, err := RecoveryUnaryServerInterceptor()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("integer divide by zero")
+	})
+
+	require.Equal(t, codes.Internal, status.Code(err))
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "recovered panic", entry["msg"])
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, info.FullMethod, entry["method"])
+	assert.Equal(t, "integer divide by zero", entry["panic"])
+	assert.Contains(t, entry["stack"], "TestRecoveryUnaryServerInterceptor")
+}
+
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	Recover(ctx, "outbox relay", func() {
+		panic("counter cannot decrease in value")
+	})
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "outbox relay", entry["task"])
+	assert.Equal(t, "counter cannot decrease in value", entry["panic"])
+
+	called := false
+	Recover(ctx, "outbox relay", func() {
+		called = true
+	})
+	assert.True(t, called)
+}
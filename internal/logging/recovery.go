@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryServerInterceptor turns a panic of the handler into an INTERNAL error, the panic is logged
+// with its stack through the request logger
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logPanic(ctx, r, "method", info.FullMethod)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor turns a panic of the stream handler into an INTERNAL error
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logPanic(ss.Context(), r, "method", info.FullMethod)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// Recover calls fn and logs a panic instead of crashing the process, so that background work keeps running
+func Recover(ctx context.Context, task string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logPanic(ctx, r, "task", task)
+		}
+	}()
+	fn()
+}
+
+func logPanic(ctx context.Context, r any, args ...any) {
+	args = append(args, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	FromContext(ctx).ErrorContext(ctx, "recovered panic", args...)
+}
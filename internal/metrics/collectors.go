@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"warehouse/internal/logging"
+	"warehouse/internal/repositories/articles"
+)
+
+const defaultScrapeTimeout = 5 * time.Second
+
+var (
+	poolAcquiredConns = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquired_connections"),
+		"Number of connections currently acquired from the pool.", nil, nil)
+	poolIdleConns = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "idle_connections"),
+		"Number of idle connections in the pool.", nil, nil)
+	poolTotalConns = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "total_connections"),
+		"Number of connections in the pool.", nil, nil)
+	poolMaxConns = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "max_connections"),
+		"Maximum size of the pool.", nil, nil)
+	poolAcquires = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquires_total"),
+		"Number of successful connection acquires.", nil, nil)
+	poolEmptyAcquires = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "empty_acquires_total"),
+		"Number of acquires which had to wait for a connection.", nil, nil)
+	poolCanceledAcquires = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "canceled_acquires_total"),
+		"Number of acquires canceled by their context.", nil, nil)
+	poolAcquireDuration = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquire_duration_seconds_total"),
+		"Total time spent acquiring connections.", nil, nil)
+
+	articleStock = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "article", "stock"),
+		"Article stock in the article unit.", []string{"article_id"}, nil)
+	articleReserved = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "article", "reserved"),
+		"Article stock reserved for orders.", []string{"article_id"}, nil)
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// NewPoolCollector collects the statistics of the connection pool
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &poolCollector{
+		pool: pool,
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolCanceledAcquires
+	ch <- poolAcquireDuration
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+type stockCollector struct {
+	articlesRepo articles.Repository
+	timeout      time.Duration
+}
+
+// NewStockCollector collects the current article stock on every scrape
+func NewStockCollector(aRepo articles.Repository, timeout time.Duration) prometheus.Collector {
+	if timeout <= 0 {
+		timeout = defaultScrapeTimeout
+	}
+	return &stockCollector{
+		articlesRepo: aRepo,
+		timeout:      timeout,
+	}
+}
+
+func (c *stockCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- articleStock
+	ch <- articleReserved
+}
+
+func (c *stockCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	items, err := c.articlesRepo.GetArticles(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error collecting article stock", "error", err)
+		ch <- prometheus.NewInvalidMetric(articleStock, err)
+		return
+	}
+	for _, item := range items {
+		id := strconv.Itoa(int(item.ID))
+		ch <- prometheus.MustNewConstMetric(articleStock, prometheus.GaugeValue, float64(item.Stock), id)
+		ch <- prometheus.MustNewConstMetric(articleReserved, prometheus.GaugeValue, float64(item.Reserved), id)
+	}
+}
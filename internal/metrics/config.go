@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Enabled bool
+	Port    int
+	Path    string
+	// ScrapeTimeout bounds the database queries run on every scrape
+	ScrapeTimeout time.Duration `koanf:"scrape_timeout"`
+}
+
+func (c *Config) Address() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"warehouse/api/warehousepb"
+)
+
+const namespace = "warehouse"
+
+// Metrics holds the server metrics. Business counters observe the events relayed by the outbox relay,
+// an event is counted by the instance that relayed it first, whether the sinks accepted it or not.
+type Metrics struct {
+	registry  *prometheus.Registry
+	requests  *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	unitsSold *prometheus.CounterVec
+	stockOuts *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC requests handled per method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Latency of gRPC requests per method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		unitsSold: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "units_sold_total",
+			Help:      "Number of product units sold.",
+		}, []string{"product_id"}),
+		stockOuts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stock_outs_total",
+			Help:      "Number of times the article stock ran out.",
+		}, []string{"article_id"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.latency,
+		m.unitsSold,
+		m.stockOuts,
+	)
+	return m
+}
+
+// Register adds collectors to the metrics served by the handler
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		err := m.registry.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		// a failing collector, e.g. while the database is down, must not hide the other metrics
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// UnaryServerInterceptor counts the requests and observes their latency
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		code := status.Code(err).String()
+		m.requests.WithLabelValues(info.FullMethod, code).Inc()
+		m.latency.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// Observe updates the business counters, it implements the outbox observer
+func (m *Metrics) Observe(_ context.Context, event *warehousepb.Event) {
+	switch payload := event.Payload.(type) {
+	case *warehousepb.Event_ProductSold_:
+		productID := strconv.Itoa(int(payload.ProductSold.ProductId))
+		m.unitsSold.WithLabelValues(productID).Add(float64(payload.ProductSold.Quantity))
+	case *warehousepb.Event_ArticleStockChanged_:
+		change := payload.ArticleStockChanged
+		if change.StockBefore > 0 && change.StockAfter <= 0 {
+			m.stockOuts.WithLabelValues(strconv.Itoa(int(change.ArticleId))).Inc()
+		}
+	}
+}
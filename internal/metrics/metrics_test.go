@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles/mock"
+)
+
+func TestMetrics_UnaryServerInterceptor(t *testing.T) {
+	m := New()
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/warehouse.WarehouseService/RemoveProduct"}
+
+	for _, err := range []error{nil, nil, status.Error(codes.NotFound, "not found")} {
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return nil, err
+		})
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues(info.FullMethod, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(info.FullMethod, "NotFound")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.latency))
+}
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New()
+	events := []*warehousepb.Event{
+		{Payload: &warehousepb.Event_ProductSold_{ProductSold: &warehousepb.Event_ProductSold{ProductId: 1, Quantity: 2}}},
+		{Payload: &warehousepb.Event_ProductSold_{ProductSold: &warehousepb.Event_ProductSold{ProductId: 1, Quantity: 3}}},
+		{Payload: &warehousepb.Event_ArticleStockChanged_{ArticleStockChanged: &warehousepb.Event_ArticleStockChanged{
+			ArticleId: 4, StockBefore: 2, StockAfter: 0,
+		}}},
+		{Payload: &warehousepb.Event_ArticleStockChanged_{ArticleStockChanged: &warehousepb.Event_ArticleStockChanged{
+			ArticleId: 5, StockBefore: 0, StockAfter: 0,
+		}}},
+	}
+
+	for _, event := range events {
+		m.Observe(context.Background(), event)
+	}
+
+	assert.Equal(t, float64(5), testutil.ToFloat64(m.unitsSold.WithLabelValues("1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.stockOuts.WithLabelValues("4")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.stockOuts))
+}
+
+func TestStockCollector(t *testing.T) {
+	t.Run("should collect article stock", func(t *testing.T) {
+		aRepo := mockArticlesRepo.NewMockRepository(gomock.NewController(t))
+		aRepo.EXPECT().GetArticles(gomock.Any()).Return([]models.Article{
+			{ID: 1, Name: "leg", Stock: 12, Reserved: 4},
+		}, nil)
+
+		err := testutil.CollectAndCompare(NewStockCollector(aRepo, time.Second), strings.NewReader(`
+# HELP warehouse_article_reserved Article stock reserved for orders.
+# TYPE warehouse_article_reserved gauge
+warehouse_article_reserved{article_id="1"} 4
+# HELP warehouse_article_stock Article stock in the article unit.
+# TYPE warehouse_article_stock gauge
+warehouse_article_stock{article_id="1"} 12
+`))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should report repo errors", func(t *testing.T) {
+		aRepo := mockArticlesRepo.NewMockRepository(gomock.NewController(t))
+		aRepo.EXPECT().GetArticles(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		err := testutil.CollectAndCompare(NewStockCollector(aRepo, time.Second), strings.NewReader(""))
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type LowStockAlert struct {
+	ArticleID       int32
+	Name            string
+	Stock           int32
+	ReorderPoint    int32
+	ReorderQuantity int32
+	DetectedAt      time.Time
+}
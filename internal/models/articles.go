@@ -1,7 +1,32 @@
 package models
 
+import "time"
+
 type Article struct {
 	ID    int32
 	Name  string
 	Stock int32
+	// Unit is the base unit of the article's dimension, stock and all other article quantities are kept in it
+	Unit       string
+	Serialized bool
+	// ReorderPoint is the stock level at or below which the article is low on stock, nil disables alerts
+	ReorderPoint    *int32
+	ReorderQuantity int32
+	LowStock        bool
+	// Reserved is the part of the stock allocated to orders which are not shipped yet
+	Reserved int32
+	// Version is incremented on every change of the article
+	Version int32
+}
+
+// Available returns the stock which is not reserved
+func (a Article) Available() int32 {
+	return a.Stock - a.Reserved
+}
+
+type ArticleSerial struct {
+	Serial     string
+	ArticleID  int32
+	SaleID     *int32
+	ReceivedAt time.Time
 }
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type AssemblyOrderStatus string
+
+const (
+	// AssemblyOrderOpen orders have consumed their component articles and are being assembled
+	AssemblyOrderOpen      AssemblyOrderStatus = "open"
+	AssemblyOrderCompleted AssemblyOrderStatus = "completed"
+)
+
+type AssemblyOrder struct {
+	ID          int32
+	ProductID   int32
+	Quantity    int32
+	Status      AssemblyOrderStatus
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// Disassembly records the articles recovered from product units broken down into parts
+type Disassembly struct {
+	ID        int32
+	ProductID int32
+	Quantity  int32
+	// FromAssembled units were taken from the assembled stock, otherwise they were returned
+	FromAssembled bool
+	Components    []DisassembledComponent
+	CreatedAt     time.Time
+}
+
+// DisassembledComponent quantities are in the article unit
+type DisassembledComponent struct {
+	ArticleID int32
+	Expected  int32
+	Recovered int32
+	Scrapped  int32
+}
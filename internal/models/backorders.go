@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Backorder is the part of a sale which could not be fulfilled from stock at the time of the sale.
+// Units are fulfilled as stock arrives, oldest backorders first.
+type Backorder struct {
+	ID        int32
+	SaleID    int32
+	ProductID int32
+	Quantity  int32
+	Fulfilled int32
+	CreatedAt time.Time
+	// FulfilledAt is set once the backorder is fulfilled in full
+	FulfilledAt *time.Time
+}
+
+// Outstanding returns the quantity still to be fulfilled
+func (b Backorder) Outstanding() int32 {
+	return b.Quantity - b.Fulfilled
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// currencyExponents lists ISO 4217 currencies which minor unit is not a hundredth
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// Money is an exact amount in minor units of the ISO 4217 currency
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// CurrencyExponent returns the number of decimal digits of the currency minor unit
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// ValidateCurrency checks that the currency is an ISO 4217 code of three upper-case letters
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 || strings.IndexFunc(currency, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return fmt.Errorf("%w: currency %q", ErrInvalidMoney, currency)
+	}
+	return nil
+}
+
+// ParseMoney parses a decimal amount in major units, e.g. "19.99", the currency code is upper-cased
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, amount)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(currency))), nil)
+	value.Mul(value, new(big.Rat).SetInt(scale))
+	if !value.IsInt() || !value.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q has too many decimals for %s", ErrInvalidMoney, amount, currency)
+	}
+	return Money{
+		Amount:   value.Num().Int64(),
+		Currency: currency,
+	}, nil
+}
+
+// String formats the amount in major units, e.g. "19.99 EUR"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Decimal formats the amount in major units without the currency, e.g. "19.99"
+func (m Money) Decimal() string {
+	exp := CurrencyExponent(m.Currency)
+	value := new(big.Rat).SetFrac(big.NewInt(m.Amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	return value.FloatString(exp)
+}
+
+// Add sums amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{
+		Amount:   m.Amount + other.Amount,
+		Currency: m.Currency,
+	}, nil
+}
+
+// Mul multiplies the amount by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{
+		Amount:   m.Amount * quantity,
+		Currency: m.Currency,
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		amount   string
+		currency string
+		expected Money
+	}{
+		{amount: "19.99", currency: "EUR", expected: Money{Amount: 1999, Currency: "EUR"}},
+		{amount: "20", currency: "sek", expected: Money{Amount: 2000, Currency: "SEK"}},
+		{amount: "1500", currency: "JPY", expected: Money{Amount: 1500, Currency: "JPY"}},
+		{amount: "1.005", currency: "KWD", expected: Money{Amount: 1005, Currency: "KWD"}},
+	}
+	for _, tc := range cases {
+		m, err := ParseMoney(tc.amount, tc.currency)
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, m)
+	}
+
+	t.Run("should fail on sub-minor amount", func(t *testing.T) {
+		_, err := ParseMoney("19.999", "EUR")
+
+		require.ErrorIs(t, err, ErrInvalidMoney)
+	})
+
+	t.Run("should fail on invalid currency", func(t *testing.T) {
+		_, err := ParseMoney("1", "EURO")
+
+		require.ErrorIs(t, err, ErrInvalidMoney)
+	})
+}
+
+func TestValidateCurrency(t *testing.T) {
+	require.NoError(t, ValidateCurrency("EUR"))
+	for _, currency := range []string{"", "eu", "eur", "EURO", "E1R", "EU "} {
+		require.ErrorIs(t, ValidateCurrency(currency), ErrInvalidMoney, currency)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "19.99 EUR", Money{Amount: 1999, Currency: "EUR"}.String())
+	assert.Equal(t, "0.05 EUR", Money{Amount: 5, Currency: "EUR"}.String())
+	assert.Equal(t, "1500 JPY", Money{Amount: 1500, Currency: "JPY"}.String())
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := Money{Amount: 100, Currency: "EUR"}.Add(Money{Amount: 250, Currency: "EUR"})
+
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 350, Currency: "EUR"}, sum)
+
+	_, err = Money{Amount: 100, Currency: "EUR"}.Add(Money{Amount: 250, Currency: "SEK"})
+
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
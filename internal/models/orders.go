@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderPlaced    OrderStatus = "placed"
+	OrderAllocated OrderStatus = "allocated"
+	OrderPicking   OrderStatus = "picking"
+	OrderPacked    OrderStatus = "packed"
+	OrderShipped   OrderStatus = "shipped"
+	OrderCancelled OrderStatus = "cancelled"
+)
+
+// Order is a customer order of products. Stock is reserved when the order is allocated
+// and removed when it is shipped.
+type Order struct {
+	ID        int32
+	Status    OrderStatus
+	Lines     []OrderLine
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type OrderLine struct {
+	ID        int32
+	ProductID int32
+	Quantity  int32
+	// Assembled is the number of units taken from the assembled stock on allocation
+	Assembled int32
+	// Reserved lists the article quantities reserved on allocation to build the rest of the units
+	Reserved []ProductArticle
+	// SaleID is set when the line is shipped
+	SaleID *int32
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event waiting to be delivered, the payload is the warehouse.Event payload
+// in the protobuf JSON mapping
+type OutboxEvent struct {
+	ID        int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+	// Attempts counts the failed deliveries
+	Attempts int32
+}
@@ -0,0 +1,23 @@
+package models
+
+// PickList is the consolidated list of articles to pick to build the product lines,
+// quantities are in the article unit
+type PickList struct {
+	Lines []ProductSale
+	Items []PickListItem
+}
+
+type PickListItem struct {
+	ArticleID int32
+	Name      string
+	Unit      string
+	Quantity  int32
+	// OnHand is the stock which is not reserved for orders
+	OnHand   int32
+	Shortage int32
+	// Missing lines are made of articles unknown to the warehouse or in a unit which cannot be converted
+	// to the article unit, the quantity and unit are kept as in the BOM
+	Missing bool
+	// ProductIDs lists the products the article is picked for
+	ProductIDs []int32
+}
@@ -0,0 +1,23 @@
+package models
+
+type MixObjective int
+
+const (
+	MixObjectiveRevenue MixObjective = iota
+	MixObjectiveUnits
+)
+
+type ProductionMix struct {
+	Items    []ProductionMixItem
+	Revenue  Money
+	Units    int32
+	Leftover []ProductArticle
+	// Optimal is false if the search was cut short and the mix is the best one found
+	Optimal bool
+}
+
+type ProductionMixItem struct {
+	ProductID int32
+	Quantity  int32
+	Revenue   Money
+}
@@ -3,16 +3,39 @@ package models
 type Product struct {
 	ID       int32
 	Name     string
-	Price    float32
+	Price    Money
 	Articles []ProductArticle
+	// Assembled is the number of pre-assembled units on stock
+	Assembled int32
+	// Version is incremented on every change of the product
+	Version int32
 }
 
 type ProductArticle struct {
 	ID       int32
 	Quantity int32
+	// Unit of Quantity, empty means the article unit
+	Unit string
 }
 
 type ProductWithStock struct {
 	Product
-	Stock int32
+	// Stock is the assembled units plus the units buildable from articles
+	Stock     int32
+	Buildable int32
+	// Components break the stock down per article in the product BOM
+	Components          []ComponentAvailability
+	BottleneckArticleID int32
+	MissingArticleIDs   []int32
+}
+
+// ComponentAvailability shows how many product units an article allows to build,
+// quantities are in the article unit
+type ComponentAvailability struct {
+	ArticleID  int32
+	Required   int32
+	OnHand     int32
+	Buildable  int32
+	Missing    bool
+	Bottleneck bool
 }
@@ -0,0 +1,34 @@
+package models
+
+// ReplenishmentPolicy controls how consumption is estimated and how much safety stock is kept
+type ReplenishmentPolicy struct {
+	// WindowDays is the sales history used to estimate the daily consumption
+	WindowDays int32
+	// SafetyStockDays is the consumption kept on stock on top of the lead time demand
+	SafetyStockDays int32
+}
+
+// ReplenishmentSuggestion proposes how much of the article to order, quantities are in the article unit
+type ReplenishmentSuggestion struct {
+	ArticleID int32
+	Name      string
+	Unit      string
+	// Stock is the stock which is not reserved for orders
+	Stock            int32
+	OnOrder          int32
+	DailyConsumption float64
+	LeadTimeDays     int32
+	SafetyStock      int32
+	// ReorderLevel is the demand over the lead time plus the safety stock
+	ReorderLevel int32
+	Quantity     int32
+	// Source is the supplier with the shortest lead time, nil if no supplier sells the article
+	Source *SupplierArticle
+}
+
+// Replenishment holds the suggestions and the products whose sales could not be exploded,
+// their BOM has a line which cannot be converted to the article unit
+type Replenishment struct {
+	Suggestions       []ReplenishmentSuggestion
+	SkippedProductIDs []int32
+}
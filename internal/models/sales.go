@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type Sale struct {
+	ID        int32
+	ProductID int32
+	Quantity  int32
+	// Backordered is the part of the quantity which was not on stock at the time of the sale
+	Backordered int32
+	CreatedAt   time.Time
+}
+
+type SerialTrace struct {
+	ArticleSerial
+	Sale *Sale
+}
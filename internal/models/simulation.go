@@ -0,0 +1,26 @@
+package models
+
+// Simulation describes hypothetical inventory changes,
+// BOM changes are applied first, then receipts and then sales
+type Simulation struct {
+	Sales      []ProductSale
+	Receipts   []ProductArticle
+	BOMChanges []Product
+}
+
+type ProductSale struct {
+	ProductID int32
+	Quantity  int32
+}
+
+type SimulationResult struct {
+	Products []ProductWithStock
+	Articles []ArticleBalance
+}
+
+type ArticleBalance struct {
+	ArticleID int32
+	Name      string
+	Before    int32
+	After     int32
+}
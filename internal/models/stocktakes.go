@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+type StocktakeStatus string
+
+const (
+	StocktakeOpen      StocktakeStatus = "open"
+	StocktakeApproved  StocktakeStatus = "approved"
+	StocktakeCancelled StocktakeStatus = "cancelled"
+)
+
+// Stocktake is a physical count of a set of articles, quantities are in the article unit
+type Stocktake struct {
+	ID       int32
+	Status   StocktakeStatus
+	Articles []StocktakeArticle
+	// ClosedAt is set when the stocktake is approved or cancelled
+	ClosedAt  *time.Time
+	CreatedAt time.Time
+}
+
+type StocktakeArticle struct {
+	ArticleID int32
+	// Expected is the stock frozen when the stocktake was opened
+	Expected int32
+	// Stock is the current stock, it differs from the expected stock if the stock changed during the count
+	Stock  int32
+	Counts []StocktakeCount
+	// Adjustment is the stock change posted when the stocktake was approved
+	Adjustment int32
+}
+
+// Counted sums the quantities of all counters, false if the article has not been counted.
+// Every counter counts a part of the stock, e.g. a separate location.
+func (a StocktakeArticle) Counted() (int32, bool) {
+	var counted int32
+	for _, c := range a.Counts {
+		counted += c.Quantity
+	}
+	return counted, len(a.Counts) > 0
+}
+
+// Variance returns the counted quantity less the stock when the article was last counted, zero if the article
+// has not been counted. Stock received or removed before the last count is part of the counted quantity,
+// measuring against the expected quantity would post it twice.
+func (a StocktakeArticle) Variance() int32 {
+	counted, ok := a.Counted()
+	if !ok {
+		return 0
+	}
+	last := a.Counts[0]
+	for _, c := range a.Counts[1:] {
+		if c.CountedAt.After(last.CountedAt) {
+			last = c
+		}
+	}
+	return counted - last.Stock
+}
+
+// StocktakeCount is the quantity counted by a counter, a new count of the same counter replaces the previous one
+type StocktakeCount struct {
+	ArticleID int32
+	Counter   string
+	Quantity  int32
+	// Stock is the article stock when the count was submitted
+	Stock     int32
+	CountedAt time.Time
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type Supplier struct {
+	ID   int32
+	Name string
+}
+
+// SupplierArticle is an article the supplier sells, quantities and prices are per article unit
+type SupplierArticle struct {
+	SupplierID   int32
+	ArticleID    int32
+	SKU          string
+	Price        Money
+	LeadTimeDays int32
+}
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderClosed            PurchaseOrderStatus = "closed"
+)
+
+type PurchaseOrder struct {
+	ID         int32
+	SupplierID int32
+	Status     PurchaseOrderStatus
+	Lines      []PurchaseOrderLine
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type PurchaseOrderLine struct {
+	ID        int32
+	ArticleID int32
+	Quantity  int32
+	Received  int32
+	// Price per article unit
+	Price Money
+}
+
+// Outstanding returns the quantity still to be received
+func (l PurchaseOrderLine) Outstanding() int32 {
+	return l.Quantity - l.Received
+}
+
+// PurchaseOrderReceipt is a quantity received against a purchase order line,
+// serialized articles require a serial for every unit
+type PurchaseOrderReceipt struct {
+	LineID   int32
+	Quantity int32
+	Serials  []string
+}
@@ -0,0 +1,15 @@
+package models
+
+// Unit is a unit of measure, Factor is the number of dimension base units in one unit
+type Unit struct {
+	Code      string
+	Dimension string
+	Factor    int64
+}
+
+// ArticleUnit is an article specific packaging unit, Factor is the number of article units in one unit
+type ArticleUnit struct {
+	ArticleID int32
+	Code      string
+	Factor    int64
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	WebhookEventProductInStock    = "product.in_stock"
+	WebhookEventProductOutOfStock = "product.out_of_stock"
+)
+
+// WebhookEventTypes lists the event types subscriptions can filter on
+var WebhookEventTypes = []string{WebhookEventProductInStock, WebhookEventProductOutOfStock}
+
+// WebhookSubscription receives the events matching its filters, an empty filter matches everything.
+// Deliveries are signed with the secret.
+type WebhookSubscription struct {
+	ID         int32
+	URL        string
+	Secret     string
+	EventTypes []string
+	ProductIDs []int32
+	CreatedAt  time.Time
+}
+
+// Matches reports whether the subscription filters match the event
+func (s WebhookSubscription) Matches(eventType string, productID int32) bool {
+	if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	return len(s.ProductIDs) == 0 || slices.Contains(s.ProductIDs, productID)
+}
+
+// WebhookDelivery is an event queued for a subscription, the payload is the JSON request body
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int32
+	EventType      string
+	Payload        []byte
+	CreatedAt      time.Time
+	// Attempts counts the failed deliveries
+	Attempts    int32
+	LastError   string
+	DeliveredAt *time.Time
+	// DeadAt is set when the delivery was given up, dead letters are only retried when replayed
+	DeadAt *time.Time
+}
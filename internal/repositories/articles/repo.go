@@ -4,21 +4,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"warehouse/internal/db"
 	"warehouse/internal/models"
 )
 
+// ChangesChannel is notified with the article ID whenever the article stock, reservation, unit or reorder point changes
+const ChangesChannel = "article_changes"
+
+// RestocksChannel is notified with the article ID whenever the stock which is not reserved increases
+const RestocksChannel = "article_restocks"
+
+const (
+	checkViolation = "23514"
+	// stockConstraint keeps the article stock from dropping below zero
+	stockConstraint = "articles_stock_non_negative"
+)
+
+// failingRowID matches the article ID, the first column, in the detail of a constraint violation
+var failingRowID = regexp.MustCompile(`^Failing row contains \((\d+),`)
+
 var (
-	ErrNotFound = errors.New("article not found")
+	ErrNotFound          = errors.New("article not found")
+	ErrInsufficientStock = errors.New("insufficient available stock")
+	ErrVersionConflict   = errors.New("article was modified concurrently")
 )
 
 type Repository interface {
 	GetArticles(ctx context.Context) ([]models.Article, error)
 	GetArticle(ctx context.Context, id int32) (models.Article, error)
+	GetArticlesByID(ctx context.Context, ids []int32) ([]models.Article, error)
+	AddArticles(ctx context.Context, items []models.ProductArticle) error
 	RemoveArticles(ctx context.Context, items []models.ProductArticle) error
+	UpdateArticle(ctx context.Context, item models.Article) (models.Article, error)
+	UpdateReorderPolicy(ctx context.Context, id, version int32, point *int32, quantity int32) (models.Article, error)
+	GetLowStockArticles(ctx context.Context) ([]models.Article, error)
+	SyncLowStock(ctx context.Context, ids []int32) ([]models.Article, error)
+	ReserveArticles(ctx context.Context, items []models.ProductArticle) error
+	ReleaseArticles(ctx context.Context, items []models.ProductArticle) error
+	RemoveReserved(ctx context.Context, items []models.ProductArticle) error
 }
 
 type impl struct {
@@ -31,40 +62,37 @@ func NewRepository(db *pgxpool.Pool) Repository {
 	}
 }
 
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
 func (repo *impl) GetArticles(ctx context.Context) ([]models.Article, error) {
 	const query = `
-		SELECT id, name, stock
+		SELECT id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
 		FROM articles
 		ORDER BY id
 	`
+	return repo.queryArticles(ctx, query)
+}
 
-	var items []models.Article
-	rows, err := repo.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query rows: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var item models.Article
-		err := rows.Scan(&item.ID, &item.Name, &item.Stock)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		items = append(items, item)
-	}
-	return items, nil
+func (repo *impl) GetArticlesByID(ctx context.Context, ids []int32) ([]models.Article, error) {
+	const query = `
+		SELECT id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
+		FROM articles
+		WHERE id = ANY ($1)
+		ORDER BY id
+	`
+	return repo.queryArticles(ctx, query, ids)
 }
 
 func (repo *impl) GetArticle(ctx context.Context, id int32) (models.Article, error) {
 	const query = `
-		SELECT id, name, stock
+		SELECT id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
 		FROM articles
 		WHERE id = $1
 	`
 	var item models.Article
-	err := repo.db.QueryRow(ctx, query, id).Scan(&item.ID, &item.Name, &item.Stock)
+	err := scanArticle(repo.conn(ctx).QueryRow(ctx, query, id), &item)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Article{}, ErrNotFound
@@ -74,25 +102,283 @@ func (repo *impl) GetArticle(ctx context.Context, id int32) (models.Article, err
 	return item, nil
 }
 
+func (repo *impl) AddArticles(ctx context.Context, items []models.ProductArticle) error {
+	const query = `
+		WITH to_add (id, quantity) AS (
+			SELECT *
+			FROM unnest($1::int[], $2::int[])
+		)
+		UPDATE articles
+		SET stock = stock + to_add.quantity
+		FROM to_add
+		WHERE articles.id = to_add.id
+	`
+
+	ids, quantities := splitItems(items)
+	_, err := repo.conn(ctx).Exec(ctx, query, ids, quantities)
+	return stockError(err)
+}
+
+// RemoveArticles decrements the stock, it fails with ErrInsufficientStock if any article has not enough
+// stock which is not reserved. Unknown articles are ignored. It must be called within a transaction,
+// quantities of repeated articles are summed.
 func (repo *impl) RemoveArticles(ctx context.Context, items []models.ProductArticle) error {
 	const query = `
 		WITH to_remove (id, quantity) AS (
+			SELECT id, sum(quantity)
+			FROM unnest($1::int[], $2::int[]) AS items (id, quantity)
+			GROUP BY id
+		)
+		UPDATE articles
+		SET stock = stock - to_remove.quantity
+		FROM to_remove
+		WHERE articles.id = to_remove.id
+		  AND articles.stock - articles.reserved >= to_remove.quantity
+		RETURNING articles.id
+	`
+	return repo.updateStock(ctx, query, items)
+}
+
+// ReserveArticles reserves the available stock of the articles, it fails if any article has not enough
+// stock which is not reserved yet. It must be called within a transaction, items must not repeat an article.
+func (repo *impl) ReserveArticles(ctx context.Context, items []models.ProductArticle) error {
+	const query = `
+		WITH to_reserve (id, quantity) AS (
 			SELECT *
 			FROM unnest($1::int[], $2::int[])
 		)
 		UPDATE articles
-		SET stock = stock - to_remove.quantity
+		SET reserved = reserved + to_reserve.quantity
+		FROM to_reserve
+		WHERE articles.id = to_reserve.id
+		  AND articles.stock - articles.reserved >= to_reserve.quantity
+		RETURNING articles.id
+	`
+
+	ids, quantities := splitItems(items)
+	rows, err := repo.conn(ctx).Query(ctx, query, ids, quantities)
+	if err != nil {
+		return fmt.Errorf("failed to query rows: %w", err)
+	}
+	reserved, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+	if err != nil {
+		return fmt.Errorf("failed to scan row: %w", err)
+	}
+	for _, id := range ids {
+		if !slices.Contains(reserved, id) {
+			return fmt.Errorf("%w: article %d", ErrInsufficientStock, id)
+		}
+	}
+	return nil
+}
+
+// ReleaseArticles returns reserved stock to the available stock
+func (repo *impl) ReleaseArticles(ctx context.Context, items []models.ProductArticle) error {
+	const query = `
+		WITH to_release (id, quantity) AS (
+			SELECT *
+			FROM unnest($1::int[], $2::int[])
+		)
+		UPDATE articles
+		SET reserved = reserved - to_release.quantity
+		FROM to_release
+		WHERE articles.id = to_release.id
+	`
+
+	ids, quantities := splitItems(items)
+	_, err := repo.conn(ctx).Exec(ctx, query, ids, quantities)
+	return stockError(err)
+}
+
+// RemoveReserved removes reserved stock, both the stock and the reservation are decremented. It fails with
+// ErrInsufficientStock if any article has not enough reserved stock, unknown articles are ignored.
+// It must be called within a transaction, quantities of repeated articles are summed.
+func (repo *impl) RemoveReserved(ctx context.Context, items []models.ProductArticle) error {
+	const query = `
+		WITH to_remove (id, quantity) AS (
+			SELECT id, sum(quantity)
+			FROM unnest($1::int[], $2::int[]) AS items (id, quantity)
+			GROUP BY id
+		)
+		UPDATE articles
+		SET stock    = stock - to_remove.quantity,
+			reserved = reserved - to_remove.quantity
 		FROM to_remove
 		WHERE articles.id = to_remove.id
+		  AND articles.reserved >= to_remove.quantity
+		  AND articles.stock >= to_remove.quantity
+		RETURNING articles.id
+	`
+	return repo.updateStock(ctx, query, items)
+}
+
+// UpdateArticle updates the name and reorder policy of the article if it still has the given version
+func (repo *impl) UpdateArticle(ctx context.Context, item models.Article) (models.Article, error) {
+	const query = `
+		UPDATE articles
+		SET name             = $3,
+			reorder_point    = $4,
+			reorder_quantity = $5
+		WHERE id = $1
+		  AND version = $2
+		RETURNING id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
+	`
+	row := repo.conn(ctx).QueryRow(ctx, query, item.ID, item.Version, item.Name, item.ReorderPoint, item.ReorderQuantity)
+	return repo.scanUpdated(ctx, item.ID, row)
+}
+
+// UpdateReorderPolicy updates the reorder policy of the article if it still has the given version
+func (repo *impl) UpdateReorderPolicy(
+	ctx context.Context,
+	id, version int32,
+	point *int32,
+	quantity int32,
+) (models.Article, error) {
+	const query = `
+		UPDATE articles
+		SET reorder_point    = $3,
+			reorder_quantity = $4
+		WHERE id = $1
+		  AND version = $2
+		RETURNING id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
+	`
+	row := repo.conn(ctx).QueryRow(ctx, query, id, version, point, quantity)
+	return repo.scanUpdated(ctx, id, row)
+}
+
+// updateStock runs a stock update returning the IDs of the updated articles, it fails with ErrInsufficientStock
+// naming the first existing article which was not updated or which violates the stock constraint
+func (repo *impl) updateStock(ctx context.Context, query string, items []models.ProductArticle) error {
+	ids, quantities := splitItems(items)
+	rows, err := repo.conn(ctx).Query(ctx, query, ids, quantities)
+	if err != nil {
+		return stockError(fmt.Errorf("failed to query rows: %w", err))
+	}
+	updated, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+	if err != nil {
+		return stockError(fmt.Errorf("failed to scan row: %w", err))
+	}
+
+	var skipped []int32
+	for _, id := range ids {
+		if !slices.Contains(updated, id) && !slices.Contains(skipped, id) {
+			skipped = append(skipped, id)
+		}
+	}
+	if len(skipped) == 0 {
+		return nil
+	}
+	existing, err := repo.GetArticlesByID(ctx, skipped)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: article %d", ErrInsufficientStock, existing[0].ID)
+}
+
+// scanUpdated scans the article returned by a versioned update,
+// no row means the article does not exist or its version has changed
+func (repo *impl) scanUpdated(ctx context.Context, id int32, row pgx.Row) (models.Article, error) {
+	var item models.Article
+	err := scanArticle(row, &item)
+	if err == nil {
+		return item, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return models.Article{}, err
+	}
+
+	_, err = repo.GetArticle(ctx, id)
+	if err != nil {
+		return models.Article{}, err
+	}
+	return models.Article{}, ErrVersionConflict
+}
+
+func (repo *impl) GetLowStockArticles(ctx context.Context) ([]models.Article, error) {
+	const query = `
+		SELECT id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
+		FROM articles
+		WHERE stock <= reorder_point
+		ORDER BY id
+	`
+	return repo.queryArticles(ctx, query)
+}
+
+// SyncLowStock updates the low stock flag of the given articles, all articles if ids is nil,
+// and returns the articles which flag has changed
+func (repo *impl) SyncLowStock(ctx context.Context, ids []int32) ([]models.Article, error) {
+	const query = `
+		UPDATE articles
+		SET low_stock = coalesce(stock <= reorder_point, FALSE)
+		WHERE ($1::int[] IS NULL OR id = ANY ($1))
+		  AND low_stock <> coalesce(stock <= reorder_point, FALSE)
+		RETURNING id, name, stock, unit, serialized, reorder_point, reorder_quantity, low_stock, reserved, version
 	`
+	return repo.queryArticles(ctx, query, ids)
+}
 
+func (repo *impl) queryArticles(ctx context.Context, query string, args ...any) ([]models.Article, error) {
+	var items []models.Article
+	rows, err := repo.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.Article
+		err := scanArticle(rows, &item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanArticle(row pgx.Row, item *models.Article) error {
+	return row.Scan(
+		&item.ID,
+		&item.Name,
+		&item.Stock,
+		&item.Unit,
+		&item.Serialized,
+		&item.ReorderPoint,
+		&item.ReorderQuantity,
+		&item.LowStock,
+		&item.Reserved,
+		&item.Version,
+	)
+}
+
+// stockError converts a violation of the non-negative stock constraint to ErrInsufficientStock
+// naming the article
+func stockError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != checkViolation || pgErr.ConstraintName != stockConstraint {
+		return err
+	}
+	match := failingRowID.FindStringSubmatch(pgErr.Detail)
+	if match == nil {
+		return ErrInsufficientStock
+	}
+	id, err := strconv.ParseInt(match[1], 10, 32)
+	if err != nil {
+		return ErrInsufficientStock
+	}
+	return fmt.Errorf("%w: article %d", ErrInsufficientStock, id)
+}
+
+func splitItems(items []models.ProductArticle) ([]int32, []int32) {
 	ids := make([]int32, len(items))
-	stocks := make([]int32, len(items))
+	quantities := make([]int32, len(items))
 	for i, item := range items {
 		ids[i] = item.ID
-		stocks[i] = item.Quantity
+		quantities[i] = item.Quantity
 	}
-
-	_, err := repo.db.Exec(ctx, query, ids, stocks)
-	return err
+	return ids, quantities
 }
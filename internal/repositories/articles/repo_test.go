@@ -2,12 +2,16 @@ package articles
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"warehouse/internal/db"
 	"warehouse/internal/models"
 	"warehouse/internal/testhelpers"
 )
@@ -39,6 +43,22 @@ func TestImpl_GetArticles(t *testing.T) {
 	})
 }
 
+func TestImpl_GetArticlesByID(t *testing.T) {
+	t.Run("should return requested items", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		a1 := fx.createArticle(models.Article{})
+		fx.createArticle(models.Article{})
+		a3 := fx.createArticle(models.Article{})
+
+		items, err := fx.GetArticlesByID(fx.ctx, []int32{a3.ID, a1.ID, testhelpers.RandomInt32()})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Article{a1, a3}, items)
+	})
+}
+
 func TestImpl_GetArticle(t *testing.T) {
 	t.Run("should return ErrNotFound", func(t *testing.T) {
 		fx := newFixture(t)
@@ -73,6 +93,49 @@ func TestImpl_GetArticle(t *testing.T) {
 	})
 }
 
+func TestImpl_AddArticles(t *testing.T) {
+	t.Run("should add items", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art1 := fx.createArticle(models.Article{Stock: 10})
+		art2 := fx.createArticle(models.Article{Stock: 10})
+
+		toAdd := []models.ProductArticle{
+			{
+				ID:       art2.ID,
+				Quantity: 5,
+			},
+			{
+				ID:       testhelpers.RandomInt32(),
+				Quantity: 1,
+			},
+		}
+		err := fx.AddArticles(fx.ctx, toAdd)
+		require.NoError(t, err)
+
+		art1, err = fx.GetArticle(fx.ctx, art1.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 10, art1.Stock)
+
+		art2, err = fx.GetArticle(fx.ctx, art2.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 15, art2.Stock)
+	})
+
+	t.Run("should return ErrInsufficientStock on negative stock", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{Stock: 3})
+
+		err := fx.AddArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: -4}})
+
+		require.ErrorIs(t, err, ErrInsufficientStock)
+		assert.Contains(t, err.Error(), fmt.Sprintf("article %d", art.ID))
+	})
+}
+
 func TestImpl_RemoveArticles(t *testing.T) {
 	t.Run("should remove items", func(t *testing.T) {
 		fx := newFixture(t)
@@ -81,7 +144,6 @@ func TestImpl_RemoveArticles(t *testing.T) {
 		art1 := fx.createArticle(models.Article{Stock: 10})
 		art2 := fx.createArticle(models.Article{Stock: 10})
 		art3 := fx.createArticle(models.Article{Stock: 10})
-		art4 := fx.createArticle(models.Article{Stock: 10})
 
 		toRemove := []models.ProductArticle{
 			{
@@ -90,10 +152,6 @@ func TestImpl_RemoveArticles(t *testing.T) {
 			},
 			{
 				ID:       art3.ID,
-				Quantity: 15,
-			},
-			{
-				ID:       art4.ID,
 				Quantity: 4,
 			},
 			{
@@ -114,11 +172,300 @@ func TestImpl_RemoveArticles(t *testing.T) {
 
 		art3, err = fx.GetArticle(fx.ctx, art3.ID)
 		require.NoError(t, err)
-		assert.EqualValues(t, -5, art3.Stock)
+		assert.EqualValues(t, 6, art3.Stock)
+	})
+
+	t.Run("should return ErrInsufficientStock naming the article", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art1 := fx.createArticle(models.Article{Stock: 10})
+		art2 := fx.createArticle(models.Article{Stock: 10})
+
+		err := db.NewTransactor(fx.db).InTx(fx.ctx, func(ctx context.Context) error {
+			return fx.RemoveArticles(ctx, []models.ProductArticle{
+				{ID: art1.ID, Quantity: 4},
+				{ID: art2.ID, Quantity: 15},
+			})
+		})
+
+		require.ErrorIs(t, err, ErrInsufficientStock)
+		assert.Contains(t, err.Error(), fmt.Sprintf("article %d", art2.ID))
+
+		// nothing is removed
+		art1, err = fx.GetArticle(fx.ctx, art1.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 10, art1.Stock)
+	})
+
+	t.Run("should not remove reserved stock", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{Stock: 10})
+		err := fx.ReserveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 6}})
+		require.NoError(t, err)
+
+		err = fx.RemoveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 5}})
+		require.ErrorIs(t, err, ErrInsufficientStock)
+
+		err = fx.RemoveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 4}})
+		require.NoError(t, err)
+
+		art, err = fx.GetArticle(fx.ctx, art.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 6, art.Stock)
+		assert.EqualValues(t, 6, art.Reserved)
+	})
+
+	t.Run("should sum repeated articles", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{Stock: 10})
+
+		err := fx.RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: art.ID, Quantity: 6},
+			{ID: art.ID, Quantity: 6},
+		})
+		require.ErrorIs(t, err, ErrInsufficientStock)
+
+		err = fx.RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: art.ID, Quantity: 3},
+			{ID: art.ID, Quantity: 4},
+		})
+		require.NoError(t, err)
+
+		art, err = fx.GetArticle(fx.ctx, art.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, art.Stock)
+	})
+}
+
+func TestStockError(t *testing.T) {
+	violation := &pgconn.PgError{
+		Code:           checkViolation,
+		ConstraintName: stockConstraint,
+		Detail:         "Failing row contains (42, leg, -5, pcs, f, null, 0, f, 0, 3).",
+	}
+
+	err := stockError(fmt.Errorf("exec: %w", violation))
+	require.ErrorIs(t, err, ErrInsufficientStock)
+	assert.EqualError(t, err, "insufficient available stock: article 42")
+
+	violation.Detail = ""
+	require.Equal(t, ErrInsufficientStock, stockError(violation))
+
+	other := &pgconn.PgError{Code: checkViolation, ConstraintName: "articles_reserved_check"}
+	require.Equal(t, other, stockError(other))
+
+	err = errors.New("connection lost")
+	require.Equal(t, err, stockError(err))
+	require.NoError(t, stockError(nil))
+}
+
+func TestImpl_ReserveArticles(t *testing.T) {
+	t.Run("should reserve available stock", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art1 := fx.createArticle(models.Article{Stock: 10})
+		art2 := fx.createArticle(models.Article{Stock: 5})
+
+		err := fx.ReserveArticles(fx.ctx, []models.ProductArticle{
+			{ID: art1.ID, Quantity: 4},
+			{ID: art2.ID, Quantity: 5},
+		})
+		require.NoError(t, err)
+
+		art1, err = fx.GetArticle(fx.ctx, art1.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 10, art1.Stock)
+		assert.EqualValues(t, 4, art1.Reserved)
+		assert.EqualValues(t, 6, art1.Available())
+	})
+
+	t.Run("should not reserve more than available", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art1 := fx.createArticle(models.Article{Stock: 10})
+		art2 := fx.createArticle(models.Article{Stock: 5})
+		err := fx.ReserveArticles(fx.ctx, []models.ProductArticle{{ID: art2.ID, Quantity: 3}})
+		require.NoError(t, err)
+
+		err = fx.ReserveArticles(fx.ctx, []models.ProductArticle{
+			{ID: art1.ID, Quantity: 1},
+			{ID: art2.ID, Quantity: 3},
+		})
+
+		require.ErrorIs(t, err, ErrInsufficientStock)
+	})
+}
+
+func TestImpl_RemoveReserved(t *testing.T) {
+	t.Run("should remove stock and reservation", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{Stock: 10})
+		err := fx.ReserveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 6}})
+		require.NoError(t, err)
+
+		err = fx.RemoveReserved(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 4}})
+		require.NoError(t, err)
+		err = fx.ReleaseArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 2}})
+		require.NoError(t, err)
 
-		art4, err = fx.GetArticle(fx.ctx, art4.ID)
+		art, err = fx.GetArticle(fx.ctx, art.ID)
 		require.NoError(t, err)
-		assert.EqualValues(t, 6, art4.Stock)
+		assert.EqualValues(t, 6, art.Stock)
+		assert.EqualValues(t, 0, art.Reserved)
+	})
+
+	t.Run("should return ErrInsufficientStock naming the article", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{Stock: 10})
+		err := fx.ReserveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 3}})
+		require.NoError(t, err)
+
+		err = fx.RemoveReserved(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 4}})
+
+		require.ErrorIs(t, err, ErrInsufficientStock)
+		assert.Contains(t, err.Error(), fmt.Sprintf("article %d", art.ID))
+	})
+}
+
+func TestImpl_UpdateReorderPolicy(t *testing.T) {
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		point := int32(5)
+		_, err := fx.UpdateReorderPolicy(fx.ctx, testhelpers.RandomInt32(), 1, &point, 10)
+
+		require.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("should update policy", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{})
+		point := int32(5)
+
+		updated, err := fx.UpdateReorderPolicy(fx.ctx, art.ID, art.Version, &point, 10)
+		require.NoError(t, err)
+		assert.Equal(t, art.Version+1, updated.Version)
+
+		art, err = fx.GetArticle(fx.ctx, art.ID)
+		require.NoError(t, err)
+		require.NotNil(t, art.ReorderPoint)
+		assert.EqualValues(t, 5, *art.ReorderPoint)
+		assert.EqualValues(t, 10, art.ReorderQuantity)
+	})
+
+	t.Run("should return ErrVersionConflict", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{})
+		point := int32(5)
+
+		_, err := fx.UpdateReorderPolicy(fx.ctx, art.ID, art.Version+1, &point, 10)
+
+		require.Equal(t, ErrVersionConflict, err)
+	})
+}
+
+func TestImpl_UpdateArticle(t *testing.T) {
+	t.Run("should update article once per version", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{})
+		art.Name = testhelpers.RandomString()
+
+		updated, err := fx.UpdateArticle(fx.ctx, art)
+		require.NoError(t, err)
+		assert.Equal(t, art.Name, updated.Name)
+		assert.Equal(t, art.Version+1, updated.Version)
+
+		_, err = fx.UpdateArticle(fx.ctx, art)
+		require.Equal(t, ErrVersionConflict, err)
+	})
+
+	t.Run("should keep version on stock change", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		art := fx.createArticle(models.Article{})
+		err := fx.AddArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 1}})
+		require.NoError(t, err)
+		err = fx.ReserveArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 1}})
+		require.NoError(t, err)
+		_, err = fx.SyncLowStock(fx.ctx, []int32{art.ID})
+		require.NoError(t, err)
+
+		updated, err := fx.UpdateArticle(fx.ctx, art)
+		require.NoError(t, err)
+		assert.Equal(t, art.Version+1, updated.Version)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.UpdateArticle(fx.ctx, models.Article{ID: testhelpers.RandomInt32(), Version: 1})
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestImpl_SyncLowStock(t *testing.T) {
+	t.Run("should flag articles once per crossing", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		point := int32(5)
+		low := fx.createArticle(models.Article{Stock: 5, ReorderPoint: &point})
+		fx.createArticle(models.Article{Stock: 6, ReorderPoint: &point})
+		fx.createArticle(models.Article{Stock: 1})
+
+		items, err := fx.SyncLowStock(fx.ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, low.ID, items[0].ID)
+		assert.True(t, items[0].LowStock)
+
+		items, err = fx.SyncLowStock(fx.ctx, []int32{low.ID})
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		lowStock, err := fx.GetLowStockArticles(fx.ctx)
+		require.NoError(t, err)
+		require.Len(t, lowStock, 1)
+		assert.Equal(t, low.ID, lowStock[0].ID)
+	})
+
+	t.Run("should reset flag when stock is replenished", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		point := int32(5)
+		art := fx.createArticle(models.Article{Stock: 1, ReorderPoint: &point})
+		_, err := fx.SyncLowStock(fx.ctx, nil)
+		require.NoError(t, err)
+
+		err = fx.AddArticles(fx.ctx, []models.ProductArticle{{ID: art.ID, Quantity: 10}})
+		require.NoError(t, err)
+
+		items, err := fx.SyncLowStock(fx.ctx, []int32{art.ID})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.False(t, items[0].LowStock)
 	})
 }
 
@@ -154,8 +501,16 @@ func (fx *fixture) createArticle(item models.Article) models.Article {
 		item.Name = testhelpers.RandomString()
 		item.Stock = int32(testhelpers.RandomIntRange(1, 100))
 	}
-	const query = `INSERT INTO articles (name, stock) VALUES ($1, $2) RETURNING id`
-	err := fx.db.QueryRow(fx.ctx, query, item.Name, item.Stock).Scan(&item.ID)
+	if item.Unit == "" {
+		item.Unit = "pcs"
+	}
+	const query = `
+		INSERT INTO articles (name, stock, unit, serialized, reorder_point, reorder_quantity)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, version
+	`
+	err := fx.db.QueryRow(fx.ctx, query, item.Name, item.Stock, item.Unit, item.Serialized, item.ReorderPoint, item.ReorderQuantity).
+		Scan(&item.ID, &item.Version)
 	require.NoError(fx.t, err)
 	return item
 }
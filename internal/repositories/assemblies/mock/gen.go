@@ -0,0 +1,2 @@
+//go:generate mockgen -source ../repo.go -destination mock.gen.go -package mockAssembliesRepo
+package mockAssembliesRepo
@@ -0,0 +1,116 @@
+package assemblies
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("assembly order not found")
+)
+
+type Repository interface {
+	CreateAssemblyOrder(ctx context.Context, item models.AssemblyOrder) (models.AssemblyOrder, error)
+	GetAssemblyOrderForUpdate(ctx context.Context, id int32) (models.AssemblyOrder, error)
+	CompleteAssemblyOrder(ctx context.Context, id int32) (models.AssemblyOrder, error)
+	CreateDisassembly(ctx context.Context, item models.Disassembly) (models.Disassembly, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) CreateAssemblyOrder(ctx context.Context, item models.AssemblyOrder) (models.AssemblyOrder, error) {
+	const query = `
+		INSERT INTO assembly_orders (product_id, quantity)
+		VALUES ($1, $2)
+		RETURNING id, product_id, quantity, status, created_at, completed_at
+	`
+	return repo.queryAssemblyOrder(ctx, query, item.ProductID, item.Quantity)
+}
+
+// GetAssemblyOrderForUpdate locks the order until the end of the transaction
+func (repo *impl) GetAssemblyOrderForUpdate(ctx context.Context, id int32) (models.AssemblyOrder, error) {
+	const query = `
+		SELECT id, product_id, quantity, status, created_at, completed_at
+		FROM assembly_orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return repo.queryAssemblyOrder(ctx, query, id)
+}
+
+func (repo *impl) CompleteAssemblyOrder(ctx context.Context, id int32) (models.AssemblyOrder, error) {
+	const query = `
+		UPDATE assembly_orders
+		SET status       = 'completed',
+			completed_at = now()
+		WHERE id = $1
+		RETURNING id, product_id, quantity, status, created_at, completed_at
+	`
+	return repo.queryAssemblyOrder(ctx, query, id)
+}
+
+func (repo *impl) queryAssemblyOrder(ctx context.Context, query string, args ...any) (models.AssemblyOrder, error) {
+	var item models.AssemblyOrder
+	err := repo.conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&item.ID,
+		&item.ProductID,
+		&item.Quantity,
+		&item.Status,
+		&item.CreatedAt,
+		&item.CompletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.AssemblyOrder{}, ErrNotFound
+		}
+		return models.AssemblyOrder{}, err
+	}
+	return item, nil
+}
+
+// CreateDisassembly inserts the disassembly with its components, it must be called within a transaction
+func (repo *impl) CreateDisassembly(ctx context.Context, item models.Disassembly) (models.Disassembly, error) {
+	const query = `
+		INSERT INTO disassemblies (product_id, quantity, from_assembled)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	const componentsQuery = `
+		INSERT INTO disassembly_components (disassembly_id, article_id, expected, recovered, scrapped)
+		SELECT $1, unnest($2::int[]), unnest($3::int[]), unnest($4::int[]), unnest($5::int[])
+	`
+	conn := repo.conn(ctx)
+	err := conn.QueryRow(ctx, query, item.ProductID, item.Quantity, item.FromAssembled).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return models.Disassembly{}, err
+	}
+
+	n := len(item.Components)
+	ids, expected, recovered, scrapped := make([]int32, n), make([]int32, n), make([]int32, n), make([]int32, n)
+	for i, c := range item.Components {
+		ids[i], expected[i], recovered[i], scrapped[i] = c.ArticleID, c.Expected, c.Recovered, c.Scrapped
+	}
+	_, err = conn.Exec(ctx, componentsQuery, item.ID, ids, expected, recovered, scrapped)
+	if err != nil {
+		return models.Disassembly{}, err
+	}
+	return item, nil
+}
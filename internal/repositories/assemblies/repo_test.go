@@ -0,0 +1,98 @@
+package assemblies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CompleteAssemblyOrder(t *testing.T) {
+	t.Run("should complete open order", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order, err := fx.CreateAssemblyOrder(fx.ctx, models.AssemblyOrder{
+			ProductID: testhelpers.RandomInt32(),
+			Quantity:  3,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.AssemblyOrderOpen, order.Status)
+		assert.Nil(t, order.CompletedAt)
+
+		item, err := fx.GetAssemblyOrderForUpdate(fx.ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order, item)
+
+		completed, err := fx.CompleteAssemblyOrder(fx.ctx, order.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.AssemblyOrderCompleted, completed.Status)
+		assert.NotNil(t, completed.CompletedAt)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.CompleteAssemblyOrder(fx.ctx, testhelpers.RandomInt32())
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestImpl_CreateDisassembly(t *testing.T) {
+	t.Run("should create disassembly with components", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item := models.Disassembly{
+			ProductID: testhelpers.RandomInt32(),
+			Quantity:  2,
+			Components: []models.DisassembledComponent{
+				{ArticleID: 1, Expected: 8, Recovered: 7, Scrapped: 1},
+				{ArticleID: 2, Expected: 2, Recovered: 2},
+			},
+		}
+		created, err := fx.CreateDisassembly(fx.ctx, item)
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		var recovered int32
+		err = fx.db.QueryRow(fx.ctx, "SELECT sum(recovered) FROM disassembly_components WHERE disassembly_id = $1", created.ID).Scan(&recovered)
+		require.NoError(t, err)
+		assert.EqualValues(t, 9, recovered)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE assembly_orders, disassemblies, disassembly_components")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
@@ -0,0 +1,129 @@
+package backorders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+const checkViolation = "23514"
+
+var (
+	ErrNotFound      = errors.New("backorder not found")
+	ErrOverFulfilled = errors.New("fulfilled quantity exceeds the backordered quantity")
+)
+
+type Repository interface {
+	CreateBackorder(ctx context.Context, item models.Backorder) (models.Backorder, error)
+	GetBackorders(ctx context.Context, openOnly bool) ([]models.Backorder, error)
+	GetOpenBackordersForUpdate(ctx context.Context) ([]models.Backorder, error)
+	FulfilBackorder(ctx context.Context, id, quantity int32) (models.Backorder, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) CreateBackorder(ctx context.Context, item models.Backorder) (models.Backorder, error) {
+	const query = `
+		INSERT INTO backorders (sale_id, product_id, quantity)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := repo.conn(ctx).QueryRow(ctx, query, item.SaleID, item.ProductID, item.Quantity).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return models.Backorder{}, err
+	}
+	return item, nil
+}
+
+// GetBackorders lists backorders oldest first, openOnly skips the fulfilled ones
+func (repo *impl) GetBackorders(ctx context.Context, openOnly bool) ([]models.Backorder, error) {
+	const query = `
+		SELECT id, sale_id, product_id, quantity, fulfilled, created_at, fulfilled_at
+		FROM backorders
+		WHERE NOT $1 OR fulfilled < quantity
+		ORDER BY created_at, id
+	`
+	return repo.queryBackorders(ctx, query, openOnly)
+}
+
+// GetOpenBackordersForUpdate lists the backorders which are not fulfilled yet, oldest first,
+// and locks them until the end of the transaction
+func (repo *impl) GetOpenBackordersForUpdate(ctx context.Context) ([]models.Backorder, error) {
+	const query = `
+		SELECT id, sale_id, product_id, quantity, fulfilled, created_at, fulfilled_at
+		FROM backorders
+		WHERE fulfilled < quantity
+		ORDER BY created_at, id
+		FOR UPDATE
+	`
+	return repo.queryBackorders(ctx, query)
+}
+
+// FulfilBackorder increments the fulfilled quantity, the backorder is marked fulfilled once complete
+func (repo *impl) FulfilBackorder(ctx context.Context, id, quantity int32) (models.Backorder, error) {
+	const query = `
+		UPDATE backorders
+		SET fulfilled    = fulfilled + $2,
+			fulfilled_at = CASE WHEN fulfilled + $2 = quantity THEN now() END
+		WHERE id = $1
+		RETURNING id, sale_id, product_id, quantity, fulfilled, created_at, fulfilled_at
+	`
+	items, err := repo.queryBackorders(ctx, query, id, quantity)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == checkViolation {
+			return models.Backorder{}, ErrOverFulfilled
+		}
+		return models.Backorder{}, err
+	}
+	if len(items) == 0 {
+		return models.Backorder{}, ErrNotFound
+	}
+	return items[0], nil
+}
+
+func (repo *impl) queryBackorders(ctx context.Context, query string, args ...any) ([]models.Backorder, error) {
+	var items []models.Backorder
+	rows, err := repo.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.Backorder
+		err := rows.Scan(
+			&item.ID,
+			&item.SaleID,
+			&item.ProductID,
+			&item.Quantity,
+			&item.Fulfilled,
+			&item.CreatedAt,
+			&item.FulfilledAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,112 @@
+package backorders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_GetBackorders(t *testing.T) {
+	t.Run("should list open backorders oldest first", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		b1 := fx.createBackorder(t, 2)
+		b2 := fx.createBackorder(t, 1)
+		_, err := fx.FulfilBackorder(fx.ctx, b2.ID, 1)
+		require.NoError(t, err)
+
+		items, err := fx.GetBackorders(fx.ctx, true)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, b1.ID, items[0].ID)
+
+		items, err = fx.GetBackorders(fx.ctx, false)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, b2.ID, items[1].ID)
+		assert.NotNil(t, items[1].FulfilledAt)
+	})
+}
+
+func TestImpl_FulfilBackorder(t *testing.T) {
+	t.Run("should fulfil in parts", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		b := fx.createBackorder(t, 3)
+
+		item, err := fx.FulfilBackorder(fx.ctx, b.ID, 2)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, item.Outstanding())
+		assert.Nil(t, item.FulfilledAt)
+
+		item, err = fx.FulfilBackorder(fx.ctx, b.ID, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, item.Outstanding())
+		assert.NotNil(t, item.FulfilledAt)
+	})
+
+	t.Run("should reject over fulfilment", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		b := fx.createBackorder(t, 1)
+
+		_, err := fx.FulfilBackorder(fx.ctx, b.ID, 2)
+
+		require.Equal(t, ErrOverFulfilled, err)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.FulfilBackorder(fx.ctx, testhelpers.RandomInt32(), 1)
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE backorders")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
+
+func (fx *fixture) createBackorder(t *testing.T, quantity int32) models.Backorder {
+	item, err := fx.CreateBackorder(fx.ctx, models.Backorder{
+		SaleID:    testhelpers.RandomInt32(),
+		ProductID: testhelpers.RandomInt32(),
+		Quantity:  quantity,
+	})
+	require.NoError(t, err)
+	return item
+}
@@ -0,0 +1,313 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound     = errors.New("order not found")
+	ErrLineNotFound = errors.New("order line not found")
+)
+
+type Repository interface {
+	CreateOrder(ctx context.Context, item models.Order) (models.Order, error)
+	GetOrder(ctx context.Context, id int32) (models.Order, error)
+	GetOrderForUpdate(ctx context.Context, id int32) (models.Order, error)
+	GetOrders(ctx context.Context, status models.OrderStatus) ([]models.Order, error)
+	UpdateStatus(ctx context.Context, id int32, status models.OrderStatus) error
+	AllocateLine(ctx context.Context, lineID, assembled int32, reserved []models.ProductArticle) error
+	ShipLine(ctx context.Context, lineID, saleID int32) error
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+// CreateOrder inserts the order with its lines, it must be called within a transaction
+func (repo *impl) CreateOrder(ctx context.Context, item models.Order) (models.Order, error) {
+	const orderQuery = `
+		INSERT INTO orders (status)
+		VALUES ($1)
+		RETURNING id, created_at, updated_at
+	`
+	const lineQuery = `
+		INSERT INTO order_lines (order_id, product_id, quantity)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	conn := repo.conn(ctx)
+	err := conn.QueryRow(ctx, orderQuery, item.Status).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	lines := make([]models.OrderLine, 0, len(item.Lines))
+	for _, line := range item.Lines {
+		err := conn.QueryRow(ctx, lineQuery, item.ID, line.ProductID, line.Quantity).Scan(&line.ID)
+		if err != nil {
+			return models.Order{}, err
+		}
+		lines = append(lines, line)
+	}
+	item.Lines = lines
+	return item, nil
+}
+
+func (repo *impl) GetOrder(ctx context.Context, id int32) (models.Order, error) {
+	const query = `
+		SELECT id, status, created_at, updated_at
+		FROM orders
+		WHERE id = $1
+	`
+	return repo.getOrder(ctx, query, id)
+}
+
+// GetOrderForUpdate locks the order until the end of the transaction
+func (repo *impl) GetOrderForUpdate(ctx context.Context, id int32) (models.Order, error) {
+	const query = `
+		SELECT id, status, created_at, updated_at
+		FROM orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return repo.getOrder(ctx, query, id)
+}
+
+func (repo *impl) getOrder(ctx context.Context, query string, id int32) (models.Order, error) {
+	var item models.Order
+	err := scanOrder(repo.conn(ctx).QueryRow(ctx, query, id), &item)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Order{}, ErrNotFound
+		}
+		return models.Order{}, err
+	}
+
+	lines, err := repo.getLines(ctx, []int32{id})
+	if err != nil {
+		return models.Order{}, err
+	}
+	item.Lines = lines[id]
+	return item, nil
+}
+
+// GetOrders lists orders with their lines, an empty status lists all orders
+func (repo *impl) GetOrders(ctx context.Context, status models.OrderStatus) ([]models.Order, error) {
+	const query = `
+		SELECT id, status, created_at, updated_at
+		FROM orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY id
+	`
+
+	var items []models.Order
+	rows, err := repo.conn(ctx).Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int32
+	for rows.Next() {
+		var item models.Order
+		err := scanOrder(rows, &item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+		ids = append(ids, item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	lines, err := repo.getLines(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Lines = lines[items[i].ID]
+	}
+	return items, nil
+}
+
+func (repo *impl) getLines(ctx context.Context, orderIDs []int32) (map[int32][]models.OrderLine, error) {
+	const query = `
+		SELECT order_id, id, product_id, quantity, assembled, sale_id
+		FROM order_lines
+		WHERE order_id = ANY ($1)
+		ORDER BY id
+	`
+
+	items := make(map[int32][]models.OrderLine, len(orderIDs))
+	rows, err := repo.conn(ctx).Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	var lineIDs []int32
+	for rows.Next() {
+		var orderID int32
+		var item models.OrderLine
+		err := rows.Scan(
+			&orderID,
+			&item.ID,
+			&item.ProductID,
+			&item.Quantity,
+			&item.Assembled,
+			&item.SaleID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items[orderID] = append(items[orderID], item)
+		lineIDs = append(lineIDs, item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(lineIDs) == 0 {
+		return items, nil
+	}
+
+	reserved, err := repo.getReservations(ctx, lineIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, lines := range items {
+		for i := range lines {
+			lines[i].Reserved = reserved[lines[i].ID]
+		}
+	}
+	return items, nil
+}
+
+func (repo *impl) getReservations(ctx context.Context, lineIDs []int32) (map[int32][]models.ProductArticle, error) {
+	const query = `
+		SELECT order_line_id, article_id, quantity
+		FROM order_reservations
+		WHERE order_line_id = ANY ($1)
+		ORDER BY order_line_id, article_id
+	`
+
+	items := make(map[int32][]models.ProductArticle, len(lineIDs))
+	rows, err := repo.conn(ctx).Query(ctx, query, lineIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lineID int32
+		var item models.ProductArticle
+		err := rows.Scan(&lineID, &item.ID, &item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items[lineID] = append(items[lineID], item)
+	}
+	return items, rows.Err()
+}
+
+func (repo *impl) UpdateStatus(ctx context.Context, id int32, status models.OrderStatus) error {
+	const query = `
+		UPDATE orders
+		SET status     = $2,
+			updated_at = now()
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AllocateLine records the assembled units and the article quantities reserved for the line,
+// it must be called within a transaction
+func (repo *impl) AllocateLine(ctx context.Context, lineID, assembled int32, reserved []models.ProductArticle) error {
+	const lineQuery = `
+		UPDATE order_lines
+		SET assembled = $2
+		WHERE id = $1
+	`
+	const reservationQuery = `
+		INSERT INTO order_reservations (order_line_id, article_id, quantity)
+		SELECT $1, *
+		FROM unnest($2::int[], $3::int[])
+	`
+	conn := repo.conn(ctx)
+	tag, err := conn.Exec(ctx, lineQuery, lineID, assembled)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLineNotFound
+	}
+	if len(reserved) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, len(reserved))
+	quantities := make([]int32, len(reserved))
+	for i, item := range reserved {
+		ids[i] = item.ID
+		quantities[i] = item.Quantity
+	}
+	_, err = conn.Exec(ctx, reservationQuery, lineID, ids, quantities)
+	return err
+}
+
+// ShipLine links the line to the sale recorded when it was shipped
+func (repo *impl) ShipLine(ctx context.Context, lineID, saleID int32) error {
+	const query = `
+		UPDATE order_lines
+		SET sale_id = $2
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, lineID, saleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLineNotFound
+	}
+	return nil
+}
+
+func scanOrder(row pgx.Row, item *models.Order) error {
+	return row.Scan(
+		&item.ID,
+		&item.Status,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+}
@@ -0,0 +1,133 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateOrder(t *testing.T) {
+	t.Run("should create order with lines", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+
+		item, err := fx.GetOrder(fx.ctx, order.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderPlaced, item.Status)
+		assert.Equal(t, order.Lines, item.Lines)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.GetOrder(fx.ctx, testhelpers.RandomInt32())
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestImpl_GetOrders(t *testing.T) {
+	t.Run("should filter by status", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		placed := fx.createOrder(t)
+		cancelled := fx.createOrder(t)
+		err := fx.UpdateStatus(fx.ctx, cancelled.ID, models.OrderCancelled)
+		require.NoError(t, err)
+
+		items, err := fx.GetOrders(fx.ctx, models.OrderCancelled)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, cancelled.ID, items[0].ID)
+
+		items, err = fx.GetOrders(fx.ctx, "")
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, placed.ID, items[0].ID)
+	})
+}
+
+func TestImpl_AllocateLine(t *testing.T) {
+	t.Run("should record reservations and the sale", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+		line := order.Lines[0]
+		reserved := []models.ProductArticle{
+			{ID: testhelpers.RandomInt32(), Quantity: 4},
+		}
+
+		err := fx.AllocateLine(fx.ctx, line.ID, 1, reserved)
+		require.NoError(t, err)
+		err = fx.ShipLine(fx.ctx, line.ID, 42)
+		require.NoError(t, err)
+
+		item, err := fx.GetOrder(fx.ctx, order.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, item.Lines[0].Assembled)
+		assert.Equal(t, reserved, item.Lines[0].Reserved)
+		require.NotNil(t, item.Lines[0].SaleID)
+		assert.EqualValues(t, 42, *item.Lines[0].SaleID)
+		assert.Empty(t, item.Lines[1].Reserved)
+	})
+
+	t.Run("should return ErrLineNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		err := fx.AllocateLine(fx.ctx, testhelpers.RandomInt32(), 0, nil)
+
+		require.Equal(t, ErrLineNotFound, err)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE orders, order_lines, order_reservations")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
+
+func (fx *fixture) createOrder(t *testing.T) models.Order {
+	order, err := fx.CreateOrder(fx.ctx, models.Order{
+		Status: models.OrderPlaced,
+		Lines: []models.OrderLine{
+			{ProductID: testhelpers.RandomInt32(), Quantity: 2},
+			{ProductID: testhelpers.RandomInt32(), Quantity: 1},
+		},
+	})
+	require.NoError(t, err)
+	return order
+}
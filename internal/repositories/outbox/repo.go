@@ -0,0 +1,118 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+type Repository interface {
+	ClaimEvents(ctx context.Context, limit int, until time.Time) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, id int64, reason string) error
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+// ClaimEvents claims undelivered events which are not dead and due for an attempt until the given time, oldest first.
+// Claimed events are not due for other relays, they are due again if the claim expires
+// without the event being marked.
+func (repo *impl) ClaimEvents(ctx context.Context, limit int, until time.Time) ([]models.OutboxEvent, error) {
+	const query = `
+		UPDATE outbox o
+		SET next_attempt_at = $2
+		FROM (
+			SELECT id
+			FROM outbox
+			WHERE delivered_at IS NULL
+			  AND dead_at IS NULL
+			  AND next_attempt_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE o.id = due.id
+		RETURNING o.id, o.event_type, o.payload, o.created_at, o.attempts
+	`
+
+	var items []models.OutboxEvent
+	rows, err := repo.conn(ctx).Query(ctx, query, limit, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.OutboxEvent
+		err := rows.Scan(&item.ID, &item.Type, &item.Payload, &item.CreatedAt, &item.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(items, func(a, b models.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return items, nil
+}
+
+func (repo *impl) MarkDelivered(ctx context.Context, ids []int64) error {
+	const query = `
+		UPDATE outbox
+		SET delivered_at = now(),
+			last_error   = NULL
+		WHERE id = ANY ($1)
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, ids)
+	return err
+}
+
+// MarkFailed records a failed delivery, the event is retried at nextAttemptAt
+func (repo *impl) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	const query = `
+		UPDATE outbox
+		SET attempts        = attempts + 1,
+			last_error      = $2,
+			next_attempt_at = $3
+		WHERE id = $1
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, id, reason, nextAttemptAt)
+	return err
+}
+
+// MarkDead records an event which can never be delivered, it is not retried
+func (repo *impl) MarkDead(ctx context.Context, id int64, reason string) error {
+	const query = `
+		UPDATE outbox
+		SET attempts   = attempts + 1,
+			last_error = $2,
+			dead_at    = now()
+		WHERE id = $1
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, id, reason)
+	return err
+}
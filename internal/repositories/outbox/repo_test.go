@@ -0,0 +1,165 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_ClaimEvents(t *testing.T) {
+	t.Run("should record events in the transaction of the change", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		var articleID int32
+		err := fx.db.QueryRow(fx.ctx, "INSERT INTO articles (name, stock) VALUES ($1, 5) RETURNING id", testhelpers.RandomString()).
+			Scan(&articleID)
+		require.NoError(t, err)
+		_, err = fx.db.Exec(fx.ctx, "UPDATE articles SET stock = 3 WHERE id = $1", articleID)
+		require.NoError(t, err)
+		_, err = fx.db.Exec(fx.ctx, "INSERT INTO sales (product_id, quantity) VALUES (7, 2)")
+		require.NoError(t, err)
+
+		items, err := fx.ClaimEvents(fx.ctx, 10, time.Now().Add(time.Minute))
+
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, "ArticleStockChanged", items[0].Type)
+		assert.Equal(t, "ArticleStockChanged", items[1].Type)
+		assert.Equal(t, "ProductSold", items[2].Type)
+
+		var payload map[string]map[string]any
+		require.NoError(t, json.Unmarshal(items[1].Payload, &payload))
+		assert.Equal(t, map[string]any{
+			"articleId":   float64(articleID),
+			"stockBefore": float64(5),
+			"stockAfter":  float64(3),
+			"reserved":    float64(0),
+		}, payload["articleStockChanged"])
+
+		claimed, err := fx.ClaimEvents(fx.ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+	})
+
+	t.Run("should write payloads of the event message", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		var articleID, productID int32
+		err := fx.db.QueryRow(fx.ctx, "INSERT INTO articles (name, stock) VALUES ($1, 5) RETURNING id", testhelpers.RandomString()).
+			Scan(&articleID)
+		require.NoError(t, err)
+		_, err = fx.db.Exec(fx.ctx, "UPDATE articles SET stock = 3, reserved = 1 WHERE id = $1", articleID)
+		require.NoError(t, err)
+		articles := fmt.Sprintf(`[{"ID": %d, "Quantity": 2, "Unit": "pcs"}, {"ID": %d, "Quantity": 1}]`, articleID, articleID)
+		err = fx.db.QueryRow(fx.ctx,
+			"INSERT INTO products (name, articles, price_minor, currency) VALUES ($1, $2, 1250, 'EUR') RETURNING id",
+			testhelpers.RandomString(), articles,
+		).Scan(&productID)
+		require.NoError(t, err)
+		_, err = fx.db.Exec(fx.ctx, "INSERT INTO sales (product_id, quantity) VALUES ($1, 2)", productID)
+		require.NoError(t, err)
+		_, err = fx.db.Exec(fx.ctx, "DELETE FROM products WHERE id = $1", productID)
+		require.NoError(t, err)
+
+		items, err := fx.ClaimEvents(fx.ctx, 10, time.Now().Add(time.Minute))
+
+		require.NoError(t, err)
+		require.Len(t, items, 5)
+		for _, item := range items {
+			var event warehousepb.Event
+			err = protojson.UnmarshalOptions{DiscardUnknown: false}.Unmarshal(item.Payload, &event)
+			require.NoError(t, err, item.Type)
+			require.NotNil(t, event.Payload, item.Type)
+		}
+	})
+}
+
+func TestImpl_MarkDelivered(t *testing.T) {
+	t.Run("should skip delivered and failed events until due", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.db.Exec(fx.ctx, "INSERT INTO sales (product_id, quantity) VALUES (1, 1), (2, 1), (3, 1)")
+		require.NoError(t, err)
+		// claims expiring immediately keep the events due
+		items, err := fx.ClaimEvents(fx.ctx, 10, time.Now())
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		err = fx.MarkDelivered(fx.ctx, []int64{items[0].ID})
+		require.NoError(t, err)
+		err = fx.MarkFailed(fx.ctx, items[1].ID, "timeout", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		pending, err := fx.ClaimEvents(fx.ctx, 10, time.Now())
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, items[2].ID, pending[0].ID)
+
+		err = fx.MarkFailed(fx.ctx, items[2].ID, "timeout", time.Now())
+		require.NoError(t, err)
+		pending, err = fx.ClaimEvents(fx.ctx, 10, time.Now())
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.EqualValues(t, 1, pending[0].Attempts)
+	})
+}
+
+func TestImpl_MarkDead(t *testing.T) {
+	t.Run("should not claim dead events again", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.db.Exec(fx.ctx, "INSERT INTO sales (product_id, quantity) VALUES (1, 1)")
+		require.NoError(t, err)
+		items, err := fx.ClaimEvents(fx.ctx, 10, time.Now())
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+
+		err = fx.MarkDead(fx.ctx, items[0].ID, "malformed payload")
+		require.NoError(t, err)
+
+		pending, err := fx.ClaimEvents(fx.ctx, 10, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE outbox, articles, products, sales")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
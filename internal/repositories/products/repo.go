@@ -8,16 +8,25 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"warehouse/internal/db"
 	"warehouse/internal/models"
 )
 
+// ChangesChannel is notified with the product ID whenever the product is created, changed or deleted
+const ChangesChannel = "product_changes"
+
 var (
-	ErrNotFound = errors.New("product not found")
+	ErrNotFound        = errors.New("product not found")
+	ErrVersionConflict = errors.New("product was modified concurrently")
 )
 
 type Repository interface {
 	GetProducts(ctx context.Context) ([]models.Product, error)
 	GetProduct(ctx context.Context, id int32) (models.Product, error)
+	GetProductsByID(ctx context.Context, ids []int32) ([]models.Product, error)
+	AddAssembled(ctx context.Context, id, quantity int32) error
+	TakeAssembled(ctx context.Context, id, quantity int32) (int32, error)
+	UpdateProduct(ctx context.Context, item models.Product) (models.Product, error)
 }
 
 type impl struct {
@@ -30,15 +39,130 @@ func NewRepository(db *pgxpool.Pool) Repository {
 	}
 }
 
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
 func (repo *impl) GetProducts(ctx context.Context) ([]models.Product, error) {
 	const query = `
-		SELECT id, name, price, articles
+		SELECT id, name, price_minor, currency, articles, assembled, version
+		FROM products
+		ORDER BY id
+	`
+	return repo.queryProducts(ctx, query)
+}
+
+func (repo *impl) GetProductsByID(ctx context.Context, ids []int32) ([]models.Product, error) {
+	const query = `
+		SELECT id, name, price_minor, currency, articles, assembled, version
 		FROM products
+		WHERE id = ANY ($1)
 		ORDER BY id
 	`
+	return repo.queryProducts(ctx, query, ids)
+}
+
+func (repo *impl) GetProduct(ctx context.Context, id int32) (models.Product, error) {
+	const query = `
+		SELECT id, name, price_minor, currency, articles, assembled, version
+		FROM products
+		WHERE id = $1
+	`
+	var item models.Product
+	err := scanProduct(repo.conn(ctx).QueryRow(ctx, query, id), &item)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Product{}, ErrNotFound
+		}
+		return models.Product{}, err
+	}
+	return item, nil
+}
+
+// AddAssembled increments the assembled stock of the product
+func (repo *impl) AddAssembled(ctx context.Context, id, quantity int32) error {
+	const query = `
+		UPDATE products
+		SET assembled = assembled + $2
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id, quantity)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TakeAssembled removes up to quantity assembled units and returns how many were taken
+func (repo *impl) TakeAssembled(ctx context.Context, id, quantity int32) (int32, error) {
+	const query = `
+		WITH old AS (
+			SELECT id, assembled
+			FROM products
+			WHERE id = $1
+			FOR UPDATE
+		)
+		UPDATE products p
+		SET assembled = p.assembled - least(old.assembled, $2)
+		FROM old
+		WHERE p.id = old.id
+		RETURNING least(old.assembled, $2)
+	`
+	var taken int32
+	err := repo.conn(ctx).QueryRow(ctx, query, id, quantity).Scan(&taken)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return taken, nil
+}
 
+// UpdateProduct updates the name, price and articles of the product if it still has the given version
+func (repo *impl) UpdateProduct(ctx context.Context, item models.Product) (models.Product, error) {
+	const query = `
+		UPDATE products
+		SET name        = $3,
+			price_minor = $4,
+			currency    = $5,
+			articles    = $6
+		WHERE id = $1
+		  AND version = $2
+		RETURNING id, name, price_minor, currency, articles, assembled, version
+	`
+	var updated models.Product
+	err := scanProduct(repo.conn(ctx).QueryRow(
+		ctx,
+		query,
+		item.ID,
+		item.Version,
+		item.Name,
+		item.Price.Amount,
+		item.Price.Currency,
+		item.Articles,
+	), &updated)
+	if err == nil {
+		return updated, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return models.Product{}, err
+	}
+
+	// no row means the product does not exist or its version has changed
+	_, err = repo.GetProduct(ctx, item.ID)
+	if err != nil {
+		return models.Product{}, err
+	}
+	return models.Product{}, ErrVersionConflict
+}
+
+func (repo *impl) queryProducts(ctx context.Context, query string, args ...any) ([]models.Product, error) {
 	var items []models.Product
-	rows, err := repo.db.Query(ctx, query)
+	rows, err := repo.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query rows: %w", err)
 	}
@@ -46,29 +170,24 @@ func (repo *impl) GetProducts(ctx context.Context) ([]models.Product, error) {
 
 	for rows.Next() {
 		var item models.Product
-		err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Articles)
+		err := scanProduct(rows, &item)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
 		items = append(items, item)
 	}
-	return items, nil
+	return items, rows.Err()
 }
 
-func (repo *impl) GetProduct(ctx context.Context, id int32) (models.Product, error) {
-	const query = `
-		SELECT id, name, price, articles
-		FROM products
-		WHERE id = $1
-	`
-	var item models.Product
-	err := repo.db.QueryRow(ctx, query, id).Scan(&item.ID, &item.Name, &item.Price, &item.Articles)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return models.Product{}, ErrNotFound
-		}
-		return models.Product{}, err
-	}
-	return item, nil
+func scanProduct(row pgx.Row, item *models.Product) error {
+	return row.Scan(
+		&item.ID,
+		&item.Name,
+		&item.Price.Amount,
+		&item.Price.Currency,
+		&item.Articles,
+		&item.Assembled,
+		&item.Version,
+	)
 }
@@ -39,6 +39,22 @@ func TestImpl_GetProducts(t *testing.T) {
 	})
 }
 
+func TestImpl_GetProductsByID(t *testing.T) {
+	t.Run("should return requested items", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		p1 := fx.createProduct()
+		fx.createProduct()
+		p3 := fx.createProduct()
+
+		items, err := fx.GetProductsByID(fx.ctx, []int32{p3.ID, p1.ID, testhelpers.RandomInt32()})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Product{p1, p3}, items)
+	})
+}
+
 func TestImpl_GetProduct(t *testing.T) {
 	t.Run("should return ErrNotFound", func(t *testing.T) {
 		fx := newFixture(t)
@@ -65,6 +81,82 @@ func TestImpl_GetProduct(t *testing.T) {
 	})
 }
 
+func TestImpl_TakeAssembled(t *testing.T) {
+	t.Run("should take up to assembled stock", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		product := fx.createProduct()
+		err := fx.AddAssembled(fx.ctx, product.ID, 5)
+		require.NoError(t, err)
+
+		taken, err := fx.TakeAssembled(fx.ctx, product.ID, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), taken)
+
+		taken, err = fx.TakeAssembled(fx.ctx, product.ID, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), taken)
+
+		item, err := fx.GetProduct(fx.ctx, product.ID)
+		require.NoError(t, err)
+		assert.Zero(t, item.Assembled)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.TakeAssembled(fx.ctx, testhelpers.RandomInt32(), 1)
+		require.Equal(t, ErrNotFound, err)
+
+		err = fx.AddAssembled(fx.ctx, testhelpers.RandomInt32(), 1)
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestImpl_UpdateProduct(t *testing.T) {
+	t.Run("should update product once per version", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		product := fx.createProduct()
+		product.Name = testhelpers.RandomString()
+		product.Articles = []models.ProductArticle{{ID: 1, Quantity: 2, Unit: "m"}}
+
+		item, err := fx.UpdateProduct(fx.ctx, product)
+		require.NoError(t, err)
+		product.Version++
+		assert.Equal(t, product, item)
+
+		product.Version--
+		_, err = fx.UpdateProduct(fx.ctx, product)
+		require.Equal(t, ErrVersionConflict, err)
+	})
+
+	t.Run("should keep version on assembled stock change", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		product := fx.createProduct()
+		err := fx.AddAssembled(fx.ctx, product.ID, 1)
+		require.NoError(t, err)
+
+		updated, err := fx.UpdateProduct(fx.ctx, product)
+		require.NoError(t, err)
+		assert.Equal(t, product.Version+1, updated.Version)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.UpdateProduct(fx.ctx, models.Product{ID: testhelpers.RandomInt32(), Version: 1})
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
 type fixture struct {
 	Repository
 
@@ -94,8 +186,11 @@ func (fx *fixture) Finish() {
 
 func (fx *fixture) createProduct() models.Product {
 	item := models.Product{
-		Name:  testhelpers.RandomString(),
-		Price: float32(testhelpers.RandomInt()),
+		Name: testhelpers.RandomString(),
+		Price: models.Money{
+			Amount:   int64(testhelpers.RandomInt()),
+			Currency: "EUR",
+		},
 		Articles: []models.ProductArticle{
 			{
 				ID:       testhelpers.RandomInt32(),
@@ -104,8 +199,9 @@ func (fx *fixture) createProduct() models.Product {
 		},
 	}
 
-	const query = `INSERT INTO products (name, price, articles) VALUES ($1, $2, $3) RETURNING id`
-	err := fx.db.QueryRow(fx.ctx, query, item.Name, item.Price, item.Articles).Scan(&item.ID)
+	const query = `INSERT INTO products (name, price_minor, currency, articles) VALUES ($1, $2, $3, $4) RETURNING id, version`
+	err := fx.db.QueryRow(fx.ctx, query, item.Name, item.Price.Amount, item.Price.Currency, item.Articles).
+		Scan(&item.ID, &item.Version)
 	require.NoError(fx.t, err)
 	return item
 }
@@ -0,0 +1,277 @@
+package purchaseorders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+const checkViolation = "23514"
+
+var (
+	ErrNotFound     = errors.New("purchase order not found")
+	ErrLineNotFound = errors.New("purchase order line not found")
+	ErrOverReceipt  = errors.New("received quantity exceeds the ordered quantity")
+)
+
+type Repository interface {
+	CreatePurchaseOrder(ctx context.Context, item models.PurchaseOrder) (models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error)
+	GetPurchaseOrderForUpdate(ctx context.Context, id int32) (models.PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus) ([]models.PurchaseOrder, error)
+	UpdateStatus(ctx context.Context, id int32, status models.PurchaseOrderStatus) error
+	ReceiveLine(ctx context.Context, orderID, lineID, quantity int32) error
+	GetOnOrder(ctx context.Context) ([]models.ProductArticle, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+// CreatePurchaseOrder inserts the order with its lines, it must be called within a transaction
+func (repo *impl) CreatePurchaseOrder(ctx context.Context, item models.PurchaseOrder) (models.PurchaseOrder, error) {
+	const orderQuery = `
+		INSERT INTO purchase_orders (supplier_id, status)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	const lineQuery = `
+		INSERT INTO purchase_order_lines (purchase_order_id, article_id, quantity, price_minor, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	conn := repo.conn(ctx)
+	err := conn.QueryRow(ctx, orderQuery, item.SupplierID, item.Status).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	lines := make([]models.PurchaseOrderLine, 0, len(item.Lines))
+	for _, line := range item.Lines {
+		err := conn.QueryRow(
+			ctx, lineQuery,
+			item.ID, line.ArticleID, line.Quantity, line.Price.Amount, line.Price.Currency,
+		).Scan(&line.ID)
+		if err != nil {
+			return models.PurchaseOrder{}, err
+		}
+		lines = append(lines, line)
+	}
+	item.Lines = lines
+	return item, nil
+}
+
+func (repo *impl) GetPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error) {
+	const query = `
+		SELECT id, supplier_id, status, created_at, updated_at
+		FROM purchase_orders
+		WHERE id = $1
+	`
+	return repo.getPurchaseOrder(ctx, query, id)
+}
+
+// GetPurchaseOrderForUpdate locks the order until the end of the transaction
+func (repo *impl) GetPurchaseOrderForUpdate(ctx context.Context, id int32) (models.PurchaseOrder, error) {
+	const query = `
+		SELECT id, supplier_id, status, created_at, updated_at
+		FROM purchase_orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return repo.getPurchaseOrder(ctx, query, id)
+}
+
+func (repo *impl) getPurchaseOrder(ctx context.Context, query string, id int32) (models.PurchaseOrder, error) {
+	var item models.PurchaseOrder
+	err := scanPurchaseOrder(repo.conn(ctx).QueryRow(ctx, query, id), &item)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PurchaseOrder{}, ErrNotFound
+		}
+		return models.PurchaseOrder{}, err
+	}
+
+	lines, err := repo.getLines(ctx, []int32{id})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	item.Lines = lines[id]
+	return item, nil
+}
+
+// GetPurchaseOrders lists orders with their lines, an empty status lists all orders
+func (repo *impl) GetPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus) ([]models.PurchaseOrder, error) {
+	const query = `
+		SELECT id, supplier_id, status, created_at, updated_at
+		FROM purchase_orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY id
+	`
+
+	var items []models.PurchaseOrder
+	rows, err := repo.conn(ctx).Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int32
+	for rows.Next() {
+		var item models.PurchaseOrder
+		err := scanPurchaseOrder(rows, &item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+		ids = append(ids, item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	lines, err := repo.getLines(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Lines = lines[items[i].ID]
+	}
+	return items, nil
+}
+
+func (repo *impl) getLines(ctx context.Context, orderIDs []int32) (map[int32][]models.PurchaseOrderLine, error) {
+	const query = `
+		SELECT purchase_order_id, id, article_id, quantity, received_quantity, price_minor, currency
+		FROM purchase_order_lines
+		WHERE purchase_order_id = ANY ($1)
+		ORDER BY id
+	`
+
+	items := make(map[int32][]models.PurchaseOrderLine, len(orderIDs))
+	rows, err := repo.conn(ctx).Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int32
+		var item models.PurchaseOrderLine
+		err := rows.Scan(
+			&orderID,
+			&item.ID,
+			&item.ArticleID,
+			&item.Quantity,
+			&item.Received,
+			&item.Price.Amount,
+			&item.Price.Currency,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items[orderID] = append(items[orderID], item)
+	}
+	return items, rows.Err()
+}
+
+func (repo *impl) UpdateStatus(ctx context.Context, id int32, status models.PurchaseOrderStatus) error {
+	const query = `
+		UPDATE purchase_orders
+		SET status     = $2,
+			updated_at = now()
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReceiveLine increments the received quantity of the order line, it never exceeds the ordered quantity
+func (repo *impl) ReceiveLine(ctx context.Context, orderID, lineID, quantity int32) error {
+	const query = `
+		UPDATE purchase_order_lines
+		SET received_quantity = received_quantity + $3
+		WHERE purchase_order_id = $1
+		  AND id = $2
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, orderID, lineID, quantity)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == checkViolation {
+			return ErrOverReceipt
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLineNotFound
+	}
+	return nil
+}
+
+// GetOnOrder sums the outstanding quantity per article of the orders sent to suppliers
+func (repo *impl) GetOnOrder(ctx context.Context) ([]models.ProductArticle, error) {
+	const query = `
+		SELECT l.article_id, sum(l.quantity - l.received_quantity)::int
+		FROM purchase_order_lines l
+			JOIN purchase_orders o ON o.id = l.purchase_order_id
+		WHERE o.status IN ('sent', 'partially_received')
+		GROUP BY l.article_id
+		HAVING sum(l.quantity - l.received_quantity) > 0
+		ORDER BY l.article_id
+	`
+
+	var items []models.ProductArticle
+	rows, err := repo.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ProductArticle
+		err := rows.Scan(&item.ID, &item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanPurchaseOrder(row pgx.Row, item *models.PurchaseOrder) error {
+	return row.Scan(
+		&item.ID,
+		&item.SupplierID,
+		&item.Status,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+}
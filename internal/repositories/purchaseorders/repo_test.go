@@ -0,0 +1,164 @@
+package purchaseorders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreatePurchaseOrder(t *testing.T) {
+	t.Run("should create order with lines", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+
+		item, err := fx.GetPurchaseOrder(fx.ctx, order.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PurchaseOrderDraft, item.Status)
+		assert.Equal(t, order.Lines, item.Lines)
+	})
+}
+
+func TestImpl_GetPurchaseOrders(t *testing.T) {
+	t.Run("should filter by status", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		draft := fx.createOrder(t)
+		sent := fx.createOrder(t)
+		err := fx.UpdateStatus(fx.ctx, sent.ID, models.PurchaseOrderSent)
+		require.NoError(t, err)
+
+		items, err := fx.GetPurchaseOrders(fx.ctx, models.PurchaseOrderSent)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, sent.ID, items[0].ID)
+		assert.Equal(t, sent.Lines, items[0].Lines)
+
+		items, err = fx.GetPurchaseOrders(fx.ctx, "")
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, draft.ID, items[0].ID)
+	})
+}
+
+func TestImpl_ReceiveLine(t *testing.T) {
+	t.Run("should increment received quantity", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+		line := order.Lines[0]
+
+		err := fx.ReceiveLine(fx.ctx, order.ID, line.ID, 3)
+		require.NoError(t, err)
+		err = fx.ReceiveLine(fx.ctx, order.ID, line.ID, 2)
+		require.NoError(t, err)
+
+		item, err := fx.GetPurchaseOrder(fx.ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), item.Lines[0].Received)
+	})
+
+	t.Run("should reject over receipt", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+		line := order.Lines[0]
+
+		err := fx.ReceiveLine(fx.ctx, order.ID, line.ID, line.Quantity+1)
+
+		require.Equal(t, ErrOverReceipt, err)
+	})
+
+	t.Run("should return ErrLineNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		order := fx.createOrder(t)
+
+		err := fx.ReceiveLine(fx.ctx, order.ID, testhelpers.RandomInt32(), 1)
+
+		require.Equal(t, ErrLineNotFound, err)
+	})
+}
+
+func TestImpl_GetOnOrder(t *testing.T) {
+	t.Run("should sum outstanding quantities of sent orders", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		fx.createOrder(t)
+		sent := fx.createOrder(t)
+		err := fx.UpdateStatus(fx.ctx, sent.ID, models.PurchaseOrderSent)
+		require.NoError(t, err)
+		err = fx.ReceiveLine(fx.ctx, sent.ID, sent.Lines[0].ID, 4)
+		require.NoError(t, err)
+		err = fx.ReceiveLine(fx.ctx, sent.ID, sent.Lines[1].ID, sent.Lines[1].Quantity)
+		require.NoError(t, err)
+
+		items, err := fx.GetOnOrder(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductArticle{
+			{ID: sent.Lines[0].ArticleID, Quantity: sent.Lines[0].Quantity - 4},
+		}, items)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE purchase_orders, purchase_order_lines")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
+
+func (fx *fixture) createOrder(t *testing.T) models.PurchaseOrder {
+	order, err := fx.CreatePurchaseOrder(fx.ctx, models.PurchaseOrder{
+		SupplierID: testhelpers.RandomInt32(),
+		Status:     models.PurchaseOrderDraft,
+		Lines: []models.PurchaseOrderLine{
+			{
+				ArticleID: testhelpers.RandomInt32(),
+				Quantity:  10,
+				Price:     models.Money{Amount: 150, Currency: "EUR"},
+			},
+			{
+				ArticleID: testhelpers.RandomInt32(),
+				Quantity:  4,
+				Price:     models.Money{Amount: 999, Currency: "EUR"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return order
+}
@@ -0,0 +1,98 @@
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("sale not found")
+)
+
+type Repository interface {
+	CreateSale(ctx context.Context, item models.Sale) (models.Sale, error)
+	GetSale(ctx context.Context, id int32) (models.Sale, error)
+	GetProductSales(ctx context.Context, since time.Time) ([]models.ProductSale, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) CreateSale(ctx context.Context, item models.Sale) (models.Sale, error) {
+	const query = `
+		INSERT INTO sales (product_id, quantity)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err := repo.conn(ctx).QueryRow(ctx, query, item.ProductID, item.Quantity).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return models.Sale{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSale(ctx context.Context, id int32) (models.Sale, error) {
+	const query = `
+		SELECT s.id, s.product_id, s.quantity, coalesce(b.quantity, 0), s.created_at
+		FROM sales s
+			LEFT JOIN backorders b ON b.sale_id = s.id
+		WHERE s.id = $1
+	`
+	var item models.Sale
+	err := repo.conn(ctx).QueryRow(ctx, query, id).Scan(&item.ID, &item.ProductID, &item.Quantity, &item.Backordered, &item.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Sale{}, ErrNotFound
+		}
+		return models.Sale{}, err
+	}
+	return item, nil
+}
+
+// GetProductSales sums the quantity sold per product since the given time
+func (repo *impl) GetProductSales(ctx context.Context, since time.Time) ([]models.ProductSale, error) {
+	const query = `
+		SELECT product_id, sum(quantity)::int
+		FROM sales
+		WHERE created_at >= $1
+		GROUP BY product_id
+		ORDER BY product_id
+	`
+
+	var items []models.ProductSale
+	rows, err := repo.conn(ctx).Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ProductSale
+		err := rows.Scan(&item.ProductID, &item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
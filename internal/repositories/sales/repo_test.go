@@ -0,0 +1,117 @@
+package sales
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateSale(t *testing.T) {
+	t.Run("should create sale", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		sale := models.Sale{
+			ProductID: testhelpers.RandomInt32(),
+			Quantity:  int32(testhelpers.RandomIntRange(1, 10)),
+		}
+		created, err := fx.CreateSale(fx.ctx, sale)
+
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+		assert.NotZero(t, created.CreatedAt)
+		assert.Equal(t, sale.ProductID, created.ProductID)
+		assert.Equal(t, sale.Quantity, created.Quantity)
+	})
+}
+
+func TestImpl_GetSale(t *testing.T) {
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item, err := fx.GetSale(fx.ctx, testhelpers.RandomInt32())
+
+		require.Equal(t, ErrNotFound, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("should get existing sale", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		sale, err := fx.CreateSale(fx.ctx, models.Sale{
+			ProductID: testhelpers.RandomInt32(),
+			Quantity:  1,
+		})
+		require.NoError(t, err)
+
+		item, err := fx.GetSale(fx.ctx, sale.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, sale.ID, item.ID)
+		assert.Equal(t, sale.ProductID, item.ProductID)
+		assert.True(t, sale.CreatedAt.Equal(item.CreatedAt))
+	})
+}
+
+func TestImpl_GetProductSales(t *testing.T) {
+	t.Run("should sum quantities per product", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		since := time.Now().Add(-time.Hour)
+		for _, sale := range []models.Sale{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 1, Quantity: 3},
+			{ProductID: 2, Quantity: 1},
+		} {
+			_, err := fx.CreateSale(fx.ctx, sale)
+			require.NoError(t, err)
+		}
+		_, err := fx.db.Exec(fx.ctx, "INSERT INTO sales (product_id, quantity, created_at) VALUES (2, 10, $1)", since.Add(-time.Hour))
+		require.NoError(t, err)
+
+		items, err := fx.GetProductSales(fx.ctx, since)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductSale{
+			{ProductID: 1, Quantity: 5},
+			{ProductID: 2, Quantity: 1},
+		}, items)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE sales")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
@@ -0,0 +1,137 @@
+package serials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+const uniqueViolation = "23505"
+
+var (
+	ErrNotFound    = errors.New("serial not found")
+	ErrDuplicate   = errors.New("serial already registered")
+	ErrUnavailable = errors.New("serial not available")
+)
+
+type Repository interface {
+	GetSerial(ctx context.Context, serial string) (models.ArticleSerial, error)
+	AddSerials(ctx context.Context, articleID int32, serials []string) error
+	AssignSerials(ctx context.Context, saleID, articleID int32, serials []string) error
+	AssignAvailableSerials(ctx context.Context, saleID, articleID, count int32) ([]string, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) GetSerial(ctx context.Context, serial string) (models.ArticleSerial, error) {
+	const query = `
+		SELECT serial, article_id, sale_id, received_at
+		FROM article_serials
+		WHERE serial = $1
+	`
+	var item models.ArticleSerial
+	err := repo.conn(ctx).QueryRow(ctx, query, serial).Scan(&item.Serial, &item.ArticleID, &item.SaleID, &item.ReceivedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ArticleSerial{}, ErrNotFound
+		}
+		return models.ArticleSerial{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) AddSerials(ctx context.Context, articleID int32, serials []string) error {
+	const query = `
+		INSERT INTO article_serials (serial, article_id)
+		SELECT unnest($2::text[]), $1
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, articleID, serials)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrDuplicate
+		}
+		return err
+	}
+	return nil
+}
+
+// AssignSerials marks the given serials of the article as consumed by the sale
+func (repo *impl) AssignSerials(ctx context.Context, saleID, articleID int32, serials []string) error {
+	const query = `
+		UPDATE article_serials
+		SET sale_id = $1
+		WHERE article_id = $2
+		  AND serial = ANY ($3::text[])
+		  AND sale_id IS NULL
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, saleID, articleID, serials)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != int64(len(serials)) {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// AssignAvailableSerials picks the oldest available serials of the article and marks them as consumed by the sale
+func (repo *impl) AssignAvailableSerials(ctx context.Context, saleID, articleID, count int32) ([]string, error) {
+	const query = `
+		UPDATE article_serials
+		SET sale_id = $1
+		WHERE serial IN (
+			SELECT serial
+			FROM article_serials
+			WHERE article_id = $2
+			  AND sale_id IS NULL
+			ORDER BY received_at, serial
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING serial
+	`
+
+	var items []string
+	rows, err := repo.conn(ctx).Query(ctx, query, saleID, articleID, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item string
+		err := rows.Scan(&item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) != int(count) {
+		return nil, ErrUnavailable
+	}
+	return items, nil
+}
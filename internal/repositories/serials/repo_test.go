@@ -0,0 +1,161 @@
+package serials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_AddSerials(t *testing.T) {
+	t.Run("should register serials", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := testhelpers.RandomInt32()
+		serial := testhelpers.RandomString()
+
+		err := fx.AddSerials(fx.ctx, articleID, []string{serial})
+		require.NoError(t, err)
+
+		item, err := fx.GetSerial(fx.ctx, serial)
+		require.NoError(t, err)
+		assert.Equal(t, articleID, item.ArticleID)
+		assert.Nil(t, item.SaleID)
+	})
+
+	t.Run("should return ErrDuplicate", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		serial := testhelpers.RandomString()
+		err := fx.AddSerials(fx.ctx, testhelpers.RandomInt32(), []string{serial})
+		require.NoError(t, err)
+
+		err = fx.AddSerials(fx.ctx, testhelpers.RandomInt32(), []string{serial})
+
+		require.Equal(t, ErrDuplicate, err)
+	})
+}
+
+func TestImpl_GetSerial(t *testing.T) {
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item, err := fx.GetSerial(fx.ctx, testhelpers.RandomString())
+
+		require.Equal(t, ErrNotFound, err)
+		assert.Empty(t, item)
+	})
+}
+
+func TestImpl_AssignSerials(t *testing.T) {
+	t.Run("should assign serials", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := testhelpers.RandomInt32()
+		saleID := testhelpers.RandomInt32()
+		serials := []string{testhelpers.RandomString(), testhelpers.RandomString()}
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, serials))
+
+		err := fx.AssignSerials(fx.ctx, saleID, articleID, serials[:1])
+		require.NoError(t, err)
+
+		item, err := fx.GetSerial(fx.ctx, serials[0])
+		require.NoError(t, err)
+		require.NotNil(t, item.SaleID)
+		assert.Equal(t, saleID, *item.SaleID)
+
+		item, err = fx.GetSerial(fx.ctx, serials[1])
+		require.NoError(t, err)
+		assert.Nil(t, item.SaleID)
+	})
+
+	t.Run("should return ErrUnavailable for sold serial", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := testhelpers.RandomInt32()
+		serials := []string{testhelpers.RandomString()}
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, serials))
+		require.NoError(t, fx.AssignSerials(fx.ctx, testhelpers.RandomInt32(), articleID, serials))
+
+		err := fx.AssignSerials(fx.ctx, testhelpers.RandomInt32(), articleID, serials)
+
+		require.Equal(t, ErrUnavailable, err)
+	})
+
+	t.Run("should return ErrUnavailable for another article", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		serials := []string{testhelpers.RandomString()}
+		require.NoError(t, fx.AddSerials(fx.ctx, 1, serials))
+
+		err := fx.AssignSerials(fx.ctx, testhelpers.RandomInt32(), 2, serials)
+
+		require.Equal(t, ErrUnavailable, err)
+	})
+}
+
+func TestImpl_AssignAvailableSerials(t *testing.T) {
+	t.Run("should assign oldest serials", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := testhelpers.RandomInt32()
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, []string{"a"}))
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, []string{"b"}))
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, []string{"c"}))
+
+		items, err := fx.AssignAvailableSerials(fx.ctx, testhelpers.RandomInt32(), articleID, 2)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, items)
+	})
+
+	t.Run("should return ErrUnavailable if not enough serials", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := testhelpers.RandomInt32()
+		require.NoError(t, fx.AddSerials(fx.ctx, articleID, []string{testhelpers.RandomString()}))
+
+		_, err := fx.AssignAvailableSerials(fx.ctx, testhelpers.RandomInt32(), articleID, 2)
+
+		require.Equal(t, ErrUnavailable, err)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE article_serials")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
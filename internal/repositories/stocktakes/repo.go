@@ -0,0 +1,233 @@
+package stocktakes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("stocktake not found")
+)
+
+type Repository interface {
+	CreateStocktake(ctx context.Context, item models.Stocktake) (models.Stocktake, error)
+	GetStocktake(ctx context.Context, id int32) (models.Stocktake, error)
+	GetStocktakeForUpdate(ctx context.Context, id int32) (models.Stocktake, error)
+	SaveCounts(ctx context.Context, id int32, counts []models.StocktakeCount) error
+	SetAdjustment(ctx context.Context, id, articleID, adjustment int32) error
+	UpdateStatus(ctx context.Context, id int32, status models.StocktakeStatus) error
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+// CreateStocktake inserts the stocktake with the expected article quantities,
+// it must be called within a transaction
+func (repo *impl) CreateStocktake(ctx context.Context, item models.Stocktake) (models.Stocktake, error) {
+	const stocktakeQuery = `
+		INSERT INTO stocktakes (status)
+		VALUES ($1)
+		RETURNING id, created_at
+	`
+	const articlesQuery = `
+		INSERT INTO stocktake_articles (stocktake_id, article_id, expected)
+		SELECT $1, *
+		FROM unnest($2::int[], $3::int[])
+	`
+	conn := repo.conn(ctx)
+	err := conn.QueryRow(ctx, stocktakeQuery, item.Status).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+
+	ids := make([]int32, len(item.Articles))
+	expected := make([]int32, len(item.Articles))
+	for i, art := range item.Articles {
+		ids[i] = art.ArticleID
+		expected[i] = art.Expected
+	}
+	_, err = conn.Exec(ctx, articlesQuery, item.ID, ids, expected)
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetStocktake(ctx context.Context, id int32) (models.Stocktake, error) {
+	const query = `
+		SELECT id, status, closed_at, created_at
+		FROM stocktakes
+		WHERE id = $1
+	`
+	return repo.getStocktake(ctx, query, id)
+}
+
+// GetStocktakeForUpdate locks the stocktake until the end of the transaction
+func (repo *impl) GetStocktakeForUpdate(ctx context.Context, id int32) (models.Stocktake, error) {
+	const query = `
+		SELECT id, status, closed_at, created_at
+		FROM stocktakes
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return repo.getStocktake(ctx, query, id)
+}
+
+func (repo *impl) getStocktake(ctx context.Context, query string, id int32) (models.Stocktake, error) {
+	var item models.Stocktake
+	err := repo.conn(ctx).QueryRow(ctx, query, id).Scan(&item.ID, &item.Status, &item.ClosedAt, &item.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Stocktake{}, ErrNotFound
+		}
+		return models.Stocktake{}, err
+	}
+
+	item.Articles, err = repo.getArticles(ctx, id)
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	counts, err := repo.getCounts(ctx, id)
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	for i := range item.Articles {
+		item.Articles[i].Counts = counts[item.Articles[i].ArticleID]
+	}
+	return item, nil
+}
+
+// getArticles lists the stocktake articles with the current stock, zero if the article was deleted
+func (repo *impl) getArticles(ctx context.Context, id int32) ([]models.StocktakeArticle, error) {
+	const query = `
+		SELECT s.article_id, s.expected, coalesce(a.stock, 0), s.adjustment
+		FROM stocktake_articles s
+			LEFT JOIN articles a ON a.id = s.article_id
+		WHERE s.stocktake_id = $1
+		ORDER BY s.article_id
+	`
+
+	var items []models.StocktakeArticle
+	rows, err := repo.conn(ctx).Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.StocktakeArticle
+		err := rows.Scan(&item.ArticleID, &item.Expected, &item.Stock, &item.Adjustment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (repo *impl) getCounts(ctx context.Context, id int32) (map[int32][]models.StocktakeCount, error) {
+	const query = `
+		SELECT article_id, counter, quantity, stock, counted_at
+		FROM stocktake_counts
+		WHERE stocktake_id = $1
+		ORDER BY article_id, counter
+	`
+
+	items := make(map[int32][]models.StocktakeCount)
+	rows, err := repo.conn(ctx).Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.StocktakeCount
+		err := rows.Scan(&item.ArticleID, &item.Counter, &item.Quantity, &item.Stock, &item.CountedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items[item.ArticleID] = append(items[item.ArticleID], item)
+	}
+	return items, rows.Err()
+}
+
+// SaveCounts records the counted quantities with the current article stock, a count replaces the previous count
+// of the same counter. The stock is zero if the article was deleted.
+func (repo *impl) SaveCounts(ctx context.Context, id int32, counts []models.StocktakeCount) error {
+	const query = `
+		INSERT INTO stocktake_counts (stocktake_id, article_id, counter, quantity, stock)
+		SELECT $1, c.article_id, c.counter, c.quantity, coalesce(a.stock, 0)
+		FROM unnest($2::int[], $3::text[], $4::int[]) AS c (article_id, counter, quantity)
+			LEFT JOIN articles a ON a.id = c.article_id
+		ON CONFLICT (stocktake_id, article_id, counter) DO UPDATE
+			SET quantity   = excluded.quantity,
+				stock      = excluded.stock,
+				counted_at = now()
+	`
+
+	ids := make([]int32, len(counts))
+	counters := make([]string, len(counts))
+	quantities := make([]int32, len(counts))
+	for i, c := range counts {
+		ids[i] = c.ArticleID
+		counters[i] = c.Counter
+		quantities[i] = c.Quantity
+	}
+	_, err := repo.conn(ctx).Exec(ctx, query, id, ids, counters, quantities)
+	return err
+}
+
+func (repo *impl) SetAdjustment(ctx context.Context, id, articleID, adjustment int32) error {
+	const query = `
+		UPDATE stocktake_articles
+		SET adjustment = $3
+		WHERE stocktake_id = $1
+		  AND article_id = $2
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id, articleID, adjustment)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateStatus changes the status, the stocktake is closed unless the status is open
+func (repo *impl) UpdateStatus(ctx context.Context, id int32, status models.StocktakeStatus) error {
+	const query = `
+		UPDATE stocktakes
+		SET status    = $2,
+			closed_at = CASE WHEN $2 = 'open' THEN NULL ELSE now() END
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
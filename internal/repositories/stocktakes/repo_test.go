@@ -0,0 +1,154 @@
+package stocktakes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateStocktake(t *testing.T) {
+	t.Run("should create stocktake with current stock", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := fx.createArticle(7)
+		created := fx.createStocktake(t, articleID)
+
+		item, err := fx.GetStocktake(fx.ctx, created.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.StocktakeOpen, item.Status)
+		assert.Nil(t, item.ClosedAt)
+		assert.Equal(t, []models.StocktakeArticle{
+			{ArticleID: articleID, Expected: 5, Stock: 7},
+		}, item.Articles)
+	})
+
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.GetStocktake(fx.ctx, testhelpers.RandomInt32())
+
+		require.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestImpl_SaveCounts(t *testing.T) {
+	t.Run("should replace counts of the same counter", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := fx.createArticle(5)
+		created := fx.createStocktake(t, articleID)
+
+		err := fx.SaveCounts(fx.ctx, created.ID, []models.StocktakeCount{
+			{ArticleID: articleID, Counter: "alice", Quantity: 2},
+			{ArticleID: articleID, Counter: "bob", Quantity: 3},
+		})
+		require.NoError(t, err)
+		err = fx.SaveCounts(fx.ctx, created.ID, []models.StocktakeCount{
+			{ArticleID: articleID, Counter: "alice", Quantity: 1},
+		})
+		require.NoError(t, err)
+
+		item, err := fx.GetStocktake(fx.ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, item.Articles[0].Counts, 2)
+		counted, ok := item.Articles[0].Counted()
+		assert.True(t, ok)
+		assert.EqualValues(t, 4, counted)
+	})
+
+	t.Run("should record current stock with the count", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := fx.createArticle(5)
+		created := fx.createStocktake(t, articleID)
+		_, err := fx.db.Exec(fx.ctx, "UPDATE articles SET stock = 3 WHERE id = $1", articleID)
+		require.NoError(t, err)
+
+		err = fx.SaveCounts(fx.ctx, created.ID, []models.StocktakeCount{
+			{ArticleID: articleID, Counter: "alice", Quantity: 4},
+		})
+		require.NoError(t, err)
+
+		item, err := fx.GetStocktake(fx.ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, item.Articles[0].Counts, 1)
+		assert.EqualValues(t, 3, item.Articles[0].Counts[0].Stock)
+		assert.EqualValues(t, 1, item.Articles[0].Variance())
+	})
+}
+
+func TestImpl_UpdateStatus(t *testing.T) {
+	t.Run("should close stocktake", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		articleID := fx.createArticle(5)
+		created := fx.createStocktake(t, articleID)
+
+		err := fx.SetAdjustment(fx.ctx, created.ID, articleID, -2)
+		require.NoError(t, err)
+		err = fx.UpdateStatus(fx.ctx, created.ID, models.StocktakeApproved)
+		require.NoError(t, err)
+
+		item, err := fx.GetStocktake(fx.ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.StocktakeApproved, item.Status)
+		assert.NotNil(t, item.ClosedAt)
+		assert.EqualValues(t, -2, item.Articles[0].Adjustment)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE stocktakes, stocktake_articles, stocktake_counts, articles")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
+
+func (fx *fixture) createArticle(stock int32) int32 {
+	var id int32
+	err := fx.db.QueryRow(fx.ctx, "INSERT INTO articles (name, stock) VALUES ($1, $2) RETURNING id", testhelpers.RandomString(), stock).Scan(&id)
+	require.NoError(fx.t, err)
+	return id
+}
+
+// createStocktake opens a stocktake expecting 5 units of the article
+func (fx *fixture) createStocktake(t *testing.T, articleID int32) models.Stocktake {
+	item, err := fx.CreateStocktake(fx.ctx, models.Stocktake{
+		Status:   models.StocktakeOpen,
+		Articles: []models.StocktakeArticle{{ArticleID: articleID, Expected: 5}},
+	})
+	require.NoError(t, err)
+	return item
+}
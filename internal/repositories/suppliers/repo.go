@@ -0,0 +1,186 @@
+package suppliers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound        = errors.New("supplier not found")
+	ErrArticleNotFound = errors.New("article not supplied by the supplier")
+)
+
+type Repository interface {
+	CreateSupplier(ctx context.Context, item models.Supplier) (models.Supplier, error)
+	GetSupplier(ctx context.Context, id int32) (models.Supplier, error)
+	GetSuppliers(ctx context.Context) ([]models.Supplier, error)
+	SetSupplierArticle(ctx context.Context, item models.SupplierArticle) error
+	GetSupplierArticle(ctx context.Context, supplierID, articleID int32) (models.SupplierArticle, error)
+	GetSupplierArticles(ctx context.Context, supplierID int32) ([]models.SupplierArticle, error)
+	GetArticleSources(ctx context.Context) ([]models.SupplierArticle, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) CreateSupplier(ctx context.Context, item models.Supplier) (models.Supplier, error) {
+	const query = `
+		INSERT INTO suppliers (name)
+		VALUES ($1)
+		RETURNING id
+	`
+	err := repo.conn(ctx).QueryRow(ctx, query, item.Name).Scan(&item.ID)
+	if err != nil {
+		return models.Supplier{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSupplier(ctx context.Context, id int32) (models.Supplier, error) {
+	const query = `
+		SELECT id, name
+		FROM suppliers
+		WHERE id = $1
+	`
+	var item models.Supplier
+	err := repo.conn(ctx).QueryRow(ctx, query, id).Scan(&item.ID, &item.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Supplier{}, ErrNotFound
+		}
+		return models.Supplier{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	const query = `
+		SELECT id, name
+		FROM suppliers
+		ORDER BY id
+	`
+
+	var items []models.Supplier
+	rows, err := repo.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.Supplier
+		err := rows.Scan(&item.ID, &item.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SetSupplierArticle adds the article to the supplier or updates its terms
+func (repo *impl) SetSupplierArticle(ctx context.Context, item models.SupplierArticle) error {
+	const query = `
+		INSERT INTO supplier_articles (supplier_id, article_id, sku, price_minor, currency, lead_time_days)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (supplier_id, article_id) DO UPDATE
+			SET sku            = excluded.sku,
+				price_minor    = excluded.price_minor,
+				currency       = excluded.currency,
+				lead_time_days = excluded.lead_time_days
+	`
+	_, err := repo.conn(ctx).Exec(
+		ctx, query,
+		item.SupplierID, item.ArticleID, item.SKU, item.Price.Amount, item.Price.Currency, item.LeadTimeDays,
+	)
+	return err
+}
+
+func (repo *impl) GetSupplierArticle(ctx context.Context, supplierID, articleID int32) (models.SupplierArticle, error) {
+	const query = `
+		SELECT supplier_id, article_id, sku, price_minor, currency, lead_time_days
+		FROM supplier_articles
+		WHERE supplier_id = $1
+		  AND article_id = $2
+	`
+	var item models.SupplierArticle
+	err := scanSupplierArticle(repo.conn(ctx).QueryRow(ctx, query, supplierID, articleID), &item)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SupplierArticle{}, ErrArticleNotFound
+		}
+		return models.SupplierArticle{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSupplierArticles(ctx context.Context, supplierID int32) ([]models.SupplierArticle, error) {
+	const query = `
+		SELECT supplier_id, article_id, sku, price_minor, currency, lead_time_days
+		FROM supplier_articles
+		WHERE supplier_id = $1
+		ORDER BY article_id
+	`
+	return repo.querySupplierArticles(ctx, query, supplierID)
+}
+
+// GetArticleSources lists the supplier articles of all suppliers,
+// ordered by article with the shortest lead time and then the lowest price first
+func (repo *impl) GetArticleSources(ctx context.Context) ([]models.SupplierArticle, error) {
+	const query = `
+		SELECT supplier_id, article_id, sku, price_minor, currency, lead_time_days
+		FROM supplier_articles
+		ORDER BY article_id, lead_time_days, price_minor, supplier_id
+	`
+	return repo.querySupplierArticles(ctx, query)
+}
+
+func (repo *impl) querySupplierArticles(ctx context.Context, query string, args ...any) ([]models.SupplierArticle, error) {
+	var items []models.SupplierArticle
+	rows, err := repo.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.SupplierArticle
+		err := scanSupplierArticle(rows, &item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanSupplierArticle(row pgx.Row, item *models.SupplierArticle) error {
+	return row.Scan(
+		&item.SupplierID,
+		&item.ArticleID,
+		&item.SKU,
+		&item.Price.Amount,
+		&item.Price.Currency,
+		&item.LeadTimeDays,
+	)
+}
@@ -0,0 +1,126 @@
+package suppliers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_GetSupplier(t *testing.T) {
+	t.Run("should return ErrNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item, err := fx.GetSupplier(fx.ctx, testhelpers.RandomInt32())
+
+		require.Equal(t, ErrNotFound, err)
+		assert.Empty(t, item)
+	})
+
+	t.Run("should get created supplier", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		supplier, err := fx.CreateSupplier(fx.ctx, models.Supplier{Name: testhelpers.RandomString()})
+		require.NoError(t, err)
+
+		item, err := fx.GetSupplier(fx.ctx, supplier.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, supplier, item)
+	})
+}
+
+func TestImpl_SetSupplierArticle(t *testing.T) {
+	t.Run("should add and update article", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item := models.SupplierArticle{
+			SupplierID:   testhelpers.RandomInt32(),
+			ArticleID:    testhelpers.RandomInt32(),
+			SKU:          testhelpers.RandomString(),
+			Price:        models.Money{Amount: 250, Currency: "EUR"},
+			LeadTimeDays: 7,
+		}
+		err := fx.SetSupplierArticle(fx.ctx, item)
+		require.NoError(t, err)
+
+		item.Price.Amount = 300
+		item.LeadTimeDays = 10
+		err = fx.SetSupplierArticle(fx.ctx, item)
+		require.NoError(t, err)
+
+		got, err := fx.GetSupplierArticle(fx.ctx, item.SupplierID, item.ArticleID)
+		require.NoError(t, err)
+		assert.Equal(t, item, got)
+
+		items, err := fx.GetSupplierArticles(fx.ctx, item.SupplierID)
+		require.NoError(t, err)
+		assert.Equal(t, []models.SupplierArticle{item}, items)
+	})
+
+	t.Run("should return ErrArticleNotFound", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		_, err := fx.GetSupplierArticle(fx.ctx, testhelpers.RandomInt32(), testhelpers.RandomInt32())
+
+		require.Equal(t, ErrArticleNotFound, err)
+	})
+}
+
+func TestImpl_GetArticleSources(t *testing.T) {
+	t.Run("should order sources by lead time and price", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		sources := []models.SupplierArticle{
+			{SupplierID: 1, ArticleID: 1, SKU: "A", Price: models.Money{Amount: 100, Currency: "EUR"}, LeadTimeDays: 5},
+			{SupplierID: 2, ArticleID: 1, SKU: "B", Price: models.Money{Amount: 200, Currency: "EUR"}, LeadTimeDays: 3},
+			{SupplierID: 3, ArticleID: 1, SKU: "C", Price: models.Money{Amount: 150, Currency: "EUR"}, LeadTimeDays: 3},
+		}
+		for _, item := range sources {
+			err := fx.SetSupplierArticle(fx.ctx, item)
+			require.NoError(t, err)
+		}
+
+		items, err := fx.GetArticleSources(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.SupplierArticle{sources[2], sources[1], sources[0]}, items)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE suppliers, supplier_articles")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
@@ -0,0 +1,85 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+// ChangesChannel is notified with an empty payload whenever units are created, changed or deleted
+const ChangesChannel = "unit_changes"
+
+type Repository interface {
+	GetUnits(ctx context.Context) ([]models.Unit, error)
+	GetArticleUnits(ctx context.Context) ([]models.ArticleUnit, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) GetUnits(ctx context.Context) ([]models.Unit, error) {
+	const query = `
+		SELECT code, dimension, factor
+		FROM units
+		ORDER BY code
+	`
+
+	var items []models.Unit
+	rows, err := repo.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.Unit
+		err := rows.Scan(&item.Code, &item.Dimension, &item.Factor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (repo *impl) GetArticleUnits(ctx context.Context) ([]models.ArticleUnit, error) {
+	const query = `
+		SELECT article_id, code, factor
+		FROM article_units
+		ORDER BY article_id, code
+	`
+
+	var items []models.ArticleUnit
+	rows, err := repo.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ArticleUnit
+		err := rows.Scan(&item.ArticleID, &item.Code, &item.Factor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
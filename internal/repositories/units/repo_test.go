@@ -0,0 +1,86 @@
+package units
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_GetUnits(t *testing.T) {
+	t.Run("should return predefined units", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		items, err := fx.GetUnits(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Contains(t, items, models.Unit{Code: "pcs", Dimension: "count", Factor: 1})
+		assert.Contains(t, items, models.Unit{Code: "m", Dimension: "length", Factor: 1000})
+	})
+}
+
+func TestImpl_GetArticleUnits(t *testing.T) {
+	t.Run("should return empty list", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		items, err := fx.GetArticleUnits(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("should return items", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		u1 := fx.createArticleUnit(models.ArticleUnit{ArticleID: 1, Code: "box", Factor: 100})
+		u2 := fx.createArticleUnit(models.ArticleUnit{ArticleID: 2, Code: "roll", Factor: 5000})
+
+		items, err := fx.GetArticleUnits(fx.ctx)
+
+		require.NoError(t, err)
+		expected := []models.ArticleUnit{u1, u2}
+		assert.Equal(t, expected, items)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE article_units")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
+
+func (fx *fixture) createArticleUnit(item models.ArticleUnit) models.ArticleUnit {
+	const query = `INSERT INTO article_units (article_id, code, factor) VALUES ($1, $2, $3)`
+	_, err := fx.db.Exec(fx.ctx, query, item.ArticleID, item.Code, item.Factor)
+	require.NoError(fx.t, err)
+	return item
+}
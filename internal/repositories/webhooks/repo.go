@@ -0,0 +1,347 @@
+package webhooks
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("webhook subscription not found")
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, item models.WebhookSubscription) (models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int32) (models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int32) error
+	LockStockStates(ctx context.Context) error
+	SyncStockStates(ctx context.Context, inStock map[int32]bool) ([]int32, error)
+	AddDeliveries(ctx context.Context, items []models.WebhookDelivery) error
+	ClaimDeliveries(ctx context.Context, limit int, until time.Time) ([]models.WebhookDelivery, error)
+	GetDeadDeliveries(ctx context.Context, subscriptionID int32) ([]models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, id int64, reason string) error
+	ReplayDeliveries(ctx context.Context, subscriptionID int32, ids []int64) (int, error)
+}
+
+type impl struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &impl{
+		db: db,
+	}
+}
+
+func (repo *impl) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, repo.db)
+}
+
+func (repo *impl) CreateSubscription(ctx context.Context, item models.WebhookSubscription) (models.WebhookSubscription, error) {
+	const query = `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, product_ids)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if item.EventTypes == nil {
+		item.EventTypes = []string{}
+	}
+	if item.ProductIDs == nil {
+		item.ProductIDs = []int32{}
+	}
+	err := repo.conn(ctx).QueryRow(ctx, query, item.URL, item.Secret, item.EventTypes, item.ProductIDs).
+		Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSubscription(ctx context.Context, id int32) (models.WebhookSubscription, error) {
+	const query = `
+		SELECT id, url, secret, event_types, product_ids, created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	var item models.WebhookSubscription
+	err := repo.conn(ctx).QueryRow(ctx, query, id).
+		Scan(&item.ID, &item.URL, &item.Secret, &item.EventTypes, &item.ProductIDs, &item.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.WebhookSubscription{}, ErrNotFound
+		}
+		return models.WebhookSubscription{}, err
+	}
+	return item, nil
+}
+
+func (repo *impl) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	const query = `
+		SELECT id, url, secret, event_types, product_ids, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	var items []models.WebhookSubscription
+	rows, err := repo.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.WebhookSubscription
+		err := rows.Scan(&item.ID, &item.URL, &item.Secret, &item.EventTypes, &item.ProductIDs, &item.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// DeleteSubscription deletes the subscription with all its deliveries
+func (repo *impl) DeleteSubscription(ctx context.Context, id int32) error {
+	const query = `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	tag, err := repo.conn(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LockStockStates locks the stored stock states until the transaction ends, so that evaluations
+// comparing them with the current stock run one after the other. It must be called within a transaction.
+func (repo *impl) LockStockStates(ctx context.Context) error {
+	const query = `
+		SELECT product_id
+		FROM webhook_product_states
+		ORDER BY product_id
+		FOR UPDATE
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query)
+	return err
+}
+
+// SyncStockStates stores whether the products are in stock and returns the IDs of the products whose state changed.
+// Products seen for the first time are stored without being reported.
+func (repo *impl) SyncStockStates(ctx context.Context, inStock map[int32]bool) ([]int32, error) {
+	const query = `
+		WITH input AS (
+			SELECT *
+			FROM unnest($1::int[], $2::bool[]) AS t (product_id, in_stock)
+		),
+		changed AS (
+			UPDATE webhook_product_states s
+			SET in_stock = i.in_stock
+			FROM input i
+			WHERE s.product_id = i.product_id
+			  AND s.in_stock <> i.in_stock
+			RETURNING s.product_id
+		),
+		added AS (
+			INSERT INTO webhook_product_states (product_id, in_stock)
+			SELECT i.product_id, i.in_stock
+			FROM input i
+			WHERE NOT EXISTS (SELECT 1 FROM webhook_product_states s WHERE s.product_id = i.product_id)
+			ON CONFLICT (product_id) DO NOTHING
+		)
+		SELECT product_id
+		FROM changed
+		ORDER BY product_id
+	`
+	ids := make([]int32, 0, len(inStock))
+	states := make([]bool, 0, len(inStock))
+	for id, state := range inStock {
+		ids = append(ids, id)
+		states = append(states, state)
+	}
+
+	var changed []int32
+	rows, err := repo.conn(ctx).Query(ctx, query, ids, states)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int32
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		changed = append(changed, id)
+	}
+	return changed, rows.Err()
+}
+
+func (repo *impl) AddDeliveries(ctx context.Context, items []models.WebhookDelivery) error {
+	const query = `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+		SELECT *
+		FROM unnest($1::int[], $2::text[], $3::jsonb[])
+	`
+	subscriptionIDs := make([]int32, len(items))
+	eventTypes := make([]string, len(items))
+	payloads := make([]string, len(items))
+	for i, item := range items {
+		subscriptionIDs[i] = item.SubscriptionID
+		eventTypes[i] = item.EventType
+		payloads[i] = string(item.Payload)
+	}
+	_, err := repo.conn(ctx).Exec(ctx, query, subscriptionIDs, eventTypes, payloads)
+	return err
+}
+
+// ClaimDeliveries claims deliveries due for an attempt until the given time, oldest first.
+// Claimed deliveries are not due for other dispatchers, they are due again if the claim expires
+// without the delivery being marked.
+func (repo *impl) ClaimDeliveries(ctx context.Context, limit int, until time.Time) ([]models.WebhookDelivery, error) {
+	const query = `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE delivered_at IS NULL
+			  AND dead_at IS NULL
+			  AND next_attempt_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE d.id = due.id
+		RETURNING d.id, d.subscription_id, d.event_type, d.payload, d.created_at, d.attempts, coalesce(d.last_error, ''),
+			d.delivered_at, d.dead_at
+	`
+	items, err := repo.getDeliveries(ctx, query, limit, until)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(items, func(a, b models.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return items, nil
+}
+
+// GetDeadDeliveries lists the dead letters of the subscription, oldest first
+func (repo *impl) GetDeadDeliveries(ctx context.Context, subscriptionID int32) ([]models.WebhookDelivery, error) {
+	const query = `
+		SELECT id, subscription_id, event_type, payload, created_at, attempts, coalesce(last_error, ''), delivered_at, dead_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		  AND dead_at IS NOT NULL
+		ORDER BY id
+	`
+	return repo.getDeliveries(ctx, query, subscriptionID)
+}
+
+func (repo *impl) getDeliveries(ctx context.Context, query string, args ...any) ([]models.WebhookDelivery, error) {
+	var items []models.WebhookDelivery
+	rows, err := repo.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.WebhookDelivery
+		err := rows.Scan(
+			&item.ID,
+			&item.SubscriptionID,
+			&item.EventType,
+			&item.Payload,
+			&item.CreatedAt,
+			&item.Attempts,
+			&item.LastError,
+			&item.DeliveredAt,
+			&item.DeadAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (repo *impl) MarkDelivered(ctx context.Context, ids []int64) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET delivered_at = now(),
+			last_error   = NULL
+		WHERE id = ANY ($1)
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, ids)
+	return err
+}
+
+// MarkFailed records a failed delivery, the delivery is retried at nextAttemptAt
+func (repo *impl) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts        = attempts + 1,
+			last_error      = $2,
+			next_attempt_at = $3
+		WHERE id = $1
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, id, reason, nextAttemptAt)
+	return err
+}
+
+// MarkDead records the last failed delivery and moves the delivery to the dead letters
+func (repo *impl) MarkDead(ctx context.Context, id int64, reason string) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts   = attempts + 1,
+			last_error = $2,
+			dead_at    = now()
+		WHERE id = $1
+	`
+	_, err := repo.conn(ctx).Exec(ctx, query, id, reason)
+	return err
+}
+
+// ReplayDeliveries queues the deliveries of the subscription again with a fresh retry budget,
+// all dead letters of the subscription if no IDs are given. It returns the number of replayed deliveries.
+func (repo *impl) ReplayDeliveries(ctx context.Context, subscriptionID int32, ids []int64) (int, error) {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts        = 0,
+			last_error      = NULL,
+			next_attempt_at = now(),
+			delivered_at    = NULL,
+			dead_at         = NULL
+		WHERE subscription_id = $1
+		  AND (id = ANY ($2) OR (cardinality($2::bigint[]) = 0 AND dead_at IS NOT NULL))
+	`
+	if ids == nil {
+		ids = []int64{}
+	}
+	tag, err := repo.conn(ctx).Exec(ctx, query, subscriptionID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
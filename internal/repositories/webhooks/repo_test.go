@@ -0,0 +1,140 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateSubscription(t *testing.T) {
+	t.Run("should create subscription", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		item, err := fx.CreateSubscription(fx.ctx, models.WebhookSubscription{
+			URL:        "http://example.com/hooks",
+			Secret:     testhelpers.RandomString(),
+			EventTypes: []string{models.WebhookEventProductOutOfStock},
+		})
+		require.NoError(t, err)
+
+		got, err := fx.GetSubscription(fx.ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.URL, got.URL)
+		assert.Equal(t, item.Secret, got.Secret)
+		assert.Equal(t, item.EventTypes, got.EventTypes)
+		assert.Empty(t, got.ProductIDs)
+	})
+
+	t.Run("should fail deleting unknown subscription", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		err := fx.DeleteSubscription(fx.ctx, 1)
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestImpl_SyncStockStates(t *testing.T) {
+	t.Run("should report changed states only", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		changed, err := fx.SyncStockStates(fx.ctx, map[int32]bool{1: true, 2: true})
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+
+		changed, err = fx.SyncStockStates(fx.ctx, map[int32]bool{1: false, 2: true, 3: false})
+		require.NoError(t, err)
+		assert.Equal(t, []int32{1}, changed)
+
+		changed, err = fx.SyncStockStates(fx.ctx, map[int32]bool{1: false, 3: true})
+		require.NoError(t, err)
+		assert.Equal(t, []int32{3}, changed)
+	})
+}
+
+func TestImpl_ReplayDeliveries(t *testing.T) {
+	t.Run("should retry until dead and replay dead letters", func(t *testing.T) {
+		fx := newFixture(t)
+		defer fx.Finish()
+
+		sub, err := fx.CreateSubscription(fx.ctx, models.WebhookSubscription{URL: "http://example.com", Secret: "s"})
+		require.NoError(t, err)
+		err = fx.AddDeliveries(fx.ctx, []models.WebhookDelivery{
+			{SubscriptionID: sub.ID, EventType: models.WebhookEventProductInStock, Payload: []byte(`{"product": {"id": 1}}`)},
+			{SubscriptionID: sub.ID, EventType: models.WebhookEventProductOutOfStock, Payload: []byte(`{"product": {"id": 2}}`)},
+		})
+		require.NoError(t, err)
+
+		pending, err := fx.ClaimDeliveries(fx.ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.JSONEq(t, `{"product": {"id": 1}}`, string(pending[0].Payload))
+		claimed, err := fx.ClaimDeliveries(fx.ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		err = fx.MarkDelivered(fx.ctx, []int64{pending[0].ID})
+		require.NoError(t, err)
+		err = fx.MarkFailed(fx.ctx, pending[1].ID, "timeout", time.Now())
+		require.NoError(t, err)
+		err = fx.MarkDead(fx.ctx, pending[1].ID, "unavailable")
+		require.NoError(t, err)
+
+		pending, err = fx.ClaimDeliveries(fx.ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		dead, err := fx.GetDeadDeliveries(fx.ctx, sub.ID)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.EqualValues(t, 2, dead[0].Attempts)
+		assert.Equal(t, "unavailable", dead[0].LastError)
+		assert.NotNil(t, dead[0].DeadAt)
+
+		replayed, err := fx.ReplayDeliveries(fx.ctx, sub.ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, replayed)
+
+		pending, err = fx.ClaimDeliveries(fx.ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, dead[0].ID, pending[0].ID)
+		assert.Zero(t, pending[0].Attempts)
+	})
+}
+
+type fixture struct {
+	Repository
+
+	t   *testing.T
+	ctx context.Context
+	db  *pgxpool.Pool
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	db := testhelpers.NewDB(t)
+
+	_, err := db.Exec(ctx, "TRUNCATE TABLE webhook_deliveries, webhook_subscriptions, webhook_product_states RESTART IDENTITY")
+	require.NoError(t, err)
+
+	return &fixture{
+		t:          t,
+		ctx:        ctx,
+		db:         db,
+		Repository: NewRepository(db),
+	}
+}
+
+func (fx *fixture) Finish() {
+	fx.db.Close()
+}
@@ -0,0 +1,2 @@
+//go:generate mockgen -source ../service.go -destination mock.gen.go -package mockArticlesSrv
+package mockArticlesSrv
@@ -0,0 +1,165 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/sales"
+	"warehouse/internal/repositories/serials"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	ErrSerialsRequired = errors.New("serialized article requires a serial for every unit")
+	ErrNotSerialized   = errors.New("article is not serialized")
+	ErrInvalidReorder  = errors.New("reorder point and quantity must not be negative")
+	ErrInvalidName     = errors.New("name is required")
+)
+
+type Service interface {
+	AddStock(ctx context.Context, id, quantity int32, serials []string) error
+	TraceSerial(ctx context.Context, serial string) (models.SerialTrace, error)
+	GetArticle(ctx context.Context, id int32) (models.Article, error)
+	UpdateArticle(ctx context.Context, item models.Article) (models.Article, error)
+	UpdateReorderPolicy(ctx context.Context, id, version int32, point *int32, quantity int32) (models.Article, error)
+	ListLowStock(ctx context.Context) ([]models.Article, error)
+}
+
+type impl struct {
+	tx           db.Transactor
+	articlesRepo articles.Repository
+	salesRepo    sales.Repository
+	serialsRepo  serials.Repository
+}
+
+func NewService(
+	tx db.Transactor,
+	aRepo articles.Repository,
+	sRepo sales.Repository,
+	serRepo serials.Repository,
+) Service {
+	return &impl{
+		tx:           tx,
+		articlesRepo: aRepo,
+		salesRepo:    sRepo,
+		serialsRepo:  serRepo,
+	}
+}
+
+// AddStock increments article stock and registers serial numbers of serialized articles.
+// When quantity is zero it is derived from the number of serials.
+func (srv *impl) AddStock(ctx context.Context, id, quantity int32, serials []string) error {
+	if quantity == 0 {
+		quantity = int32(len(serials))
+	}
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	return srv.tx.InTx(ctx, func(ctx context.Context) error {
+		article, err := srv.articlesRepo.GetArticle(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get article: %w", err)
+		}
+		if !article.Serialized && len(serials) > 0 {
+			return ErrNotSerialized
+		}
+		if article.Serialized && int32(len(serials)) != quantity {
+			return ErrSerialsRequired
+		}
+
+		err = srv.articlesRepo.AddArticles(ctx, []models.ProductArticle{
+			{
+				ID:       id,
+				Quantity: quantity,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add articles: %w", err)
+		}
+		if len(serials) == 0 {
+			return nil
+		}
+		err = srv.serialsRepo.AddSerials(ctx, id, serials)
+		if err != nil {
+			return fmt.Errorf("failed to add serials: %w", err)
+		}
+		return nil
+	})
+}
+
+// TraceSerial finds the serial and the sale which consumed it
+func (srv *impl) TraceSerial(ctx context.Context, serial string) (models.SerialTrace, error) {
+	item, err := srv.serialsRepo.GetSerial(ctx, serial)
+	if err != nil {
+		return models.SerialTrace{}, fmt.Errorf("failed to get serial: %w", err)
+	}
+
+	trace := models.SerialTrace{
+		ArticleSerial: item,
+	}
+	if item.SaleID == nil {
+		return trace, nil
+	}
+	sale, err := srv.salesRepo.GetSale(ctx, *item.SaleID)
+	if err != nil {
+		return models.SerialTrace{}, fmt.Errorf("failed to get sale: %w", err)
+	}
+	trace.Sale = &sale
+	return trace, nil
+}
+
+func (srv *impl) GetArticle(ctx context.Context, id int32) (models.Article, error) {
+	item, err := srv.articlesRepo.GetArticle(ctx, id)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("failed to get article: %w", err)
+	}
+	return item, nil
+}
+
+// UpdateArticle changes the name and reorder policy of the article.
+// The version must be the one read last, the update fails if the article has changed since.
+func (srv *impl) UpdateArticle(ctx context.Context, item models.Article) (models.Article, error) {
+	if item.Name == "" {
+		return models.Article{}, ErrInvalidName
+	}
+	if (item.ReorderPoint != nil && *item.ReorderPoint < 0) || item.ReorderQuantity < 0 {
+		return models.Article{}, ErrInvalidReorder
+	}
+	updated, err := srv.articlesRepo.UpdateArticle(ctx, item)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("failed to update article: %w", err)
+	}
+	return updated, nil
+}
+
+// UpdateReorderPolicy sets the stock level at which the article is low on stock and the quantity to reorder.
+// A nil point disables low stock alerts for the article. The update fails if the article version has changed.
+func (srv *impl) UpdateReorderPolicy(
+	ctx context.Context,
+	id, version int32,
+	point *int32,
+	quantity int32,
+) (models.Article, error) {
+	if (point != nil && *point < 0) || quantity < 0 {
+		return models.Article{}, ErrInvalidReorder
+	}
+	item, err := srv.articlesRepo.UpdateReorderPolicy(ctx, id, version, point, quantity)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("failed to update reorder policy: %w", err)
+	}
+	return item, nil
+}
+
+// ListLowStock lists the articles at or below their reorder point
+func (srv *impl) ListLowStock(ctx context.Context) ([]models.Article, error) {
+	items, err := srv.articlesRepo.GetLowStockArticles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock articles: %w", err)
+	}
+	return items, nil
+}
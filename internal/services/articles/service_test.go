@@ -0,0 +1,228 @@
+package articles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/db/mock"
+	"warehouse/internal/models"
+	articlesrepo "warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/sales/mock"
+	"warehouse/internal/repositories/serials/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_AddStock(t *testing.T) {
+	articleID := testhelpers.RandomInt32()
+
+	t.Run("should add stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		quantity := int32(testhelpers.RandomIntRange(1, 100))
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, articleID).Return(models.Article{ID: articleID}, nil)
+		fx.articlesRepo.EXPECT().AddArticles(fx.ctx, []models.ProductArticle{{ID: articleID, Quantity: quantity}}).Return(nil)
+
+		err := fx.AddStock(fx.ctx, articleID, quantity, nil)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should register serials", func(t *testing.T) {
+		fx := newFixture(t)
+
+		serials := []string{testhelpers.RandomString(), testhelpers.RandomString()}
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, articleID).Return(models.Article{ID: articleID, Serialized: true}, nil)
+		fx.articlesRepo.EXPECT().AddArticles(fx.ctx, []models.ProductArticle{{ID: articleID, Quantity: 2}}).Return(nil)
+		fx.serialsRepo.EXPECT().AddSerials(fx.ctx, articleID, serials).Return(nil)
+
+		err := fx.AddStock(fx.ctx, articleID, 0, serials)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should require serials for serialized article", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, articleID).Return(models.Article{ID: articleID, Serialized: true}, nil)
+
+		err := fx.AddStock(fx.ctx, articleID, 2, []string{testhelpers.RandomString()})
+
+		require.ErrorIs(t, err, ErrSerialsRequired)
+	})
+
+	t.Run("should reject serials for regular article", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, articleID).Return(models.Article{ID: articleID}, nil)
+
+		err := fx.AddStock(fx.ctx, articleID, 1, []string{testhelpers.RandomString()})
+
+		require.ErrorIs(t, err, ErrNotSerialized)
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		err := fx.AddStock(fx.ctx, articleID, -1, nil)
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestImpl_TraceSerial(t *testing.T) {
+	serial := testhelpers.RandomString()
+
+	t.Run("should return serial on stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		item := models.ArticleSerial{
+			Serial:     serial,
+			ArticleID:  testhelpers.RandomInt32(),
+			ReceivedAt: time.Now(),
+		}
+		fx.serialsRepo.EXPECT().GetSerial(fx.ctx, serial).Return(item, nil)
+
+		trace, err := fx.TraceSerial(fx.ctx, serial)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SerialTrace{ArticleSerial: item}, trace)
+	})
+
+	t.Run("should return sale of sold serial", func(t *testing.T) {
+		fx := newFixture(t)
+
+		sale := models.Sale{
+			ID:        testhelpers.RandomInt32(),
+			ProductID: testhelpers.RandomInt32(),
+			Quantity:  1,
+		}
+		item := models.ArticleSerial{
+			Serial:    serial,
+			ArticleID: testhelpers.RandomInt32(),
+			SaleID:    &sale.ID,
+		}
+		fx.serialsRepo.EXPECT().GetSerial(fx.ctx, serial).Return(item, nil)
+		fx.salesRepo.EXPECT().GetSale(fx.ctx, sale.ID).Return(sale, nil)
+
+		trace, err := fx.TraceSerial(fx.ctx, serial)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SerialTrace{ArticleSerial: item, Sale: &sale}, trace)
+	})
+}
+
+func TestImpl_UpdateArticle(t *testing.T) {
+	item := models.Article{ID: testhelpers.RandomInt32(), Name: "leg", ReorderQuantity: 10, Version: 3}
+
+	t.Run("should update article", func(t *testing.T) {
+		fx := newFixture(t)
+
+		updated := item
+		updated.Version++
+		fx.articlesRepo.EXPECT().UpdateArticle(fx.ctx, item).Return(updated, nil)
+
+		res, err := fx.UpdateArticle(fx.ctx, item)
+
+		require.NoError(t, err)
+		assert.Equal(t, updated, res)
+	})
+
+	t.Run("should pass version conflict", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().UpdateArticle(fx.ctx, item).Return(models.Article{}, articlesrepo.ErrVersionConflict)
+
+		_, err := fx.UpdateArticle(fx.ctx, item)
+
+		require.ErrorIs(t, err, articlesrepo.ErrVersionConflict)
+	})
+
+	t.Run("should require name", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.UpdateArticle(fx.ctx, models.Article{ID: item.ID, Version: item.Version})
+
+		require.ErrorIs(t, err, ErrInvalidName)
+	})
+}
+
+func TestImpl_UpdateReorderPolicy(t *testing.T) {
+	articleID := testhelpers.RandomInt32()
+	version := testhelpers.RandomInt32()
+
+	t.Run("should update reorder policy", func(t *testing.T) {
+		fx := newFixture(t)
+
+		point := int32(testhelpers.RandomIntRange(0, 100))
+		fx.articlesRepo.EXPECT().UpdateReorderPolicy(fx.ctx, articleID, version, &point, int32(50)).
+			Return(models.Article{ID: articleID}, nil)
+
+		item, err := fx.UpdateReorderPolicy(fx.ctx, articleID, version, &point, 50)
+
+		require.NoError(t, err)
+		assert.Equal(t, articleID, item.ID)
+	})
+
+	t.Run("should disable alerts without reorder point", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().UpdateReorderPolicy(fx.ctx, articleID, version, nil, int32(0)).
+			Return(models.Article{ID: articleID}, nil)
+
+		_, err := fx.UpdateReorderPolicy(fx.ctx, articleID, version, nil, 0)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject negative reorder point", func(t *testing.T) {
+		fx := newFixture(t)
+
+		point := int32(-1)
+		_, err := fx.UpdateReorderPolicy(fx.ctx, articleID, version, &point, 10)
+
+		require.ErrorIs(t, err, ErrInvalidReorder)
+	})
+}
+
+type fixture struct {
+	Service
+
+	t            *testing.T
+	ctx          context.Context
+	tx           *mockDB.MockTransactor
+	articlesRepo *mockArticlesRepo.MockRepository
+	salesRepo    *mockSalesRepo.MockRepository
+	serialsRepo  *mockSerialsRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:            t,
+		ctx:          ctx,
+		tx:           mockDB.NewMockTransactor(ctrl),
+		articlesRepo: mockArticlesRepo.NewMockRepository(ctrl),
+		salesRepo:    mockSalesRepo.NewMockRepository(ctrl),
+		serialsRepo:  mockSerialsRepo.NewMockRepository(ctrl),
+	}
+	fx.Service = NewService(fx.tx, fx.articlesRepo, fx.salesRepo, fx.serialsRepo)
+	return fx
+}
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,13 @@
+package lowstock
+
+import "time"
+
+type Config struct {
+	Log     bool
+	Webhook WebhookConfig
+}
+
+type WebhookConfig struct {
+	URL     string
+	Timeout time.Duration
+}
@@ -0,0 +1,60 @@
+package lowstock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"warehouse/internal/logging"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+)
+
+// Evaluator detects articles crossing below their reorder point and alerts about them once per crossing
+type Evaluator interface {
+	Evaluate(ctx context.Context, ids []int32) error
+}
+
+type impl struct {
+	articlesRepo articles.Repository
+	sinks        []Sink
+	now          func() time.Time
+}
+
+func NewEvaluator(aRepo articles.Repository, sinks []Sink) Evaluator {
+	return &impl{
+		articlesRepo: aRepo,
+		sinks:        sinks,
+		now:          time.Now,
+	}
+}
+
+// Evaluate syncs the low stock flag of the articles, all articles if ids is nil.
+// Alerts are sent only for the articles which have just crossed below the reorder point.
+func (e *impl) Evaluate(ctx context.Context, ids []int32) error {
+	changed, err := e.articlesRepo.SyncLowStock(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to sync low stock: %w", err)
+	}
+
+	for _, article := range changed {
+		if !article.LowStock || article.ReorderPoint == nil {
+			continue
+		}
+		alert := models.LowStockAlert{
+			ArticleID:       article.ID,
+			Name:            article.Name,
+			Stock:           article.Stock,
+			ReorderPoint:    *article.ReorderPoint,
+			ReorderQuantity: article.ReorderQuantity,
+			DetectedAt:      e.now(),
+		}
+		for _, sink := range e.sinks {
+			err := sink.Send(ctx, alert)
+			if err != nil {
+				logging.FromContext(ctx).Error("error sending low stock alert", "article_id", article.ID, "error", err)
+			}
+		}
+	}
+	return nil
+}
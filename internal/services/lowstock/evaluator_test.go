@@ -0,0 +1,93 @@
+package lowstock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/services/lowstock/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_Evaluate(t *testing.T) {
+	point := int32(10)
+
+	t.Run("should alert articles which crossed the reorder point", func(t *testing.T) {
+		fx := newFixture(t)
+
+		ids := []int32{1, 2}
+		fx.articlesRepo.EXPECT().SyncLowStock(fx.ctx, ids).Return([]models.Article{
+			{ID: 1, Name: "leg", Stock: 8, ReorderPoint: &point, ReorderQuantity: 40, LowStock: true},
+			{ID: 2, Name: "screw", Stock: 50, ReorderPoint: &point, LowStock: false},
+		}, nil)
+		fx.sink.EXPECT().Send(fx.ctx, models.LowStockAlert{
+			ArticleID:       1,
+			Name:            "leg",
+			Stock:           8,
+			ReorderPoint:    10,
+			ReorderQuantity: 40,
+			DetectedAt:      fx.now,
+		}).Return(nil)
+
+		err := fx.Evaluate(fx.ctx, ids)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not alert without crossing", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().SyncLowStock(fx.ctx, nil).Return(nil, nil)
+
+		err := fx.Evaluate(fx.ctx, nil)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should ignore sink errors", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().SyncLowStock(fx.ctx, nil).Return([]models.Article{
+			{ID: 1, ReorderPoint: &point, LowStock: true},
+		}, nil)
+		fx.sink.EXPECT().Send(fx.ctx, gomock.Any()).Return(errors.New(testhelpers.RandomString()))
+
+		err := fx.Evaluate(fx.ctx, nil)
+
+		require.NoError(t, err)
+	})
+}
+
+type fixture struct {
+	*impl
+
+	t            *testing.T
+	ctx          context.Context
+	now          time.Time
+	articlesRepo *mockArticlesRepo.MockRepository
+	sink         *mockLowStock.MockSink
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:            t,
+		ctx:          ctx,
+		now:          time.Now(),
+		articlesRepo: mockArticlesRepo.NewMockRepository(ctrl),
+		sink:         mockLowStock.NewMockSink(ctrl),
+	}
+	fx.impl = NewEvaluator(fx.articlesRepo, []Sink{fx.sink}).(*impl)
+	fx.impl.now = func() time.Time {
+		return fx.now
+	}
+	return fx
+}
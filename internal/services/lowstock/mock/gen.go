@@ -0,0 +1,2 @@
+//go:generate mockgen -source ../sinks.go -destination mock.gen.go -package mockLowStock
+package mockLowStock
@@ -0,0 +1,93 @@
+package lowstock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"warehouse/internal/logging"
+	"warehouse/internal/models"
+)
+
+const defaultWebhookTimeout = 5 * time.Second
+
+// Sink delivers low stock alerts
+type Sink interface {
+	Send(ctx context.Context, alert models.LowStockAlert) error
+}
+
+// NewSinks creates the sinks enabled in the config
+func NewSinks(cfg Config) []Sink {
+	var sinks []Sink
+	if cfg.Log {
+		sinks = append(sinks, LogSink{})
+	}
+	if cfg.Webhook.URL != "" {
+		sinks = append(sinks, NewWebhookSink(cfg.Webhook))
+	}
+	return sinks
+}
+
+type LogSink struct{}
+
+func (LogSink) Send(ctx context.Context, alert models.LowStockAlert) error {
+	logging.FromContext(ctx).Warn("article is low on stock",
+		"article_id", alert.ArticleID,
+		"name", alert.Name,
+		"stock", alert.Stock,
+		"reorder_point", alert.ReorderPoint,
+		"reorder_quantity", alert.ReorderQuantity,
+	)
+	return nil
+}
+
+// WebhookSink posts alerts as JSON
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(cfg WebhookConfig) *WebhookSink {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookSink{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookPayload struct {
+	ArticleID       int32     `json:"article_id"`
+	Name            string    `json:"name"`
+	Stock           int32     `json:"stock"`
+	ReorderPoint    int32     `json:"reorder_point"`
+	ReorderQuantity int32     `json:"reorder_quantity"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+func (s *WebhookSink) Send(ctx context.Context, alert models.LowStockAlert) error {
+	body, err := json.Marshal(webhookPayload(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
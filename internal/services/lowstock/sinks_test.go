@@ -0,0 +1,59 @@
+package lowstock
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+)
+
+func TestWebhookSink_Send(t *testing.T) {
+	alert := models.LowStockAlert{
+		ArticleID:       1,
+		Name:            "leg",
+		Stock:           8,
+		ReorderPoint:    10,
+		ReorderQuantity: 40,
+		DetectedAt:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	t.Run("should post alert as json", func(t *testing.T) {
+		var got map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(WebhookConfig{URL: server.URL}).Send(context.Background(), alert)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"article_id":       float64(1),
+			"name":             "leg",
+			"stock":            float64(8),
+			"reorder_point":    float64(10),
+			"reorder_quantity": float64(40),
+			"detected_at":      "2024-01-02T03:04:05Z",
+		}, got)
+	})
+
+	t.Run("should fail on error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(WebhookConfig{URL: server.URL}).Send(context.Background(), alert)
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,345 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/orders"
+	"warehouse/internal/repositories/products"
+	"warehouse/internal/repositories/sales"
+	"warehouse/internal/repositories/serials"
+	"warehouse/internal/repositories/units"
+	"warehouse/internal/uom"
+)
+
+var (
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+	ErrEmptyOrder        = errors.New("order requires at least one line")
+	ErrInvalidStatus     = errors.New("operation is not allowed in the order status")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// transitions lists the statuses an order may move to a status from
+var transitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderAllocated: {models.OrderPlaced},
+	models.OrderPicking:   {models.OrderAllocated},
+	models.OrderPacked:    {models.OrderPicking},
+	models.OrderShipped:   {models.OrderPacked},
+	models.OrderCancelled: {models.OrderPlaced, models.OrderAllocated, models.OrderPicking, models.OrderPacked},
+}
+
+type Service interface {
+	PlaceOrder(ctx context.Context, lines []models.OrderLine) (models.Order, error)
+	GetOrder(ctx context.Context, id int32) (models.Order, error)
+	GetOrders(ctx context.Context, status models.OrderStatus) ([]models.Order, error)
+	AllocateOrder(ctx context.Context, id int32) (models.Order, error)
+	PickOrder(ctx context.Context, id int32) (models.Order, error)
+	PackOrder(ctx context.Context, id int32) (models.Order, error)
+	ShipOrder(ctx context.Context, id int32) (models.Order, error)
+	CancelOrder(ctx context.Context, id int32) (models.Order, error)
+}
+
+type impl struct {
+	tx           db.Transactor
+	articlesRepo articles.Repository
+	productsRepo products.Repository
+	salesRepo    sales.Repository
+	serialsRepo  serials.Repository
+	unitsRepo    units.Repository
+	ordersRepo   orders.Repository
+}
+
+func NewService(
+	tx db.Transactor,
+	aRepo articles.Repository,
+	pRepo products.Repository,
+	sRepo sales.Repository,
+	serRepo serials.Repository,
+	uRepo units.Repository,
+	oRepo orders.Repository,
+) Service {
+	return &impl{
+		tx:           tx,
+		articlesRepo: aRepo,
+		productsRepo: pRepo,
+		salesRepo:    sRepo,
+		serialsRepo:  serRepo,
+		unitsRepo:    uRepo,
+		ordersRepo:   oRepo,
+	}
+}
+
+// PlaceOrder records the order, no stock is reserved until it is allocated
+func (srv *impl) PlaceOrder(ctx context.Context, lines []models.OrderLine) (models.Order, error) {
+	if len(lines) == 0 {
+		return models.Order{}, ErrEmptyOrder
+	}
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return models.Order{}, ErrInvalidQuantity
+		}
+	}
+
+	var order models.Order
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		order = models.Order{
+			Status: models.OrderPlaced,
+			Lines:  make([]models.OrderLine, 0, len(lines)),
+		}
+		for _, line := range lines {
+			_, err := srv.productsRepo.GetProduct(ctx, line.ProductID)
+			if err != nil {
+				return fmt.Errorf("failed to get product %d: %w", line.ProductID, err)
+			}
+			order.Lines = append(order.Lines, models.OrderLine{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+			})
+		}
+
+		var err error
+		order, err = srv.ordersRepo.CreateOrder(ctx, order)
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+func (srv *impl) GetOrder(ctx context.Context, id int32) (models.Order, error) {
+	order, err := srv.ordersRepo.GetOrder(ctx, id)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// GetOrders lists orders in the status, all orders if the status is empty
+func (srv *impl) GetOrders(ctx context.Context, status models.OrderStatus) ([]models.Order, error) {
+	items, err := srv.ordersRepo.GetOrders(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	return items, nil
+}
+
+// AllocateOrder reserves stock for a placed order. Assembled units are taken first,
+// the article quantities required to build the rest of the units are reserved.
+func (srv *impl) AllocateOrder(ctx context.Context, id int32) (models.Order, error) {
+	return srv.transition(ctx, id, models.OrderAllocated, func(ctx context.Context, order *models.Order) error {
+		conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+		if err != nil {
+			return err
+		}
+
+		var total []models.ProductArticle
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			err = srv.allocateLine(ctx, conv, line)
+			if err != nil {
+				return err
+			}
+			err = srv.ordersRepo.AllocateLine(ctx, line.ID, line.Assembled, line.Reserved)
+			if err != nil {
+				return fmt.Errorf("failed to allocate line %d: %w", line.ID, err)
+			}
+			total = addArticles(total, line.Reserved)
+		}
+		if len(total) == 0 {
+			return nil
+		}
+
+		err = srv.articlesRepo.ReserveArticles(ctx, total)
+		if err != nil {
+			return fmt.Errorf("failed to reserve articles: %w", err)
+		}
+		return nil
+	})
+}
+
+// PickOrder starts picking an allocated order
+func (srv *impl) PickOrder(ctx context.Context, id int32) (models.Order, error) {
+	return srv.transition(ctx, id, models.OrderPicking, nil)
+}
+
+// PackOrder marks a picked order as packed
+func (srv *impl) PackOrder(ctx context.Context, id int32) (models.Order, error) {
+	return srv.transition(ctx, id, models.OrderPacked, nil)
+}
+
+// ShipOrder removes the reserved stock and records a sale per order line.
+// Serialized articles are assigned available serials.
+func (srv *impl) ShipOrder(ctx context.Context, id int32) (models.Order, error) {
+	return srv.transition(ctx, id, models.OrderShipped, func(ctx context.Context, order *models.Order) error {
+		var total []models.ProductArticle
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			sale, err := srv.salesRepo.CreateSale(ctx, models.Sale{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create sale: %w", err)
+			}
+			for _, item := range line.Reserved {
+				article, err := srv.articlesRepo.GetArticle(ctx, item.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get article: %w", err)
+				}
+				if !article.Serialized {
+					continue
+				}
+				_, err = srv.serialsRepo.AssignAvailableSerials(ctx, sale.ID, item.ID, item.Quantity)
+				if err != nil {
+					return fmt.Errorf("failed to assign serials: %w", err)
+				}
+			}
+			err = srv.ordersRepo.ShipLine(ctx, line.ID, sale.ID)
+			if err != nil {
+				return fmt.Errorf("failed to ship line %d: %w", line.ID, err)
+			}
+			line.SaleID = &sale.ID
+			total = addArticles(total, line.Reserved)
+		}
+		if len(total) == 0 {
+			return nil
+		}
+
+		err := srv.articlesRepo.RemoveReserved(ctx, total)
+		if err != nil {
+			return fmt.Errorf("failed to remove articles: %w", err)
+		}
+		return nil
+	})
+}
+
+// CancelOrder cancels an order which is not shipped yet, the reserved stock is released
+// and the assembled units are returned to the product stock
+func (srv *impl) CancelOrder(ctx context.Context, id int32) (models.Order, error) {
+	return srv.transition(ctx, id, models.OrderCancelled, func(ctx context.Context, order *models.Order) error {
+		if order.Status == models.OrderPlaced {
+			return nil
+		}
+
+		var total []models.ProductArticle
+		for _, line := range order.Lines {
+			if line.Assembled > 0 {
+				err := srv.productsRepo.AddAssembled(ctx, line.ProductID, line.Assembled)
+				if err != nil {
+					return fmt.Errorf("failed to return assembled units: %w", err)
+				}
+			}
+			total = addArticles(total, line.Reserved)
+		}
+		if len(total) == 0 {
+			return nil
+		}
+
+		err := srv.articlesRepo.ReleaseArticles(ctx, total)
+		if err != nil {
+			return fmt.Errorf("failed to release articles: %w", err)
+		}
+		return nil
+	})
+}
+
+// transition moves the order to the status if the transitions allow it from the current status.
+// apply is called with the locked order in its current status before the status is updated.
+func (srv *impl) transition(
+	ctx context.Context,
+	id int32,
+	to models.OrderStatus,
+	apply func(ctx context.Context, order *models.Order) error,
+) (models.Order, error) {
+	var order models.Order
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = srv.ordersRepo.GetOrderForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if !slices.Contains(transitions[to], order.Status) {
+			return ErrInvalidStatus
+		}
+
+		if apply != nil {
+			err = apply(ctx, &order)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = srv.ordersRepo.UpdateStatus(ctx, id, to)
+		if err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		order.Status = to
+		return nil
+	})
+	if err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// allocateLine takes assembled units of the line product and calculates the article quantities
+// required to build the rest, normalized to the article units
+func (srv *impl) allocateLine(ctx context.Context, conv *uom.Converter, line *models.OrderLine) error {
+	product, err := srv.productsRepo.GetProduct(ctx, line.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	toBuild := line.Quantity
+	if product.Assembled > 0 {
+		line.Assembled, err = srv.productsRepo.TakeAssembled(ctx, product.ID, line.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to take assembled units: %w", err)
+		}
+		toBuild -= line.Assembled
+	}
+	if toBuild == 0 {
+		return nil
+	}
+	if len(product.Articles) == 0 {
+		return fmt.Errorf("%w: product %d", ErrInsufficientStock, product.ID)
+	}
+
+	for _, a := range product.Articles {
+		article, err := srv.articlesRepo.GetArticle(ctx, a.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get article %d: %w", a.ID, err)
+		}
+		item, err := conv.Normalize(article, a)
+		if err != nil {
+			return fmt.Errorf("failed to normalize product %d: %w", product.ID, err)
+		}
+		item.Quantity *= toBuild
+		line.Reserved = addArticles(line.Reserved, []models.ProductArticle{item})
+	}
+	return nil
+}
+
+// addArticles adds the quantities to the list, keeping one item per article
+func addArticles(to []models.ProductArticle, items []models.ProductArticle) []models.ProductArticle {
+	for _, item := range items {
+		i := slices.IndexFunc(to, func(a models.ProductArticle) bool {
+			return a.ID == item.ID
+		})
+		if i < 0 {
+			to = append(to, item)
+			continue
+		}
+		to[i].Quantity += item.Quantity
+	}
+	return to
+}
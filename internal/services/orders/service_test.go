@@ -0,0 +1,320 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/db/mock"
+	"warehouse/internal/models"
+	articlesrepo "warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/orders/mock"
+	"warehouse/internal/repositories/products/mock"
+	"warehouse/internal/repositories/sales/mock"
+	"warehouse/internal/repositories/serials/mock"
+	"warehouse/internal/repositories/units/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_PlaceOrder(t *testing.T) {
+	t.Run("should place order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(models.Product{ID: 1}, nil)
+		expected := models.Order{
+			Status: models.OrderPlaced,
+			Lines:  []models.OrderLine{{ProductID: 1, Quantity: 2}},
+		}
+		fx.ordersRepo.EXPECT().CreateOrder(fx.ctx, expected).Return(expected, nil)
+
+		order, err := fx.PlaceOrder(fx.ctx, []models.OrderLine{{ProductID: 1, Quantity: 2}})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, order)
+	})
+
+	t.Run("should reject empty order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.PlaceOrder(fx.ctx, nil)
+
+		require.ErrorIs(t, err, ErrEmptyOrder)
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.PlaceOrder(fx.ctx, []models.OrderLine{{ProductID: 1}})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestImpl_AllocateOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+	product := models.Product{
+		ID: 1,
+		Articles: []models.ProductArticle{
+			{ID: 1, Quantity: 2},
+			{ID: 2, Quantity: 1, Unit: "m"},
+		},
+	}
+
+	t.Run("should take assembled units and reserve articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).Return(models.Order{
+			ID:     orderID,
+			Status: models.OrderPlaced,
+			Lines: []models.OrderLine{
+				{ID: 10, ProductID: 1, Quantity: 3},
+				{ID: 11, ProductID: 1, Quantity: 1},
+			},
+		}, nil)
+		fx.expectUnits()
+		assembled := product
+		assembled.Assembled = 1
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(assembled, nil)
+		fx.productsRepo.EXPECT().TakeAssembled(fx.ctx, int32(1), int32(3)).Return(int32(1), nil)
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(product, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(1)).Return(models.Article{ID: 1, Unit: "pcs"}, nil).Times(2)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(2)).Return(models.Article{ID: 2, Unit: "cm"}, nil).Times(2)
+		fx.ordersRepo.EXPECT().AllocateLine(fx.ctx, int32(10), int32(1), []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 200},
+		}).Return(nil)
+		fx.ordersRepo.EXPECT().AllocateLine(fx.ctx, int32(11), int32(0), []models.ProductArticle{
+			{ID: 1, Quantity: 2},
+			{ID: 2, Quantity: 100},
+		}).Return(nil)
+		fx.articlesRepo.EXPECT().ReserveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 6},
+			{ID: 2, Quantity: 300},
+		}).Return(nil)
+		fx.ordersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.OrderAllocated).Return(nil)
+
+		order, err := fx.AllocateOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderAllocated, order.Status)
+		assert.EqualValues(t, 1, order.Lines[0].Assembled)
+	})
+
+	t.Run("should fail when articles cannot be reserved", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).Return(models.Order{
+			ID:     orderID,
+			Status: models.OrderPlaced,
+			Lines:  []models.OrderLine{{ID: 10, ProductID: 1, Quantity: 1}},
+		}, nil)
+		fx.expectUnits()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(models.Product{
+			ID:       1,
+			Articles: []models.ProductArticle{{ID: 1, Quantity: 2}},
+		}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(1)).Return(models.Article{ID: 1, Unit: "pcs"}, nil)
+		reserved := []models.ProductArticle{{ID: 1, Quantity: 2}}
+		fx.ordersRepo.EXPECT().AllocateLine(fx.ctx, int32(10), int32(0), reserved).Return(nil)
+		fx.articlesRepo.EXPECT().ReserveArticles(fx.ctx, reserved).Return(articlesrepo.ErrInsufficientStock)
+
+		_, err := fx.AllocateOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, articlesrepo.ErrInsufficientStock)
+	})
+
+	t.Run("should reject allocated order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderAllocated}, nil)
+
+		_, err := fx.AllocateOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+func TestImpl_PickOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+
+	t.Run("should pick allocated order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderAllocated}, nil)
+		fx.ordersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.OrderPicking).Return(nil)
+
+		order, err := fx.PickOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderPicking, order.Status)
+	})
+
+	t.Run("should reject placed order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderPlaced}, nil)
+
+		_, err := fx.PickOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+func TestImpl_ShipOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+
+	t.Run("should remove reserved articles and record sales", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).Return(models.Order{
+			ID:     orderID,
+			Status: models.OrderPacked,
+			Lines: []models.OrderLine{
+				{ID: 10, ProductID: 1, Quantity: 2, Reserved: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 5, Quantity: 2}}},
+				{ID: 11, ProductID: 2, Quantity: 1, Assembled: 1},
+			},
+		}, nil)
+		fx.salesRepo.EXPECT().CreateSale(fx.ctx, models.Sale{ProductID: 1, Quantity: 2}).Return(models.Sale{ID: 100}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(1)).Return(models.Article{ID: 1}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(5)).Return(models.Article{ID: 5, Serialized: true}, nil)
+		fx.serialsRepo.EXPECT().AssignAvailableSerials(fx.ctx, int32(100), int32(5), int32(2)).Return([]string{"A", "B"}, nil)
+		fx.ordersRepo.EXPECT().ShipLine(fx.ctx, int32(10), int32(100)).Return(nil)
+		fx.salesRepo.EXPECT().CreateSale(fx.ctx, models.Sale{ProductID: 2, Quantity: 1}).Return(models.Sale{ID: 101}, nil)
+		fx.ordersRepo.EXPECT().ShipLine(fx.ctx, int32(11), int32(101)).Return(nil)
+		fx.articlesRepo.EXPECT().RemoveReserved(fx.ctx, []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 5, Quantity: 2}}).Return(nil)
+		fx.ordersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.OrderShipped).Return(nil)
+
+		order, err := fx.ShipOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderShipped, order.Status)
+		require.NotNil(t, order.Lines[1].SaleID)
+		assert.EqualValues(t, 101, *order.Lines[1].SaleID)
+	})
+
+	t.Run("should reject picking order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderPicking}, nil)
+
+		_, err := fx.ShipOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+func TestImpl_CancelOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+
+	t.Run("should release reservations", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).Return(models.Order{
+			ID:     orderID,
+			Status: models.OrderPicking,
+			Lines: []models.OrderLine{
+				{ID: 10, ProductID: 1, Quantity: 2, Reserved: []models.ProductArticle{{ID: 1, Quantity: 4}}},
+				{ID: 11, ProductID: 2, Quantity: 3, Assembled: 2, Reserved: []models.ProductArticle{{ID: 1, Quantity: 1}}},
+			},
+		}, nil)
+		fx.productsRepo.EXPECT().AddAssembled(fx.ctx, int32(2), int32(2)).Return(nil)
+		fx.articlesRepo.EXPECT().ReleaseArticles(fx.ctx, []models.ProductArticle{{ID: 1, Quantity: 5}}).Return(nil)
+		fx.ordersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.OrderCancelled).Return(nil)
+
+		order, err := fx.CancelOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderCancelled, order.Status)
+	})
+
+	t.Run("should cancel placed order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderPlaced}, nil)
+		fx.ordersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.OrderCancelled).Return(nil)
+
+		_, err := fx.CancelOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject shipped order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.ordersRepo.EXPECT().GetOrderForUpdate(fx.ctx, orderID).
+			Return(models.Order{ID: orderID, Status: models.OrderShipped}, nil)
+
+		_, err := fx.CancelOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+type fixture struct {
+	Service
+
+	t            *testing.T
+	ctx          context.Context
+	tx           *mockDB.MockTransactor
+	articlesRepo *mockArticlesRepo.MockRepository
+	productsRepo *mockProductsRepo.MockRepository
+	salesRepo    *mockSalesRepo.MockRepository
+	serialsRepo  *mockSerialsRepo.MockRepository
+	unitsRepo    *mockUnitsRepo.MockRepository
+	ordersRepo   *mockOrdersRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:            t,
+		ctx:          ctx,
+		tx:           mockDB.NewMockTransactor(ctrl),
+		articlesRepo: mockArticlesRepo.NewMockRepository(ctrl),
+		productsRepo: mockProductsRepo.NewMockRepository(ctrl),
+		salesRepo:    mockSalesRepo.NewMockRepository(ctrl),
+		serialsRepo:  mockSerialsRepo.NewMockRepository(ctrl),
+		unitsRepo:    mockUnitsRepo.NewMockRepository(ctrl),
+		ordersRepo:   mockOrdersRepo.NewMockRepository(ctrl),
+	}
+	fx.Service = NewService(fx.tx, fx.articlesRepo, fx.productsRepo, fx.salesRepo, fx.serialsRepo, fx.unitsRepo, fx.ordersRepo)
+	return fx
+}
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
+
+func (fx *fixture) expectUnits() {
+	units := []models.Unit{
+		{Code: "pcs", Dimension: "count", Factor: 1},
+		{Code: "cm", Dimension: "length", Factor: 10},
+		{Code: "m", Dimension: "length", Factor: 1000},
+	}
+	fx.unitsRepo.EXPECT().GetUnits(fx.ctx).Return(units, nil)
+	fx.unitsRepo.EXPECT().GetArticleUnits(fx.ctx).Return(nil, nil)
+}
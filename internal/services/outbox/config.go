@@ -0,0 +1,32 @@
+package outbox
+
+import "time"
+
+const (
+	defaultBatchSize     = 100
+	defaultRetryDelay    = time.Second
+	defaultMaxRetryDelay = 5 * time.Minute
+	defaultClaimTimeout  = time.Minute
+)
+
+type Config struct {
+	// PollInterval is the delay between relay runs when there are no pending events
+	PollInterval  time.Duration `koanf:"poll_interval"`
+	BatchSize     int           `koanf:"batch_size"`
+	RetryDelay    time.Duration `koanf:"retry_delay"`
+	MaxRetryDelay time.Duration `koanf:"max_retry_delay"`
+	// ClaimTimeout is the time a relay has to publish a claimed batch, the events of a relay
+	// that stopped are relayed again once the claim expired
+	ClaimTimeout time.Duration `koanf:"claim_timeout"`
+	File         FileConfig
+	Webhook      WebhookConfig
+}
+
+type FileConfig struct {
+	Path string
+}
+
+type WebhookConfig struct {
+	URL     string
+	Timeout time.Duration
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/outbox"
+)
+
+// Relay delivers the events written to the outbox to the sinks
+type Relay interface {
+	// Relay delivers a batch of pending events and returns the number of events processed
+	Relay(ctx context.Context) (int, error)
+}
+
+type relay struct {
+	cfg        Config
+	outboxRepo outbox.Repository
+	sinks      []Sink
+	observers  []Observer
+	now        func() time.Time
+}
+
+func NewRelay(cfg Config, oRepo outbox.Repository, sinks []Sink, observers []Observer) Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = defaultMaxRetryDelay
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = defaultClaimTimeout
+	}
+	return &relay{
+		cfg:        cfg,
+		outboxRepo: oRepo,
+		sinks:      sinks,
+		observers:  observers,
+		now:        time.Now,
+	}
+}
+
+// Relay publishes every pending event to all sinks. An event is marked delivered once all sinks accepted it,
+// otherwise it is retried with an exponential backoff and published to all sinks again, so delivery is
+// at least once. Events whose payload cannot be decoded are marked dead and not retried. Observers are
+// notified once the first attempt of an event was marked, whether the sinks accepted it or not. Events are
+// claimed before they are published, so no transaction or connection is held while the sinks are called.
+// Publishing is cancelled when the claim expires.
+func (r *relay) Relay(ctx context.Context) (int, error) {
+	items, err := r.outboxRepo.ClaimEvents(ctx, r.cfg.BatchSize, r.now().Add(r.cfg.ClaimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, r.cfg.ClaimTimeout)
+	defer cancel()
+	delivered := make([]int64, 0, len(items))
+	relayed := make([]*warehousepb.Event, 0, len(items))
+	for _, item := range items {
+		event, err := toEvent(item)
+		if err != nil {
+			// a payload which cannot be decoded never will, retrying it would only block the sinks
+			err = r.outboxRepo.MarkDead(ctx, item.ID, err.Error())
+			if err != nil {
+				return 0, fmt.Errorf("failed to mark event %d dead: %w", item.ID, err)
+			}
+			continue
+		}
+		if item.Attempts == 0 {
+			relayed = append(relayed, event)
+		}
+		err = r.publish(publishCtx, event)
+		if err == nil {
+			delivered = append(delivered, item.ID)
+			continue
+		}
+		err = r.outboxRepo.MarkFailed(ctx, item.ID, err.Error(), r.now().Add(r.backoff(item.Attempts)))
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark event %d failed: %w", item.ID, err)
+		}
+	}
+	if len(delivered) > 0 {
+		err = r.outboxRepo.MarkDelivered(ctx, delivered)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark events delivered: %w", err)
+		}
+	}
+	for _, event := range relayed {
+		for _, observer := range r.observers {
+			observer.Observe(ctx, event)
+		}
+	}
+	return len(items), nil
+}
+
+func (r *relay) publish(ctx context.Context, event *warehousepb.Event) error {
+	var errs []error
+	for _, sink := range r.sinks {
+		err := sink.Publish(ctx, event)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backoff doubles the retry delay with every failed attempt up to the max retry delay
+func (r *relay) backoff(attempts int32) time.Duration {
+	delay := r.cfg.RetryDelay
+	for i := int32(0); i < attempts && delay < r.cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, r.cfg.MaxRetryDelay)
+}
+
+// toEvent decodes the outbox payload, the event ID and time are taken from the outbox row
+func toEvent(item models.OutboxEvent) (*warehousepb.Event, error) {
+	event := &warehousepb.Event{}
+	err := protojson.Unmarshal(item.Payload, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", item.Type, err)
+	}
+	event.Id = item.ID
+	event.OccurredAt = timestamppb.New(item.CreatedAt)
+	return event, nil
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"warehouse/api/warehousepb"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/outbox/mock"
+	"warehouse/internal/services/outbox/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestRelay_Relay(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	item := models.OutboxEvent{
+		ID:        7,
+		Type:      "ProductSold",
+		Payload:   []byte(`{"productSold": {"saleId": 1, "productId": 2, "quantity": 3}}`),
+		CreatedAt: createdAt,
+	}
+	event := &warehousepb.Event{
+		Id:         7,
+		OccurredAt: timestamppb.New(createdAt),
+		Payload: &warehousepb.Event_ProductSold_{ProductSold: &warehousepb.Event_ProductSold{
+			SaleId:    1,
+			ProductId: 2,
+			Quantity:  3,
+		}},
+	}
+
+	t.Run("should publish pending events and mark them delivered", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return([]models.OutboxEvent{item}, nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, got *warehousepb.Event) error {
+			assert.True(t, proto.Equal(event, got))
+			return nil
+		})
+		fx.outboxRepo.EXPECT().MarkDelivered(fx.ctx, []int64{7}).Return(nil)
+		fx.observer.EXPECT().Observe(fx.ctx, gomock.Any()).Do(func(_ context.Context, got *warehousepb.Event) {
+			assert.True(t, proto.Equal(event, got))
+		})
+
+		processed, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("should not mark anything without pending events", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return(nil, nil)
+
+		processed, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+	})
+
+	t.Run("should retry failed events with backoff", func(t *testing.T) {
+		fx := newFixture(t)
+
+		failed := item
+		failed.Attempts = 2
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return([]models.OutboxEvent{failed}, nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("unavailable"))
+		fx.outboxRepo.EXPECT().MarkFailed(fx.ctx, int64(7), "unavailable", fx.now.Add(4*defaultRetryDelay)).Return(nil)
+
+		processed, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("should notify observers of failed first attempts", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return([]models.OutboxEvent{item}, nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("unavailable"))
+		fx.outboxRepo.EXPECT().MarkFailed(fx.ctx, int64(7), "unavailable", fx.now.Add(defaultRetryDelay)).Return(nil)
+		fx.observer.EXPECT().Observe(fx.ctx, gomock.Any()).Do(func(_ context.Context, got *warehousepb.Event) {
+			assert.True(t, proto.Equal(event, got))
+		})
+
+		_, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not notify observers of retries", func(t *testing.T) {
+		fx := newFixture(t)
+
+		retried := item
+		retried.Attempts = 1
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return([]models.OutboxEvent{retried}, nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+		fx.outboxRepo.EXPECT().MarkDelivered(fx.ctx, []int64{7}).Return(nil)
+
+		_, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not notify observers if marking failed", func(t *testing.T) {
+		fx := newFixture(t)
+
+		expectedErr := errors.New(testhelpers.RandomString())
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return([]models.OutboxEvent{item}, nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+		fx.outboxRepo.EXPECT().MarkDelivered(fx.ctx, []int64{7}).Return(expectedErr)
+
+		_, err := fx.Relay(fx.ctx)
+
+		require.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("should mark undecodable events dead", func(t *testing.T) {
+		fx := newFixture(t)
+
+		broken := item
+		broken.ID = 8
+		broken.Payload = []byte(`{"productSold": {"saleId": 1, "unknown": 2}}`)
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).
+			Return([]models.OutboxEvent{broken, item}, nil)
+		fx.outboxRepo.EXPECT().MarkDead(fx.ctx, int64(8), gomock.Any()).Return(nil)
+		fx.sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+		fx.outboxRepo.EXPECT().MarkDelivered(fx.ctx, []int64{7}).Return(nil)
+		fx.observer.EXPECT().Observe(fx.ctx, gomock.Any())
+
+		processed, err := fx.Relay(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, processed)
+	})
+
+	t.Run("should fail on repo error", func(t *testing.T) {
+		fx := newFixture(t)
+
+		expectedErr := errors.New(testhelpers.RandomString())
+		fx.outboxRepo.EXPECT().ClaimEvents(fx.ctx, defaultBatchSize, fx.now.Add(defaultClaimTimeout)).Return(nil, expectedErr)
+
+		_, err := fx.Relay(fx.ctx)
+
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	r := NewRelay(Config{RetryDelay: time.Second, MaxRetryDelay: 10 * time.Second}, nil, nil, nil).(*relay)
+
+	assert.Equal(t, time.Second, r.backoff(0))
+	assert.Equal(t, 8*time.Second, r.backoff(3))
+	assert.Equal(t, 10*time.Second, r.backoff(4))
+	assert.Equal(t, 10*time.Second, r.backoff(100))
+}
+
+type fixture struct {
+	*relay
+
+	t          *testing.T
+	ctx        context.Context
+	now        time.Time
+	outboxRepo *mockOutboxRepo.MockRepository
+	sink       *mockOutbox.MockSink
+	observer   *mockOutbox.MockObserver
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:          t,
+		ctx:        ctx,
+		now:        time.Now(),
+		outboxRepo: mockOutboxRepo.NewMockRepository(ctrl),
+		sink:       mockOutbox.NewMockSink(ctrl),
+		observer:   mockOutbox.NewMockObserver(ctrl),
+	}
+	fx.relay = NewRelay(Config{}, fx.outboxRepo, []Sink{fx.sink}, []Observer{fx.observer}).(*relay)
+	fx.relay.now = func() time.Time {
+		return fx.now
+	}
+	return fx
+}
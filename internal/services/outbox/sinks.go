@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"warehouse/api/warehousepb"
+)
+
+const defaultWebhookTimeout = 5 * time.Second
+
+// Sink delivers domain events, an error makes the relay retry the event
+type Sink interface {
+	Publish(ctx context.Context, event *warehousepb.Event) error
+}
+
+// Observer is notified of every event once its first attempt is marked, so an unavailable sink does not
+// keep it from observing events and retries do not notify it again
+type Observer interface {
+	Observe(ctx context.Context, event *warehousepb.Event)
+}
+
+// NewSinks creates the sinks enabled in the config
+func NewSinks(cfg Config) []Sink {
+	var sinks []Sink
+	if cfg.File.Path != "" {
+		sinks = append(sinks, NewFileSink(cfg.File))
+	}
+	if cfg.Webhook.URL != "" {
+		sinks = append(sinks, NewWebhookSink(cfg.Webhook))
+	}
+	return sinks
+}
+
+// FileSink appends events to a file as JSON lines in the protobuf JSON mapping
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(cfg FileConfig) *FileSink {
+	return &FileSink{
+		path: cfg.Path,
+	}
+}
+
+func (s *FileSink) Publish(_ context.Context, event *warehousepb.Event) error {
+	line, err := protojson.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WebhookSink posts every event in the protobuf binary encoding
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(cfg WebhookConfig) *WebhookSink {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookSink{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event *warehousepb.Event) error {
+	body, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Event-Id", strconv.FormatInt(event.Id, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"warehouse/api/warehousepb"
+)
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(FileConfig{Path: path})
+
+	for id := int64(1); id <= 2; id++ {
+		err := sink.Publish(context.Background(), &warehousepb.Event{
+			Id: id,
+			Payload: &warehousepb.Event_ProductSold_{ProductSold: &warehousepb.Event_ProductSold{
+				SaleId: int32(id),
+			}},
+		})
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		event := &warehousepb.Event{}
+		require.NoError(t, protojson.Unmarshal([]byte(line), event))
+		assert.Equal(t, int64(i+1), event.Id)
+		assert.Equal(t, int32(i+1), event.GetProductSold().GetSaleId())
+	}
+}
+
+func TestWebhookSink_Publish(t *testing.T) {
+	event := &warehousepb.Event{
+		Id: 7,
+		Payload: &warehousepb.Event_ArticleStockChanged_{ArticleStockChanged: &warehousepb.Event_ArticleStockChanged{
+			ArticleId:   1,
+			StockBefore: 4,
+			StockAfter:  2,
+		}},
+	}
+
+	t.Run("should post event as protobuf", func(t *testing.T) {
+		got := &warehousepb.Event{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+			assert.Equal(t, "7", r.Header.Get("X-Event-Id"))
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, proto.Unmarshal(body, got))
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(WebhookConfig{URL: server.URL}).Publish(context.Background(), event)
+
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(event, got))
+	})
+
+	t.Run("should fail on error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(WebhookConfig{URL: server.URL}).Publish(context.Background(), event)
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,226 @@
+package products
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"warehouse/internal/models"
+)
+
+// Cache keeps the product stock calculated by GetProductsWithStock in memory.
+// A change of an article invalidates only the products made of it, found through an article to products index.
+// Invalidated products are recalculated on the next call, other methods are passed to the wrapped service.
+// Changes made through the cache are invalidated as soon as they are committed, so that the same instance
+// reads its own writes, changes made elsewhere are invalidated when notified.
+type Cache struct {
+	Service
+
+	mu sync.Mutex
+	// loaded is false until all products are calculated, and again after InvalidateAll
+	loaded bool
+	items  map[int32]models.ProductWithStock
+	// byArticle indexes the cached products by the articles they are made of, unknown articles included
+	byArticle map[int32][]int32
+	// stale lists the products to recalculate, they might be new or deleted
+	stale map[int32]struct{}
+}
+
+func NewCache(srv Service) *Cache {
+	return &Cache{
+		Service: srv,
+		stale:   make(map[int32]struct{}),
+	}
+}
+
+// GetProductsWithStock returns the cached products, all products are calculated on the first call
+// and only the invalidated ones afterwards
+func (c *Cache) GetProductsWithStock(ctx context.Context) ([]models.ProductWithStock, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		items, err := c.Service.GetProductsWithStock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.items = make(map[int32]models.ProductWithStock, len(items))
+		c.byArticle = make(map[int32][]int32)
+		clear(c.stale)
+		for _, item := range items {
+			c.put(item)
+		}
+		c.loaded = true
+	} else if len(c.stale) > 0 {
+		ids := make([]int32, 0, len(c.stale))
+		for id := range c.stale {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+
+		items, err := c.Service.GetProductsWithStockByID(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			c.remove(id)
+		}
+		for _, item := range items {
+			c.put(item)
+		}
+		clear(c.stale)
+	}
+
+	items := make([]models.ProductWithStock, 0, len(c.items))
+	for _, item := range c.items {
+		items = append(items, item)
+	}
+	slices.SortFunc(items, func(a, b models.ProductWithStock) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return items, nil
+}
+
+func (c *Cache) UpdateProduct(ctx context.Context, item models.Product) (models.ProductWithStock, error) {
+	res, err := c.Service.UpdateProduct(ctx, item)
+	if err == nil {
+		c.invalidateStock(item.ID)
+	}
+	return res, err
+}
+
+func (c *Cache) RemoveProduct(
+	ctx context.Context,
+	id, quantity int32,
+	serials []string,
+	allowBackorder bool,
+) (models.Sale, error) {
+	res, err := c.Service.RemoveProduct(ctx, id, quantity, serials, allowBackorder)
+	if err == nil {
+		c.invalidateStock(id)
+	}
+	return res, err
+}
+
+func (c *Cache) CreateAssemblyOrder(ctx context.Context, productID, quantity int32) (models.AssemblyOrder, error) {
+	res, err := c.Service.CreateAssemblyOrder(ctx, productID, quantity)
+	if err == nil {
+		c.invalidateStock(productID)
+	}
+	return res, err
+}
+
+func (c *Cache) CompleteAssemblyOrder(ctx context.Context, id int32) (models.AssemblyOrder, error) {
+	res, err := c.Service.CompleteAssemblyOrder(ctx, id)
+	if err == nil {
+		c.invalidateStock(res.ProductID)
+	}
+	return res, err
+}
+
+func (c *Cache) DisassembleProduct(
+	ctx context.Context,
+	productID, quantity int32,
+	fromAssembled bool,
+	scrap []models.ProductArticle,
+) (models.Disassembly, error) {
+	res, err := c.Service.DisassembleProduct(ctx, productID, quantity, fromAssembled, scrap)
+	if err == nil {
+		c.invalidateStock(productID)
+	}
+	return res, err
+}
+
+func (c *Cache) FulfilBackorders(ctx context.Context, articleIDs []int32) ([]models.Backorder, error) {
+	res, err := c.Service.FulfilBackorders(ctx, articleIDs)
+	if err == nil {
+		productIDs := make([]int32, 0, len(res))
+		for _, item := range res {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		c.InvalidateArticles(articleIDs)
+		c.invalidateStock(productIDs...)
+	}
+	return res, err
+}
+
+// InvalidateArticles marks the products made of the articles for recalculation
+func (c *Cache) InvalidateArticles(ids []int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		for _, productID := range c.byArticle[id] {
+			c.stale[productID] = struct{}{}
+		}
+	}
+}
+
+// InvalidateProducts marks the products for recalculation, unknown products are added when calculated
+func (c *Cache) InvalidateProducts(ids []int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		return
+	}
+	for _, id := range ids {
+		c.stale[id] = struct{}{}
+	}
+}
+
+// InvalidateAll drops the cache, all products are calculated on the next call
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaded = false
+	c.items = nil
+	c.byArticle = nil
+	clear(c.stale)
+}
+
+// invalidateStock marks the products for recalculation together with all products sharing an article with them,
+// their stock changes when the articles are consumed or returned
+func (c *Cache) invalidateStock(ids ...int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		return
+	}
+	for _, id := range ids {
+		c.stale[id] = struct{}{}
+		for _, art := range c.items[id].Articles {
+			for _, productID := range c.byArticle[art.ID] {
+				c.stale[productID] = struct{}{}
+			}
+		}
+	}
+}
+
+func (c *Cache) put(item models.ProductWithStock) {
+	c.items[item.ID] = item
+	for _, art := range item.Articles {
+		if !slices.Contains(c.byArticle[art.ID], item.ID) {
+			c.byArticle[art.ID] = append(c.byArticle[art.ID], item.ID)
+		}
+	}
+}
+
+func (c *Cache) remove(id int32) {
+	item, ok := c.items[id]
+	if !ok {
+		return
+	}
+	delete(c.items, id)
+	for _, art := range item.Articles {
+		c.byArticle[art.ID] = slices.DeleteFunc(c.byArticle[art.ID], func(productID int32) bool {
+			return productID == id
+		})
+		if len(c.byArticle[art.ID]) == 0 {
+			delete(c.byArticle, art.ID)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package products
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/models"
+	"warehouse/internal/services/products/mock"
+)
+
+func TestCache_GetProductsWithStock(t *testing.T) {
+	table := models.ProductWithStock{
+		Product: models.Product{ID: 1, Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 2, Quantity: 1}}},
+		Stock:   2,
+	}
+	chair := models.ProductWithStock{
+		Product: models.Product{ID: 2, Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 3, Quantity: 1}}},
+		Stock:   3,
+	}
+	shelf := models.ProductWithStock{
+		Product: models.Product{ID: 3, Articles: []models.ProductArticle{{ID: 4, Quantity: 2}}},
+		Stock:   5,
+	}
+
+	t.Run("should calculate all products once", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table, chair}, nil)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, chair}, items)
+
+		items, err = fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, chair}, items)
+	})
+
+	t.Run("should recalculate only products made of changed articles", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table, chair, shelf}, nil)
+		_, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+
+		fx.InvalidateArticles([]int32{3})
+		updated := chair
+		updated.Stock = 1
+		fx.srv.EXPECT().GetProductsWithStockByID(fx.ctx, []int32{2}).Return([]models.ProductWithStock{updated}, nil)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, updated, shelf}, items)
+
+		fx.InvalidateArticles([]int32{1, 9})
+		fx.srv.EXPECT().GetProductsWithStockByID(fx.ctx, []int32{1, 2}).Return([]models.ProductWithStock{table, chair}, nil)
+
+		items, err = fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, chair, shelf}, items)
+	})
+
+	t.Run("should add new and drop deleted products", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table, chair}, nil)
+		_, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+
+		fx.InvalidateProducts([]int32{3, 2})
+		fx.srv.EXPECT().GetProductsWithStockByID(fx.ctx, []int32{2, 3}).Return([]models.ProductWithStock{shelf}, nil)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, shelf}, items)
+
+		// the deleted product is no longer indexed
+		fx.InvalidateArticles([]int32{3})
+		items, err = fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, shelf}, items)
+	})
+
+	t.Run("should recalculate products sharing articles with a local sale", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table, chair, shelf}, nil)
+		_, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+
+		fx.srv.EXPECT().RemoveProduct(fx.ctx, int32(1), int32(1), nil, false).Return(models.Sale{}, nil)
+		_, err = fx.RemoveProduct(fx.ctx, 1, 1, nil, false)
+		require.NoError(t, err)
+
+		soldTable, soldChair := table, chair
+		soldTable.Stock, soldChair.Stock = 1, 2
+		fx.srv.EXPECT().GetProductsWithStockByID(fx.ctx, []int32{1, 2}).Return([]models.ProductWithStock{soldTable, soldChair}, nil)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{soldTable, soldChair, shelf}, items)
+	})
+
+	t.Run("should keep products after a failed update", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table}, nil)
+		_, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+
+		fx.srv.EXPECT().UpdateProduct(fx.ctx, table.Product).Return(models.ProductWithStock{}, ErrInvalidName)
+		_, err = fx.UpdateProduct(fx.ctx, table.Product)
+		require.ErrorIs(t, err, ErrInvalidName)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table}, items)
+	})
+
+	t.Run("should calculate all products after InvalidateAll", func(t *testing.T) {
+		fx := newCacheFixture(t)
+
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table}, nil)
+		_, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+
+		fx.InvalidateAll()
+		fx.srv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{table, chair}, nil)
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ProductWithStock{table, chair}, items)
+	})
+}
+
+type cacheFixture struct {
+	*Cache
+
+	ctx context.Context
+	srv *mockProductsSrv.MockService
+}
+
+func newCacheFixture(t *testing.T) *cacheFixture {
+	srv := mockProductsSrv.NewMockService(gomock.NewController(t))
+	return &cacheFixture{
+		Cache: NewCache(srv),
+		ctx:   context.Background(),
+		srv:   srv,
+	}
+}
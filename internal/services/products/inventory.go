@@ -0,0 +1,259 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/products"
+	"warehouse/internal/uom"
+)
+
+// inventory is a snapshot of article stock with product BOMs normalized to article units
+type inventory struct {
+	products []models.Product
+	articles map[int32]models.Article
+	// boms holds BOM lines normalized to article units
+	boms map[int32][]bomLine
+	conv *uom.Converter
+}
+
+func (srv *impl) loadInventory(ctx context.Context) (*inventory, error) {
+	prods, err := srv.productsRepo.GetProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	arts, err := srv.articlesRepo.GetArticles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles: %w", err)
+	}
+
+	conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+	if err != nil {
+		return nil, err
+	}
+	return newInventory(prods, arts, conv), nil
+}
+
+// newInventory normalizes the stored BOMs, units are validated when a BOM is written so that a line which
+// can no longer be converted, e.g. after the units changed, only keeps its product from being built
+func newInventory(prods []models.Product, arts []models.Article, conv *uom.Converter) *inventory {
+	inv := &inventory{
+		products: prods,
+		articles: make(map[int32]models.Article, len(arts)),
+		boms:     make(map[int32][]bomLine, len(prods)),
+		conv:     conv,
+	}
+	for _, article := range arts {
+		// stock reserved for orders is not available to build products
+		article.Stock = article.Available()
+		inv.articles[article.ID] = article
+	}
+	for _, prod := range prods {
+		lines := make([]bomLine, 0, len(prod.Articles))
+		for _, art := range prod.Articles {
+			line, err := inv.bomLine(art)
+			if err != nil {
+				line = bomLine{ProductArticle: art, missing: true}
+			}
+			lines = appendLine(lines, line)
+		}
+		inv.boms[prod.ID] = lines
+	}
+	return inv
+}
+
+// bomLine is a product article, the quantity is kept as is if the article is unknown
+type bomLine struct {
+	models.ProductArticle
+	missing bool
+}
+
+// bomLine normalizes the product article to the article unit
+func (inv *inventory) bomLine(art models.ProductArticle) (bomLine, error) {
+	article, ok := inv.articles[art.ID]
+	if !ok {
+		return bomLine{ProductArticle: art, missing: true}, nil
+	}
+	required, err := inv.conv.Normalize(article, art)
+	if err != nil {
+		return bomLine{}, err
+	}
+	return bomLine{ProductArticle: required}, nil
+}
+
+// setBOM normalizes the product articles to article units, it fails if any unit is not compatible with the article
+func (inv *inventory) setBOM(productID int32, items []models.ProductArticle) error {
+	lines := make([]bomLine, 0, len(items))
+	for _, art := range items {
+		line, err := inv.bomLine(art)
+		if err != nil {
+			return fmt.Errorf("failed to normalize product %d: %w", productID, err)
+		}
+		lines = appendLine(lines, line)
+	}
+	inv.boms[productID] = lines
+	return nil
+}
+
+// appendLine adds the line to the BOM, the quantity of an article listed more than once is summed
+func appendLine(lines []bomLine, line bomLine) []bomLine {
+	if line.missing {
+		return append(lines, line)
+	}
+	for i := range lines {
+		if !lines[i].missing && lines[i].ID == line.ID {
+			lines[i].Quantity += line.Quantity
+			return lines
+		}
+	}
+	return append(lines, line)
+}
+
+// bom returns the normalized BOM, false if the product is made of unknown articles
+func (inv *inventory) bom(productID int32) ([]models.ProductArticle, bool) {
+	lines := inv.boms[productID]
+	bom := make([]models.ProductArticle, 0, len(lines))
+	for _, line := range lines {
+		if line.missing {
+			return nil, false
+		}
+		bom = append(bom, line.ProductArticle)
+	}
+	return bom, true
+}
+
+// buildable calculates how many units of the product can be built from the article stock
+func (inv *inventory) buildable(productID int32) int32 {
+	bom, ok := inv.bom(productID)
+	if !ok {
+		return 0
+	}
+
+	minStock := int32(0)
+	for i, art := range bom {
+		stock := inv.articles[art.ID].Stock / art.Quantity
+		if i == 0 {
+			minStock = stock
+		}
+		if stock < minStock {
+			minStock = stock
+		}
+	}
+	return minStock
+}
+
+// availability calculates the product stock, assembled units plus the units buildable from articles,
+// with the per article breakdown. The bottleneck is named only if none of the articles is missing.
+func (inv *inventory) availability(prod models.Product) models.ProductWithStock {
+	item := models.ProductWithStock{
+		Product:   prod,
+		Buildable: inv.buildable(prod.ID),
+	}
+	item.Stock = prod.Assembled + item.Buildable
+
+	bottleneck := -1
+	for _, line := range inv.boms[prod.ID] {
+		component := models.ComponentAvailability{
+			ArticleID: line.ID,
+			Required:  line.Quantity,
+			Missing:   line.missing,
+		}
+		if line.missing {
+			item.MissingArticleIDs = append(item.MissingArticleIDs, line.ID)
+		} else {
+			component.OnHand = inv.articles[line.ID].Stock
+			component.Buildable = component.OnHand / line.Quantity
+			if bottleneck < 0 || component.Buildable < item.Components[bottleneck].Buildable {
+				bottleneck = len(item.Components)
+			}
+		}
+		item.Components = append(item.Components, component)
+	}
+	if bottleneck >= 0 && len(item.MissingArticleIDs) == 0 {
+		item.Components[bottleneck].Bottleneck = true
+		item.BottleneckArticleID = item.Components[bottleneck].ArticleID
+	}
+	return item
+}
+
+// sortedArticles returns articles ordered by ID
+func (inv *inventory) sortedArticles() []models.Article {
+	arts := make([]models.Article, 0, len(inv.articles))
+	for _, article := range inv.articles {
+		arts = append(arts, article)
+	}
+	sort.Slice(arts, func(i, j int) bool {
+		return arts[i].ID < arts[j].ID
+	})
+	return arts
+}
+
+func (inv *inventory) product(id int32) (*models.Product, error) {
+	for i := range inv.products {
+		if inv.products[i].ID == id {
+			return &inv.products[i], nil
+		}
+	}
+	return nil, products.ErrNotFound
+}
+
+// sell takes assembled units of the product first and removes articles for the rest, unknown articles
+// are skipped. Like RemoveProduct it fails with articles.ErrInsufficientStock if the stock of an article
+// is not enough.
+func (inv *inventory) sell(productID, quantity int32) error {
+	prod, err := inv.product(productID)
+	if err != nil {
+		return err
+	}
+	taken := min(prod.Assembled, quantity)
+	quantity -= taken
+
+	for _, line := range inv.boms[productID] {
+		if line.missing {
+			continue
+		}
+		if int64(inv.articles[line.ID].Stock) < int64(line.Quantity)*int64(quantity) {
+			return fmt.Errorf("%w: article %d", articles.ErrInsufficientStock, line.ID)
+		}
+	}
+
+	prod.Assembled -= taken
+	for _, line := range inv.boms[productID] {
+		if line.missing {
+			continue
+		}
+		article := inv.articles[line.ID]
+		article.Stock -= line.Quantity * quantity
+		inv.articles[line.ID] = article
+	}
+	return nil
+}
+
+// receive adds stock of the article
+func (inv *inventory) receive(item models.ProductArticle) error {
+	article, ok := inv.articles[item.ID]
+	if !ok {
+		return articles.ErrNotFound
+	}
+	received, err := inv.conv.Normalize(article, item)
+	if err != nil {
+		return err
+	}
+	article.Stock += received.Quantity
+	inv.articles[item.ID] = article
+	return nil
+}
+
+// changeBOM replaces the articles the product is made of
+func (inv *inventory) changeBOM(productID int32, items []models.ProductArticle) error {
+	prod, err := inv.product(productID)
+	if err != nil {
+		return err
+	}
+	prod.Articles = items
+	return inv.setBOM(productID, items)
+}
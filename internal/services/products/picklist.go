@@ -0,0 +1,129 @@
+package products
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"warehouse/internal/models"
+)
+
+var pickListCSVHeader = []string{
+	"article_id", "name", "quantity", "unit", "on_hand", "shortage", "product_ids", "missing",
+}
+
+// pickList explodes the product BOMs and aggregates the article quantities, items are ordered by article.
+// Missing lines are aggregated apart from the article per BOM unit, their quantities cannot be converted.
+func (inv *inventory) pickList(lines []models.ProductSale) (models.PickList, error) {
+	list := models.PickList{Lines: lines}
+	for _, line := range lines {
+		_, err := inv.product(line.ProductID)
+		if err != nil {
+			return models.PickList{}, fmt.Errorf("failed to get product %d: %w", line.ProductID, err)
+		}
+
+		for _, bomLine := range inv.boms[line.ProductID] {
+			i := slices.IndexFunc(list.Items, func(item models.PickListItem) bool {
+				if item.ArticleID != bomLine.ID || item.Missing != bomLine.missing {
+					return false
+				}
+				return !item.Missing || item.Unit == bomLine.Unit
+			})
+			if i < 0 {
+				article := inv.articles[bomLine.ID]
+				item := models.PickListItem{
+					ArticleID: bomLine.ID,
+					Name:      article.Name,
+					Unit:      article.Unit,
+					OnHand:    article.Stock,
+					Missing:   bomLine.missing,
+				}
+				if bomLine.missing {
+					item.Unit = bomLine.Unit
+					item.OnHand = 0
+				}
+				list.Items = append(list.Items, item)
+				i = len(list.Items) - 1
+			}
+
+			item := &list.Items[i]
+			item.Quantity += bomLine.Quantity * line.Quantity
+			if !slices.Contains(item.ProductIDs, line.ProductID) {
+				item.ProductIDs = append(item.ProductIDs, line.ProductID)
+			}
+		}
+	}
+
+	for i := range list.Items {
+		item := &list.Items[i]
+		item.Shortage = max(item.Quantity-max(item.OnHand, 0), 0)
+	}
+	slices.SortStableFunc(list.Items, func(a, b models.PickListItem) int {
+		return cmp.Compare(a.ArticleID, b.ArticleID)
+	})
+	return list, nil
+}
+
+// WritePickListText renders the pick list as a printable table
+func WritePickListText(w io.Writer, list models.PickList) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Pick list")
+	for _, line := range list.Lines {
+		fmt.Fprintf(tw, "Product %d\tx %d\n", line.ProductID, line.Quantity)
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "ARTICLE\tNAME\tQUANTITY\tUNIT\tON HAND\tSHORTAGE\tPRODUCTS")
+	for _, item := range list.Items {
+		name := item.Name
+		switch {
+		case item.Missing && name == "":
+			name = "(unknown)"
+		case item.Missing:
+			name += " (incompatible unit)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%d\t%d\t%s\n",
+			item.ArticleID, name, item.Quantity, item.Unit, item.OnHand, item.Shortage, joinIDs(item.ProductIDs, ", "),
+		)
+	}
+	return tw.Flush()
+}
+
+// WritePickListCSV renders the pick list items as CSV, one row per article
+func WritePickListCSV(w io.Writer, list models.PickList) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write(pickListCSVHeader)
+	if err != nil {
+		return err
+	}
+	for _, item := range list.Items {
+		err := cw.Write([]string{
+			strconv.Itoa(int(item.ArticleID)),
+			item.Name,
+			strconv.Itoa(int(item.Quantity)),
+			item.Unit,
+			strconv.Itoa(int(item.OnHand)),
+			strconv.Itoa(int(item.Shortage)),
+			joinIDs(item.ProductIDs, " "),
+			strconv.FormatBool(item.Missing),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func joinIDs(ids []int32, sep string) string {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.Itoa(int(id)))
+	}
+	return strings.Join(items, sep)
+}
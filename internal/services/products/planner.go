@@ -0,0 +1,153 @@
+package products
+
+import (
+	"math"
+	"sort"
+
+	"warehouse/internal/models"
+)
+
+// maxPlanNodes limits the branch and bound search
+const maxPlanNodes = 1_000_000
+
+type planItem struct {
+	productID int32
+	bom       []models.ProductArticle
+	value     int64
+	limit     int32
+}
+
+// planner searches for the integer product mix maximizing the total value
+// without exceeding the article stock
+type planner struct {
+	items []planItem
+	stock map[int32]int32
+
+	current   []int32
+	value     int64
+	best      []int32
+	bestValue int64
+	nodes     int
+	exhausted bool
+}
+
+func newPlanner(items []planItem, stock map[int32]int32) *planner {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].value > items[j].value
+	})
+	return &planner{
+		items:   items,
+		stock:   stock,
+		current: make([]int32, len(items)),
+		best:    make([]int32, len(items)),
+	}
+}
+
+// solve returns quantities per product and whether the search completed
+func (p *planner) solve() (map[int32]int32, bool) {
+	p.search(0)
+
+	result := make(map[int32]int32, len(p.items))
+	for i, item := range p.items {
+		if p.best[i] > 0 {
+			result[item.productID] = p.best[i]
+		}
+	}
+	return result, !p.exhausted
+}
+
+func (p *planner) search(i int) {
+	p.nodes++
+	if p.nodes > maxPlanNodes {
+		p.exhausted = true
+		return
+	}
+	if p.value > p.bestValue {
+		p.bestValue = p.value
+		copy(p.best, p.current)
+	}
+	if i == len(p.items) {
+		return
+	}
+
+	// every remaining product built to its own maximum bounds the achievable value
+	bound := p.value
+	for _, item := range p.items[i:] {
+		bound += item.value * int64(p.maxUnits(item))
+	}
+	if bound <= p.bestValue {
+		return
+	}
+
+	item := p.items[i]
+	for x := p.maxUnits(item); x >= 0; x-- {
+		p.apply(item, -x)
+		p.current[i] = x
+		p.value += item.value * int64(x)
+
+		p.search(i + 1)
+
+		p.value -= item.value * int64(x)
+		p.current[i] = 0
+		p.apply(item, x)
+		if p.exhausted {
+			return
+		}
+	}
+}
+
+func (p *planner) maxUnits(item planItem) int32 {
+	units := item.limit
+	for _, art := range item.bom {
+		if n := p.stock[art.ID] / art.Quantity; n < units {
+			units = n
+		}
+	}
+	return max(units, 0)
+}
+
+func (p *planner) apply(item planItem, units int32) {
+	for _, art := range item.bom {
+		p.stock[art.ID] += art.Quantity * units
+	}
+}
+
+// planItems prepares products which can contribute to the objective
+func (inv *inventory) planItems(objective models.MixObjective, caps map[int32]int32) ([]planItem, string, error) {
+	var (
+		items    []planItem
+		currency string
+	)
+	for _, prod := range inv.products {
+		bom, ok := inv.bom(prod.ID)
+		if !ok || len(bom) == 0 {
+			continue
+		}
+
+		value := int64(1)
+		if objective == models.MixObjectiveRevenue {
+			value = prod.Price.Amount
+			if value <= 0 {
+				continue
+			}
+			if currency == "" {
+				currency = prod.Price.Currency
+			}
+			if prod.Price.Currency != currency {
+				return nil, "", models.ErrCurrencyMismatch
+			}
+		}
+
+		limit := int32(math.MaxInt32)
+		if c, ok := caps[prod.ID]; ok {
+			limit = c
+		}
+		items = append(items, planItem{
+			productID: prod.ID,
+			bom:       bom,
+			value:     value,
+			limit:     limit,
+		})
+	}
+	return items, currency, nil
+}
@@ -0,0 +1,75 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"warehouse/internal/models"
+)
+
+func TestPlanner_Solve(t *testing.T) {
+	t.Run("should prefer the optimal mix over the greedy one", func(t *testing.T) {
+		items := []planItem{
+			{
+				productID: 1,
+				bom:       []models.ProductArticle{{ID: 1, Quantity: 6}},
+				value:     7,
+				limit:     100,
+			},
+			{
+				productID: 2,
+				bom:       []models.ProductArticle{{ID: 1, Quantity: 5}},
+				value:     5,
+				limit:     100,
+			},
+		}
+		stock := map[int32]int32{1: 10}
+
+		quantities, optimal := newPlanner(items, stock).solve()
+
+		assert.True(t, optimal)
+		assert.Equal(t, map[int32]int32{2: 2}, quantities)
+		assert.Equal(t, map[int32]int32{1: 10}, stock)
+	})
+
+	t.Run("should respect limits", func(t *testing.T) {
+		items := []planItem{
+			{
+				productID: 1,
+				bom:       []models.ProductArticle{{ID: 1, Quantity: 1}},
+				value:     10,
+				limit:     2,
+			},
+			{
+				productID: 2,
+				bom:       []models.ProductArticle{{ID: 1, Quantity: 1}},
+				value:     1,
+				limit:     100,
+			},
+		}
+		stock := map[int32]int32{1: 5}
+
+		quantities, optimal := newPlanner(items, stock).solve()
+
+		assert.True(t, optimal)
+		assert.Equal(t, map[int32]int32{1: 2, 2: 3}, quantities)
+	})
+
+	t.Run("should return empty mix if nothing can be built", func(t *testing.T) {
+		items := []planItem{
+			{
+				productID: 1,
+				bom:       []models.ProductArticle{{ID: 1, Quantity: 2}},
+				value:     10,
+				limit:     100,
+			},
+		}
+		stock := map[int32]int32{1: 1}
+
+		quantities, optimal := newPlanner(items, stock).solve()
+
+		assert.True(t, optimal)
+		assert.Empty(t, quantities)
+	})
+}
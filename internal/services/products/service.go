@@ -2,90 +2,837 @@ package products
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
+	"warehouse/internal/db"
+	"warehouse/internal/logging"
 	"warehouse/internal/models"
 	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/assemblies"
+	"warehouse/internal/repositories/backorders"
 	"warehouse/internal/repositories/products"
+	"warehouse/internal/repositories/sales"
+	"warehouse/internal/repositories/serials"
+	"warehouse/internal/repositories/units"
+	"warehouse/internal/uom"
+)
+
+var (
+	ErrSerialMismatch    = errors.New("serials do not match product articles")
+	ErrInvalidDemandCap  = errors.New("demand cap must not be negative")
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrNotAssemblable    = errors.New("product made of serialized or unknown articles cannot be pre-assembled")
+	ErrAssemblyCompleted = errors.New("assembly order is already completed")
+	ErrNotDisassemblable = errors.New("product made of serialized or unknown articles cannot be disassembled")
+	ErrEmptyPickList     = errors.New("pick list requires at least one product line")
+	ErrInvalidScrap      = errors.New("scrap must be a non-negative quantity of a product article not exceeding the disassembled quantity")
+	ErrInvalidName       = errors.New("name is required")
+	ErrInvalidPrice      = errors.New("price must not be negative")
 )
 
 type Service interface {
 	GetProductsWithStock(ctx context.Context) ([]models.ProductWithStock, error)
-	RemoveProduct(ctx context.Context, id, quantity int32) error
+	GetProductsWithStockByID(ctx context.Context, ids []int32) ([]models.ProductWithStock, error)
+	UpdateProduct(ctx context.Context, item models.Product) (models.ProductWithStock, error)
+	RemoveProduct(ctx context.Context, id, quantity int32, serials []string, allowBackorder bool) (models.Sale, error)
+	PlanProductionMix(ctx context.Context, objective models.MixObjective, caps map[int32]int32) (models.ProductionMix, error)
+	SimulateAvailability(ctx context.Context, sim models.Simulation) (models.SimulationResult, error)
+	CreateAssemblyOrder(ctx context.Context, productID, quantity int32) (models.AssemblyOrder, error)
+	CompleteAssemblyOrder(ctx context.Context, id int32) (models.AssemblyOrder, error)
+	DisassembleProduct(ctx context.Context, productID, quantity int32, fromAssembled bool, scrap []models.ProductArticle) (models.Disassembly, error)
+	GetBackorders(ctx context.Context, openOnly bool) ([]models.Backorder, error)
+	FulfilBackorders(ctx context.Context, articleIDs []int32) ([]models.Backorder, error)
+	GeneratePickList(ctx context.Context, lines []models.ProductSale) (models.PickList, error)
 }
 
 type impl struct {
-	articlesRepo articles.Repository
-	productsRepo products.Repository
+	tx             db.Transactor
+	articlesRepo   articles.Repository
+	productsRepo   products.Repository
+	salesRepo      sales.Repository
+	serialsRepo    serials.Repository
+	unitsRepo      units.Repository
+	assembliesRepo assemblies.Repository
+	backordersRepo backorders.Repository
 }
 
-func NewService(aRepo articles.Repository, pRepo products.Repository) Service {
+func NewService(
+	tx db.Transactor,
+	aRepo articles.Repository,
+	pRepo products.Repository,
+	sRepo sales.Repository,
+	serRepo serials.Repository,
+	uRepo units.Repository,
+	asmRepo assemblies.Repository,
+	boRepo backorders.Repository,
+) Service {
 	return &impl{
-		articlesRepo: aRepo,
-		productsRepo: pRepo,
+		tx:             tx,
+		articlesRepo:   aRepo,
+		productsRepo:   pRepo,
+		salesRepo:      sRepo,
+		serialsRepo:    serRepo,
+		unitsRepo:      uRepo,
+		assembliesRepo: asmRepo,
+		backordersRepo: boRepo,
 	}
 }
 
-// GetProductsWithStock list the products and calculates stock quantity
+// GetProductsWithStock list the products and calculates stock quantity with the per article breakdown.
+// Article quantities are normalized to the article unit.
 func (srv *impl) GetProductsWithStock(ctx context.Context) ([]models.ProductWithStock, error) {
-	prods, err := srv.productsRepo.GetProducts(ctx)
+	inv, err := srv.loadInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prodsWithStock := make([]models.ProductWithStock, 0, len(inv.products))
+	for _, prod := range inv.products {
+		prodsWithStock = append(prodsWithStock, inv.availability(prod))
+	}
+	return prodsWithStock, nil
+}
+
+// GetProductsWithStockByID calculates the stock of the given products, unknown products are skipped.
+// Only the products and the articles they are made of are loaded.
+func (srv *impl) GetProductsWithStockByID(ctx context.Context, ids []int32) ([]models.ProductWithStock, error) {
+	prods, err := srv.productsRepo.GetProductsByID(ctx, ids)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
+	var articleIDs []int32
+	for _, prod := range prods {
+		for _, art := range prod.Articles {
+			articleIDs = append(articleIDs, art.ID)
+		}
+	}
+	slices.Sort(articleIDs)
+	articleIDs = slices.Compact(articleIDs)
 
-	arts, err := srv.articlesRepo.GetArticles(ctx)
+	arts, err := srv.articlesRepo.GetArticlesByID(ctx, articleIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get articles: %w", err)
 	}
+	conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+	if err != nil {
+		return nil, err
+	}
+	inv := newInventory(prods, arts, conv)
 
-	inventory := make(map[int32]int32, len(arts))
-	for _, article := range arts {
-		inventory[article.ID] = article.Stock
+	prodsWithStock := make([]models.ProductWithStock, 0, len(inv.products))
+	for _, prod := range inv.products {
+		prodsWithStock = append(prodsWithStock, inv.availability(prod))
 	}
+	return prodsWithStock, nil
+}
 
-	prodsWithStock := make([]models.ProductWithStock, 0, len(prods))
-	for _, prod := range prods {
-		minStock := int32(0)
-		for i, art := range prod.Articles {
-			artStock, ok := inventory[art.ID]
-			if !ok {
-				minStock = 0
-				break
+// UpdateProduct changes the name, price and articles of the product and returns it with the resulting stock.
+// Article units must be compatible with the article, unknown articles are kept as missing.
+// The version must be the one read last, the update fails if the product has changed since.
+func (srv *impl) UpdateProduct(ctx context.Context, item models.Product) (models.ProductWithStock, error) {
+	if item.Name == "" {
+		return models.ProductWithStock{}, ErrInvalidName
+	}
+	if err := models.ValidateCurrency(item.Price.Currency); err != nil {
+		return models.ProductWithStock{}, err
+	}
+	if item.Price.Amount < 0 {
+		return models.ProductWithStock{}, ErrInvalidPrice
+	}
+	for _, art := range item.Articles {
+		if art.Quantity <= 0 {
+			return models.ProductWithStock{}, ErrInvalidQuantity
+		}
+	}
+
+	inv, err := srv.loadInventory(ctx)
+	if err != nil {
+		return models.ProductWithStock{}, err
+	}
+	err = inv.changeBOM(item.ID, item.Articles)
+	if err != nil {
+		return models.ProductWithStock{}, err
+	}
+
+	updated, err := srv.productsRepo.UpdateProduct(ctx, item)
+	if err != nil {
+		return models.ProductWithStock{}, fmt.Errorf("failed to update product: %w", err)
+	}
+	prod, err := inv.product(item.ID)
+	if err != nil {
+		return models.ProductWithStock{}, err
+	}
+	*prod = updated
+	return inv.availability(updated), nil
+}
+
+// PlanProductionMix finds the integer mix of products which can be built together from the current
+// inventory and maximizes either the revenue or the number of units. Caps limit the quantity per product.
+func (srv *impl) PlanProductionMix(ctx context.Context, objective models.MixObjective, caps map[int32]int32) (models.ProductionMix, error) {
+	for _, c := range caps {
+		if c < 0 {
+			return models.ProductionMix{}, ErrInvalidDemandCap
+		}
+	}
+
+	inv, err := srv.loadInventory(ctx)
+	if err != nil {
+		return models.ProductionMix{}, err
+	}
+	items, currency, err := inv.planItems(objective, caps)
+	if err != nil {
+		return models.ProductionMix{}, err
+	}
+
+	stock := make(map[int32]int32, len(inv.articles))
+	for id, article := range inv.articles {
+		stock[id] = article.Stock
+	}
+	quantities, optimal := newPlanner(items, stock).solve()
+
+	mix := models.ProductionMix{
+		Revenue: models.Money{Currency: currency},
+		Optimal: optimal,
+	}
+	sameCurrency := true
+	for _, prod := range inv.products {
+		quantity, ok := quantities[prod.ID]
+		if !ok {
+			continue
+		}
+		revenue := prod.Price.Mul(int64(quantity))
+		mix.Items = append(mix.Items, models.ProductionMixItem{
+			ProductID: prod.ID,
+			Quantity:  quantity,
+			Revenue:   revenue,
+		})
+		mix.Units += quantity
+
+		if mix.Revenue.Currency == "" {
+			mix.Revenue.Currency = revenue.Currency
+		}
+		if total, err := mix.Revenue.Add(revenue); err == nil {
+			mix.Revenue = total
+		} else {
+			sameCurrency = false
+		}
+		bom, _ := inv.bom(prod.ID)
+		for _, art := range bom {
+			stock[art.ID] -= art.Quantity * quantity
+		}
+	}
+	if !sameCurrency {
+		mix.Revenue = models.Money{}
+	}
+
+	for _, article := range inv.sortedArticles() {
+		mix.Leftover = append(mix.Leftover, models.ProductArticle{
+			ID:       article.ID,
+			Quantity: stock[article.ID],
+		})
+	}
+	return mix, nil
+}
+
+// SimulateAvailability applies hypothetical changes to a snapshot of the inventory
+// and calculates the resulting product stock and article balances. Nothing is persisted.
+func (srv *impl) SimulateAvailability(ctx context.Context, sim models.Simulation) (models.SimulationResult, error) {
+	for _, sale := range sim.Sales {
+		if sale.Quantity <= 0 {
+			return models.SimulationResult{}, ErrInvalidQuantity
+		}
+	}
+	for _, receipt := range sim.Receipts {
+		if receipt.Quantity <= 0 {
+			return models.SimulationResult{}, ErrInvalidQuantity
+		}
+	}
+	for _, change := range sim.BOMChanges {
+		for _, art := range change.Articles {
+			if art.Quantity <= 0 {
+				return models.SimulationResult{}, ErrInvalidQuantity
 			}
+		}
+	}
 
-			stock := artStock / art.Quantity
-			if i == 0 {
-				minStock = stock
+	inv, err := srv.loadInventory(ctx)
+	if err != nil {
+		return models.SimulationResult{}, err
+	}
+	before := make(map[int32]int32, len(inv.articles))
+	for id, article := range inv.articles {
+		before[id] = article.Stock
+	}
+
+	for _, change := range sim.BOMChanges {
+		err = inv.changeBOM(change.ID, change.Articles)
+		if err != nil {
+			return models.SimulationResult{}, fmt.Errorf("failed to change product %d: %w", change.ID, err)
+		}
+	}
+	for _, receipt := range sim.Receipts {
+		err = inv.receive(receipt)
+		if err != nil {
+			return models.SimulationResult{}, fmt.Errorf("failed to receive article %d: %w", receipt.ID, err)
+		}
+	}
+	for _, sale := range sim.Sales {
+		err = inv.sell(sale.ProductID, sale.Quantity)
+		if err != nil {
+			return models.SimulationResult{}, fmt.Errorf("failed to sell product %d: %w", sale.ProductID, err)
+		}
+	}
+
+	result := models.SimulationResult{
+		Products: make([]models.ProductWithStock, 0, len(inv.products)),
+		Articles: make([]models.ArticleBalance, 0, len(inv.articles)),
+	}
+	for _, prod := range inv.products {
+		result.Products = append(result.Products, inv.availability(prod))
+	}
+	for _, article := range inv.sortedArticles() {
+		result.Articles = append(result.Articles, models.ArticleBalance{
+			ArticleID: article.ID,
+			Name:      article.Name,
+			Before:    before[article.ID],
+			After:     article.Stock,
+		})
+	}
+	return result, nil
+}
+
+// GeneratePickList aggregates the articles required to build the product lines with the available
+// stock and shortages per article. Quantities are normalized to the article unit.
+func (srv *impl) GeneratePickList(ctx context.Context, lines []models.ProductSale) (models.PickList, error) {
+	if len(lines) == 0 {
+		return models.PickList{}, ErrEmptyPickList
+	}
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return models.PickList{}, ErrInvalidQuantity
+		}
+	}
+
+	inv, err := srv.loadInventory(ctx)
+	if err != nil {
+		return models.PickList{}, err
+	}
+	return inv.pickList(lines)
+}
+
+// RemoveProduct checks available quantity on stock, removes the product from stock and records the sale.
+// Assembled units are sold first, the rest is built from articles.
+// Serialized articles consume the given serials, missing ones are assigned automatically.
+// If allowBackorder is set, the units which cannot be built from the available stock are recorded
+// as a backorder of the sale and fulfilled as stock arrives, otherwise the sale fails with
+// articles.ErrInsufficientStock if the stock of an article would become negative.
+func (srv *impl) RemoveProduct(ctx context.Context, id, quantity int32, serials []string, allowBackorder bool) (models.Sale, error) {
+	if quantity <= 0 {
+		return models.Sale{}, ErrInvalidQuantity
+	}
+	var sale models.Sale
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		product, err := srv.productsRepo.GetProduct(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		sale, err = srv.salesRepo.CreateSale(ctx, models.Sale{
+			ProductID: id,
+			Quantity:  quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create sale: %w", err)
+		}
+
+		toBuild := quantity
+		if product.Assembled > 0 {
+			taken, err := srv.productsRepo.TakeAssembled(ctx, id, quantity)
+			if err != nil {
+				return fmt.Errorf("failed to take assembled units: %w", err)
+			}
+			toBuild -= taken
+		}
+		if allowBackorder && toBuild > 0 && len(product.Articles) == 0 {
+			err = srv.backorder(ctx, &sale, toBuild)
+			if err != nil {
+				return err
+			}
+			toBuild = 0
+		}
+		if len(product.Articles) == 0 || toBuild == 0 {
+			if len(serials) > 0 {
+				return ErrSerialMismatch
+			}
+			return nil
+		}
+
+		conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+		if err != nil {
+			return err
+		}
+		comps, err := srv.components(ctx, conv, product, toBuild)
+		if err != nil {
+			return err
+		}
+		if allowBackorder {
+			built := buildable(comps, toBuild)
+			if built < toBuild {
+				err = srv.backorder(ctx, &sale, toBuild-built)
+				if err != nil {
+					return err
+				}
+				if built == 0 {
+					if len(serials) > 0 {
+						return ErrSerialMismatch
+					}
+					return nil
+				}
+				for i := range comps {
+					comps[i].required.Quantity = comps[i].required.Quantity / toBuild * built
+				}
 			}
-			if stock < minStock {
-				minStock = stock
+		}
+
+		arts := make([]models.ProductArticle, 0, len(comps))
+		var serialized []models.ProductArticle
+		for _, c := range comps {
+			if c.missing {
+				continue
+			}
+			arts = append(arts, c.required)
+			if c.article.Serialized {
+				serialized = append(serialized, c.required)
 			}
 		}
-		prodsWithStock = append(prodsWithStock, models.ProductWithStock{
-			Product: prod,
-			Stock:   minStock,
+		err = srv.articlesRepo.RemoveArticles(ctx, arts)
+		if err != nil {
+			return fmt.Errorf("failed to remove articles: %w", err)
+		}
+		return srv.assignSerials(ctx, sale.ID, serialized, serials)
+	})
+	if err != nil {
+		return models.Sale{}, err
+	}
+	logging.FromContext(ctx).Info("product sold",
+		"sale_id", sale.ID,
+		"product_id", sale.ProductID,
+		"quantity", sale.Quantity,
+		"backordered", sale.Backordered,
+	)
+	return sale, nil
+}
+
+// backorder records quantity units of the sale as backordered
+func (srv *impl) backorder(ctx context.Context, sale *models.Sale, quantity int32) error {
+	_, err := srv.backordersRepo.CreateBackorder(ctx, models.Backorder{
+		SaleID:    sale.ID,
+		ProductID: sale.ProductID,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create backorder: %w", err)
+	}
+	sale.Backordered = quantity
+	return nil
+}
+
+// GetBackorders lists backorders oldest first, openOnly skips the fulfilled ones
+func (srv *impl) GetBackorders(ctx context.Context, openOnly bool) ([]models.Backorder, error) {
+	items, err := srv.backordersRepo.GetBackorders(ctx, openOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backorders: %w", err)
+	}
+	return items, nil
+}
+
+// FulfilBackorders fulfils open backorders from the available stock, oldest backorders first.
+// Assembled units are taken first, the rest is built from articles. Only backorders of products
+// made of the given articles are fulfilled from articles, all of them if articleIDs is nil.
+// It returns the backorders which were fulfilled at least in part.
+func (srv *impl) FulfilBackorders(ctx context.Context, articleIDs []int32) ([]models.Backorder, error) {
+	var fulfilled []models.Backorder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		open, err := srv.backordersRepo.GetOpenBackordersForUpdate(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get backorders: %w", err)
+		}
+		if len(open) == 0 {
+			return nil
+		}
+
+		conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+		if err != nil {
+			return err
+		}
+		for _, bo := range open {
+			product, err := srv.productsRepo.GetProduct(ctx, bo.ProductID)
+			if err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+
+			outstanding := bo.Outstanding()
+			var units int32
+			if product.Assembled > 0 {
+				units, err = srv.productsRepo.TakeAssembled(ctx, product.ID, outstanding)
+				if err != nil {
+					return fmt.Errorf("failed to take assembled units: %w", err)
+				}
+			}
+			if units < outstanding && usesArticles(product, articleIDs) {
+				built, err := srv.buildBackorder(ctx, conv, product, bo.SaleID, outstanding-units)
+				if err != nil {
+					return err
+				}
+				units += built
+			}
+			if units == 0 {
+				continue
+			}
+
+			item, err := srv.backordersRepo.FulfilBackorder(ctx, bo.ID, units)
+			if err != nil {
+				return fmt.Errorf("failed to fulfil backorder %d: %w", bo.ID, err)
+			}
+			fulfilled = append(fulfilled, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulfilled, nil
+}
+
+// buildBackorder removes the articles of up to quantity product units from the available stock
+// and returns the number of units built. Serialized articles are assigned to the sale.
+func (srv *impl) buildBackorder(ctx context.Context, conv *uom.Converter, product models.Product, saleID, quantity int32) (int32, error) {
+	comps, err := srv.components(ctx, conv, product, quantity)
+	if err != nil {
+		return 0, err
+	}
+	built := buildable(comps, quantity)
+	if built == 0 {
+		return 0, nil
+	}
+
+	arts := make([]models.ProductArticle, 0, len(comps))
+	for _, c := range comps {
+		item := c.required
+		item.Quantity = item.Quantity / quantity * built
+		arts = append(arts, item)
+	}
+	err = srv.articlesRepo.RemoveArticles(ctx, arts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove articles: %w", err)
+	}
+	for i, c := range comps {
+		if !c.article.Serialized {
+			continue
+		}
+		_, err = srv.serialsRepo.AssignAvailableSerials(ctx, saleID, c.article.ID, arts[i].Quantity)
+		if err != nil {
+			return 0, fmt.Errorf("failed to assign serials: %w", err)
+		}
+	}
+	return built, nil
+}
+
+// CreateAssemblyOrder consumes the component articles of quantity product units to be assembled.
+// The units are added to the assembled stock when the order is completed. It fails with
+// articles.ErrInsufficientStock if the available stock of an article is not enough, the removal checks it
+// atomically so that concurrent orders cannot consume the same stock.
+func (srv *impl) CreateAssemblyOrder(ctx context.Context, productID, quantity int32) (models.AssemblyOrder, error) {
+	if quantity <= 0 {
+		return models.AssemblyOrder{}, ErrInvalidQuantity
+	}
+
+	var order models.AssemblyOrder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		product, err := srv.productsRepo.GetProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+		if len(product.Articles) == 0 {
+			return ErrNotAssemblable
+		}
+
+		conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+		if err != nil {
+			return err
+		}
+		comps, err := srv.components(ctx, conv, product, quantity)
+		if err != nil {
+			return err
+		}
+		arts := make([]models.ProductArticle, 0, len(comps))
+		for _, c := range comps {
+			if c.missing || c.article.Serialized {
+				return ErrNotAssemblable
+			}
+			arts = append(arts, c.required)
+		}
+		err = srv.articlesRepo.RemoveArticles(ctx, arts)
+		if err != nil {
+			return fmt.Errorf("failed to remove articles: %w", err)
+		}
+
+		order, err = srv.assembliesRepo.CreateAssemblyOrder(ctx, models.AssemblyOrder{
+			ProductID: productID,
+			Quantity:  quantity,
 		})
+		if err != nil {
+			return fmt.Errorf("failed to create assembly order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.AssemblyOrder{}, err
 	}
-	return prodsWithStock, nil
+	return order, nil
 }
 
-// RemoveProduct checks available quantity on stock and removes the product from stock
-// TODO: check available quantity
-func (srv *impl) RemoveProduct(ctx context.Context, id, quantity int32) error {
-	product, err := srv.productsRepo.GetProduct(ctx, id)
+// CompleteAssemblyOrder adds the assembled units to the product stock
+func (srv *impl) CompleteAssemblyOrder(ctx context.Context, id int32) (models.AssemblyOrder, error) {
+	var order models.AssemblyOrder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = srv.assembliesRepo.GetAssemblyOrderForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get assembly order: %w", err)
+		}
+		if order.Status != models.AssemblyOrderOpen {
+			return ErrAssemblyCompleted
+		}
+
+		err = srv.productsRepo.AddAssembled(ctx, order.ProductID, order.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to add assembled units: %w", err)
+		}
+		order, err = srv.assembliesRepo.CompleteAssemblyOrder(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to complete assembly order: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get product: %w", err)
+		return models.AssemblyOrder{}, err
+	}
+	return order, nil
+}
+
+// DisassembleProduct breaks product units down and adds the recovered articles to stock.
+// The units are taken from the assembled stock if fromAssembled is set, otherwise they are returned units.
+// Scrap lists article quantities which could not be recovered, in any unit compatible with the article.
+func (srv *impl) DisassembleProduct(
+	ctx context.Context,
+	productID, quantity int32,
+	fromAssembled bool,
+	scrap []models.ProductArticle,
+) (models.Disassembly, error) {
+	if quantity <= 0 {
+		return models.Disassembly{}, ErrInvalidQuantity
 	}
-	if len(product.Articles) == 0 {
+
+	var disassembly models.Disassembly
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		product, err := srv.productsRepo.GetProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+		if len(product.Articles) == 0 {
+			return ErrNotDisassemblable
+		}
+		if fromAssembled {
+			taken, err := srv.productsRepo.TakeAssembled(ctx, productID, quantity)
+			if err != nil {
+				return fmt.Errorf("failed to take assembled units: %w", err)
+			}
+			if taken < quantity {
+				return fmt.Errorf("%w: %d assembled units", ErrInsufficientStock, taken)
+			}
+		}
+
+		conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+		if err != nil {
+			return err
+		}
+		comps, err := srv.components(ctx, conv, product, quantity)
+		if err != nil {
+			return err
+		}
+		disassembly = models.Disassembly{
+			ProductID:     productID,
+			Quantity:      quantity,
+			FromAssembled: fromAssembled,
+		}
+		expected := make(map[int32]int32, len(comps))
+		for _, c := range comps {
+			if c.missing || c.article.Serialized {
+				return ErrNotDisassemblable
+			}
+			expected[c.article.ID] += c.required.Quantity
+		}
+
+		scrapped := make(map[int32]int32, len(scrap))
+		for _, item := range scrap {
+			c := slices.IndexFunc(comps, func(c component) bool {
+				return c.article.ID == item.ID
+			})
+			if c < 0 || item.Quantity < 0 {
+				return ErrInvalidScrap
+			}
+			normalized, err := conv.Normalize(comps[c].article, item)
+			if err != nil {
+				return fmt.Errorf("failed to normalize scrap of article %d: %w", item.ID, err)
+			}
+			scrapped[item.ID] += normalized.Quantity
+			if scrapped[item.ID] > expected[item.ID] {
+				return ErrInvalidScrap
+			}
+		}
+
+		var recovered []models.ProductArticle
+		for _, c := range comps {
+			id := c.article.ID
+			if slices.ContainsFunc(disassembly.Components, func(d models.DisassembledComponent) bool {
+				return d.ArticleID == id
+			}) {
+				continue
+			}
+			component := models.DisassembledComponent{
+				ArticleID: id,
+				Expected:  expected[id],
+				Recovered: expected[id] - scrapped[id],
+				Scrapped:  scrapped[id],
+			}
+			disassembly.Components = append(disassembly.Components, component)
+			if component.Recovered > 0 {
+				recovered = append(recovered, models.ProductArticle{ID: id, Quantity: component.Recovered})
+			}
+		}
+		if len(recovered) > 0 {
+			err = srv.articlesRepo.AddArticles(ctx, recovered)
+			if err != nil {
+				return fmt.Errorf("failed to add articles: %w", err)
+			}
+		}
+
+		disassembly, err = srv.assembliesRepo.CreateDisassembly(ctx, disassembly)
+		if err != nil {
+			return fmt.Errorf("failed to create disassembly: %w", err)
+		}
 		return nil
+	})
+	if err != nil {
+		return models.Disassembly{}, err
 	}
+	return disassembly, nil
+}
 
-	arts := make([]models.ProductArticle, 0, len(product.Articles))
+// component is a product article normalized to the article unit
+type component struct {
+	article  models.Article
+	required models.ProductArticle
+	// missing articles are unknown to the warehouse, required is kept as is
+	missing bool
+}
+
+// components loads the articles of the product and normalizes the quantities for quantity product units
+func (srv *impl) components(ctx context.Context, conv *uom.Converter, product models.Product, quantity int32) ([]component, error) {
+	comps := make([]component, 0, len(product.Articles))
 	for _, a := range product.Articles {
-		arts = append(arts, models.ProductArticle{
-			ID:       a.ID,
-			Quantity: a.Quantity * quantity,
+		article, err := srv.articlesRepo.GetArticle(ctx, a.ID)
+		if err != nil {
+			if errors.Is(err, articles.ErrNotFound) {
+				comps = append(comps, component{required: a, missing: true})
+				continue
+			}
+			return nil, fmt.Errorf("failed to get article: %w", err)
+		}
+		item, err := conv.Normalize(article, a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize product %d: %w", product.ID, err)
+		}
+		item.Quantity *= quantity
+		// an article listed more than once is required in the sum of its quantities
+		i := slices.IndexFunc(comps, func(c component) bool {
+			return !c.missing && c.article.ID == article.ID
 		})
+		if i >= 0 {
+			comps[i].required.Quantity += item.Quantity
+			continue
+		}
+		comps = append(comps, component{article: article, required: item})
+	}
+	return comps, nil
+}
+
+// buildable returns how many of quantity product units can be built from the available stock
+// of the components, none if there are no components or any article is unknown
+func buildable(comps []component, quantity int32) int32 {
+	if len(comps) == 0 {
+		return 0
+	}
+	built := quantity
+	for _, c := range comps {
+		if c.missing {
+			return 0
+		}
+		perUnit := c.required.Quantity / quantity
+		if perUnit > 0 {
+			built = min(built, max(c.article.Available(), 0)/perUnit)
+		}
+	}
+	return built
+}
+
+// usesArticles checks if the product is made of any of the articles, nil matches any product
+func usesArticles(product models.Product, articleIDs []int32) bool {
+	if articleIDs == nil {
+		return true
+	}
+	return slices.ContainsFunc(product.Articles, func(a models.ProductArticle) bool {
+		return slices.Contains(articleIDs, a.ID)
+	})
+}
+
+// assignSerials consumes serials of serialized articles for the sale
+func (srv *impl) assignSerials(ctx context.Context, saleID int32, arts []models.ProductArticle, serials []string) error {
+	requested := make(map[int32][]string)
+	for _, s := range serials {
+		item, err := srv.serialsRepo.GetSerial(ctx, s)
+		if err != nil {
+			return fmt.Errorf("failed to get serial %q: %w", s, err)
+		}
+		requested[item.ArticleID] = append(requested[item.ArticleID], s)
+	}
+
+	for _, art := range arts {
+		given := requested[art.ID]
+		delete(requested, art.ID)
+		if int32(len(given)) > art.Quantity {
+			return ErrSerialMismatch
+		}
+		if len(given) > 0 {
+			err := srv.serialsRepo.AssignSerials(ctx, saleID, art.ID, given)
+			if err != nil {
+				return fmt.Errorf("failed to assign serials: %w", err)
+			}
+		}
+		if rest := art.Quantity - int32(len(given)); rest > 0 {
+			_, err := srv.serialsRepo.AssignAvailableSerials(ctx, saleID, art.ID, rest)
+			if err != nil {
+				return fmt.Errorf("failed to assign serials: %w", err)
+			}
+		}
+	}
+
+	if len(requested) > 0 {
+		return ErrSerialMismatch
 	}
-	return srv.articlesRepo.RemoveArticles(ctx, arts)
+	return nil
 }
@@ -1,17 +1,28 @@
 package products
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"warehouse/internal/db/mock"
 	"warehouse/internal/models"
+	articlesrepo "warehouse/internal/repositories/articles"
 	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/assemblies/mock"
+	"warehouse/internal/repositories/backorders/mock"
+	productsrepo "warehouse/internal/repositories/products"
 	"warehouse/internal/repositories/products/mock"
+	"warehouse/internal/repositories/sales/mock"
+	"warehouse/internal/repositories/serials/mock"
+	"warehouse/internal/repositories/units/mock"
 	"warehouse/internal/testhelpers"
+	"warehouse/internal/uom"
 )
 
 func TestImpl_GetProductsWithStock(t *testing.T) {
@@ -20,9 +31,12 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 
 		products := []models.Product{
 			{
-				ID:    testhelpers.RandomInt32(),
-				Name:  testhelpers.RandomString(),
-				Price: float32(testhelpers.RandomInt32()),
+				ID:   testhelpers.RandomInt32(),
+				Name: testhelpers.RandomString(),
+				Price: models.Money{
+					Amount:   int64(testhelpers.RandomInt()),
+					Currency: "EUR",
+				},
 				Articles: []models.ProductArticle{
 					{
 						ID:       1,
@@ -39,9 +53,12 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 				},
 			},
 			{
-				ID:    testhelpers.RandomInt32(),
-				Name:  testhelpers.RandomString(),
-				Price: float32(testhelpers.RandomInt32()),
+				ID:   testhelpers.RandomInt32(),
+				Name: testhelpers.RandomString(),
+				Price: models.Money{
+					Amount:   int64(testhelpers.RandomInt()),
+					Currency: "EUR",
+				},
 				Articles: []models.ProductArticle{
 					{
 						ID:       1,
@@ -79,18 +96,33 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 			},
 		}
 		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
 
 		items, err := fx.GetProductsWithStock(fx.ctx)
 
 		require.NoError(t, err)
 		expected := []models.ProductWithStock{
 			{
-				Product: products[0],
-				Stock:   2,
+				Product:   products[0],
+				Stock:     2,
+				Buildable: 2,
+				Components: []models.ComponentAvailability{
+					{ArticleID: 1, Required: 4, OnHand: 12, Buildable: 3},
+					{ArticleID: 2, Required: 8, OnHand: 17, Buildable: 2, Bottleneck: true},
+					{ArticleID: 3, Required: 1, OnHand: 2, Buildable: 2},
+				},
+				BottleneckArticleID: 2,
 			},
 			{
-				Product: products[1],
-				Stock:   1,
+				Product:   products[1],
+				Stock:     1,
+				Buildable: 1,
+				Components: []models.ComponentAvailability{
+					{ArticleID: 1, Required: 4, OnHand: 12, Buildable: 3},
+					{ArticleID: 2, Required: 8, OnHand: 17, Buildable: 2},
+					{ArticleID: 4, Required: 1, OnHand: 1, Buildable: 1, Bottleneck: true},
+				},
+				BottleneckArticleID: 4,
 			},
 		}
 		assert.Equal(t, expected, items)
@@ -101,9 +133,12 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 
 		products := []models.Product{
 			{
-				ID:    testhelpers.RandomInt32(),
-				Name:  testhelpers.RandomString(),
-				Price: float32(testhelpers.RandomInt32()),
+				ID:   testhelpers.RandomInt32(),
+				Name: testhelpers.RandomString(),
+				Price: models.Money{
+					Amount:   int64(testhelpers.RandomInt()),
+					Currency: "EUR",
+				},
 				Articles: []models.ProductArticle{
 					{
 						ID:       2,
@@ -129,14 +164,21 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 			},
 		}
 		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
 
 		items, err := fx.GetProductsWithStock(fx.ctx)
 
 		require.NoError(t, err)
 		expected := []models.ProductWithStock{
 			{
-				Product: products[0],
-				Stock:   0,
+				Product:   products[0],
+				Stock:     0,
+				Buildable: 0,
+				Components: []models.ComponentAvailability{
+					{ArticleID: 2, Required: 2, OnHand: 1, Buildable: 0, Bottleneck: true},
+					{ArticleID: 1, Required: 4, OnHand: 12, Buildable: 3},
+				},
+				BottleneckArticleID: 2,
 			},
 		}
 		assert.Equal(t, expected, items)
@@ -147,9 +189,12 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 
 		products := []models.Product{
 			{
-				ID:    testhelpers.RandomInt32(),
-				Name:  testhelpers.RandomString(),
-				Price: float32(testhelpers.RandomInt32()),
+				ID:   testhelpers.RandomInt32(),
+				Name: testhelpers.RandomString(),
+				Price: models.Money{
+					Amount:   int64(testhelpers.RandomInt()),
+					Currency: "EUR",
+				},
 				Articles: []models.ProductArticle{
 					{
 						ID:       1,
@@ -171,18 +216,485 @@ func TestImpl_GetProductsWithStock(t *testing.T) {
 			},
 		}
 		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+
+		require.NoError(t, err)
+		expected := []models.ProductWithStock{
+			{
+				Product:   products[0],
+				Stock:     0,
+				Buildable: 0,
+				Components: []models.ComponentAvailability{
+					{ArticleID: 1, Required: 4, OnHand: 12, Buildable: 3},
+					{ArticleID: 3, Required: 1, Missing: true},
+				},
+				MissingArticleIDs: []int32{3},
+			},
+		}
+		assert.Equal(t, expected, items)
+	})
+}
+
+func TestImpl_GetProductsWithStock_Assembled(t *testing.T) {
+	t.Run("should add assembled units to buildable units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{
+				ID:        testhelpers.RandomInt32(),
+				Articles:  []models.ProductArticle{{ID: 1, Quantity: 4}},
+				Assembled: 5,
+			},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{{ID: 1, Stock: 9}}, nil)
+		fx.expectUnits()
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.EqualValues(t, 2, items[0].Buildable)
+		assert.EqualValues(t, 7, items[0].Stock)
+	})
+}
+
+func TestImpl_GetProductsWithStockByID(t *testing.T) {
+	t.Run("should load only the products and their articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{ID: 1, Articles: []models.ProductArticle{{ID: 2, Quantity: 2}, {ID: 1, Quantity: 1}}},
+			{ID: 3, Articles: []models.ProductArticle{{ID: 2, Quantity: 3}}},
+		}
+		fx.productsRepo.EXPECT().GetProductsByID(fx.ctx, []int32{1, 3}).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticlesByID(fx.ctx, []int32{1, 2}).
+			Return([]models.Article{{ID: 1, Stock: 1}, {ID: 2, Stock: 6}}, nil)
+		fx.expectUnits()
+
+		items, err := fx.GetProductsWithStockByID(fx.ctx, []int32{1, 3})
+
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.EqualValues(t, 1, items[0].Stock)
+		assert.EqualValues(t, 2, items[1].Stock)
+	})
+}
+
+func TestImpl_GetProductsWithStock_Units(t *testing.T) {
+	t.Run("should normalize article quantities", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{
+				ID:   testhelpers.RandomInt32(),
+				Name: testhelpers.RandomString(),
+				Articles: []models.ProductArticle{
+					{
+						ID:       1,
+						Quantity: 2,
+						Unit:     "m",
+					},
+					{
+						ID:       2,
+						Quantity: 1,
+						Unit:     "box",
+					},
+				},
+			},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+
+		articles := []models.Article{
+			{
+				ID:    1,
+				Stock: 700,
+				Unit:  "cm",
+			},
+			{
+				ID:    2,
+				Stock: 450,
+				Unit:  "pcs",
+			},
+		}
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
 
 		items, err := fx.GetProductsWithStock(fx.ctx)
 
 		require.NoError(t, err)
 		expected := []models.ProductWithStock{
 			{
-				Product: products[0],
-				Stock:   0,
+				Product:   products[0],
+				Stock:     3,
+				Buildable: 3,
+				Components: []models.ComponentAvailability{
+					{ArticleID: 1, Required: 200, OnHand: 700, Buildable: 3, Bottleneck: true},
+					{ArticleID: 2, Required: 100, OnHand: 450, Buildable: 4},
+				},
+				BottleneckArticleID: 1,
 			},
 		}
 		assert.Equal(t, expected, items)
 	})
+
+	t.Run("should sum articles listed more than once", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{
+				ID:       1,
+				Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 1, Quantity: 2}},
+			},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{{ID: 1, Stock: 12, Unit: "pcs"}}, nil)
+		fx.expectUnits()
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.EqualValues(t, 2, items[0].Stock)
+		require.Len(t, items[0].Components, 1)
+		assert.EqualValues(t, 6, items[0].Components[0].Required)
+	})
+
+	t.Run("should not build a product with incompatible unit", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{
+				ID:       1,
+				Articles: []models.ProductArticle{{ID: 1, Quantity: 2, Unit: "m"}},
+			},
+			{
+				ID:       2,
+				Articles: []models.ProductArticle{{ID: 1, Quantity: 2}},
+			},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{{ID: 1, Stock: 10, Unit: "pcs"}}, nil)
+		fx.expectUnits()
+
+		items, err := fx.GetProductsWithStock(fx.ctx)
+
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.EqualValues(t, 0, items[0].Stock)
+		assert.Equal(t, []int32{1}, items[0].MissingArticleIDs)
+		assert.EqualValues(t, 5, items[1].Stock)
+	})
+}
+
+func TestImpl_UpdateProduct(t *testing.T) {
+	product := models.Product{
+		ID:       testhelpers.RandomInt32(),
+		Name:     "table",
+		Price:    models.Money{Amount: 1000, Currency: "EUR"},
+		Articles: []models.ProductArticle{{ID: 1, Quantity: 4}},
+		Version:  2,
+	}
+
+	t.Run("should update product and return its stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return([]models.Product{product}, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{{ID: 1, Stock: 450, Unit: "cm"}}, nil)
+		fx.expectUnits()
+		item := product
+		item.Name = "desk"
+		item.Articles = []models.ProductArticle{{ID: 1, Quantity: 2, Unit: "m"}}
+		updated := item
+		updated.Version++
+		fx.productsRepo.EXPECT().UpdateProduct(fx.ctx, item).Return(updated, nil)
+
+		res, err := fx.UpdateProduct(fx.ctx, item)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ProductWithStock{
+			Product:   updated,
+			Stock:     2,
+			Buildable: 2,
+			Components: []models.ComponentAvailability{
+				{ArticleID: 1, Required: 200, OnHand: 450, Buildable: 2, Bottleneck: true},
+			},
+			BottleneckArticleID: 1,
+		}, res)
+	})
+
+	t.Run("should pass version conflict", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return([]models.Product{product}, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(nil, nil)
+		fx.expectUnits()
+		fx.productsRepo.EXPECT().UpdateProduct(fx.ctx, product).Return(models.Product{}, productsrepo.ErrVersionConflict)
+
+		_, err := fx.UpdateProduct(fx.ctx, product)
+
+		require.ErrorIs(t, err, productsrepo.ErrVersionConflict)
+	})
+
+	t.Run("should reject incompatible unit", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return([]models.Product{product}, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{{ID: 1, Stock: 10, Unit: "pcs"}}, nil)
+		fx.expectUnits()
+		item := product
+		item.Articles = []models.ProductArticle{{ID: 1, Quantity: 2, Unit: "m"}}
+
+		_, err := fx.UpdateProduct(fx.ctx, item)
+
+		require.ErrorIs(t, err, uom.ErrIncompatibleUnit)
+	})
+
+	t.Run("should reject invalid product", func(t *testing.T) {
+		fx := newFixture(t)
+
+		item := product
+		item.Name = ""
+		_, err := fx.UpdateProduct(fx.ctx, item)
+		require.ErrorIs(t, err, ErrInvalidName)
+
+		item = product
+		item.Price.Amount = -1
+		_, err = fx.UpdateProduct(fx.ctx, item)
+		require.ErrorIs(t, err, ErrInvalidPrice)
+
+		for _, currency := range []string{"", "eu", "eur", "EURO"} {
+			item = product
+			item.Price.Currency = currency
+			_, err = fx.UpdateProduct(fx.ctx, item)
+			require.ErrorIs(t, err, models.ErrInvalidMoney, currency)
+		}
+
+		item = product
+		item.Articles = []models.ProductArticle{{ID: 1}}
+		_, err = fx.UpdateProduct(fx.ctx, item)
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestImpl_PlanProductionMix(t *testing.T) {
+	products := []models.Product{
+		{
+			ID:    1,
+			Price: models.Money{Amount: 2000, Currency: "EUR"},
+			Articles: []models.ProductArticle{
+				{ID: 1, Quantity: 4},
+				{ID: 2, Quantity: 8},
+				{ID: 3, Quantity: 1},
+			},
+		},
+		{
+			ID:    2,
+			Price: models.Money{Amount: 5000, Currency: "EUR"},
+			Articles: []models.ProductArticle{
+				{ID: 1, Quantity: 4},
+				{ID: 2, Quantity: 8},
+				{ID: 4, Quantity: 1},
+			},
+		},
+	}
+	articles := []models.Article{
+		{ID: 1, Stock: 12},
+		{ID: 2, Stock: 17},
+		{ID: 3, Stock: 2},
+		{ID: 4, Stock: 1},
+	}
+
+	t.Run("should maximize revenue", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		mix, err := fx.PlanProductionMix(fx.ctx, models.MixObjectiveRevenue, nil)
+
+		require.NoError(t, err)
+		expected := models.ProductionMix{
+			Items: []models.ProductionMixItem{
+				{ProductID: 1, Quantity: 1, Revenue: models.Money{Amount: 2000, Currency: "EUR"}},
+				{ProductID: 2, Quantity: 1, Revenue: models.Money{Amount: 5000, Currency: "EUR"}},
+			},
+			Revenue: models.Money{Amount: 7000, Currency: "EUR"},
+			Units:   2,
+			Leftover: []models.ProductArticle{
+				{ID: 1, Quantity: 4},
+				{ID: 2, Quantity: 1},
+				{ID: 3, Quantity: 1},
+				{ID: 4, Quantity: 0},
+			},
+			Optimal: true,
+		}
+		assert.Equal(t, expected, mix)
+	})
+
+	t.Run("should respect demand caps", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		mix, err := fx.PlanProductionMix(fx.ctx, models.MixObjectiveUnits, map[int32]int32{2: 0})
+
+		require.NoError(t, err)
+		require.Len(t, mix.Items, 1)
+		assert.Equal(t, models.ProductionMixItem{
+			ProductID: 1,
+			Quantity:  2,
+			Revenue:   models.Money{Amount: 4000, Currency: "EUR"},
+		}, mix.Items[0])
+		assert.EqualValues(t, 2, mix.Units)
+	})
+
+	t.Run("should fail on mixed currencies", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{products[0], products[1]}
+		products[1].Price.Currency = "SEK"
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		_, err := fx.PlanProductionMix(fx.ctx, models.MixObjectiveRevenue, nil)
+
+		require.ErrorIs(t, err, models.ErrCurrencyMismatch)
+	})
+}
+
+func TestImpl_SimulateAvailability(t *testing.T) {
+	products := []models.Product{
+		{
+			ID: 1,
+			Articles: []models.ProductArticle{
+				{ID: 1, Quantity: 4},
+				{ID: 2, Quantity: 8},
+				{ID: 3, Quantity: 1},
+			},
+		},
+		{
+			ID: 2,
+			Articles: []models.ProductArticle{
+				{ID: 1, Quantity: 4},
+				{ID: 2, Quantity: 8},
+				{ID: 4, Quantity: 1},
+			},
+		},
+	}
+	articles := []models.Article{
+		{ID: 1, Name: "leg", Stock: 12},
+		{ID: 2, Name: "screw", Stock: 17},
+		{ID: 3, Name: "seat", Stock: 2},
+		{ID: 4, Name: "table top", Stock: 1},
+	}
+
+	t.Run("should apply sales, receipts and BOM changes", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := append([]models.Product(nil), products...)
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		bom := []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 1, Unit: "box"},
+			{ID: 4, Quantity: 1},
+		}
+		result, err := fx.SimulateAvailability(fx.ctx, models.Simulation{
+			Sales: []models.ProductSale{
+				{ProductID: 1, Quantity: 1},
+			},
+			Receipts: []models.ProductArticle{
+				{ID: 2, Quantity: 2, Unit: "box"},
+				{ID: 4, Quantity: 3},
+			},
+			BOMChanges: []models.Product{
+				{ID: 2, Articles: bom},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Products, 2)
+		assert.EqualValues(t, 1, result.Products[0].Stock)
+		assert.EqualValues(t, 2, result.Products[1].Stock)
+		assert.Equal(t, bom, result.Products[1].Articles)
+		expected := []models.ArticleBalance{
+			{ArticleID: 1, Name: "leg", Before: 12, After: 8},
+			{ArticleID: 2, Name: "screw", Before: 17, After: 209},
+			{ArticleID: 3, Name: "seat", Before: 2, After: 1},
+			{ArticleID: 4, Name: "table top", Before: 1, After: 4},
+		}
+		assert.Equal(t, expected, result.Articles)
+	})
+
+	t.Run("should fail on unknown product", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		_, err := fx.SimulateAvailability(fx.ctx, models.Simulation{
+			Sales: []models.ProductSale{
+				{ProductID: -1, Quantity: 1},
+			},
+		})
+
+		require.ErrorIs(t, err, productsrepo.ErrNotFound)
+	})
+
+	t.Run("should reject sales above the stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{ID: 1, Assembled: 1, Articles: products[0].Articles},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(articles, nil)
+		fx.expectUnits()
+
+		_, err := fx.SimulateAvailability(fx.ctx, models.Simulation{
+			Sales: []models.ProductSale{
+				{ProductID: 1, Quantity: 1},
+				{ProductID: 1, Quantity: 3},
+			},
+		})
+
+		require.ErrorIs(t, err, articlesrepo.ErrInsufficientStock)
+		require.ErrorContains(t, err, "article 2")
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.SimulateAvailability(fx.ctx, models.Simulation{
+			Receipts: []models.ProductArticle{
+				{ID: 1, Quantity: 0},
+			},
+		})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+
+	t.Run("should reject non-positive BOM quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.SimulateAvailability(fx.ctx, models.Simulation{
+			BOMChanges: []models.Product{
+				{ID: 1, Articles: []models.ProductArticle{{ID: 1, Quantity: 2}, {ID: 2, Quantity: 0}}},
+			},
+		})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
 }
 
 func TestImpl_RemoveProduct(t *testing.T) {
@@ -200,21 +712,37 @@ func TestImpl_RemoveProduct(t *testing.T) {
 		},
 	}
 
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, -5, nil, false)
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+
+		_, err = fx.RemoveProduct(fx.ctx, productID, 0, nil, false)
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+
 	t.Run("should not remove articles if product does not have any", func(t *testing.T) {
 		fx := newFixture(t)
 
 		product := models.Product{}
+		fx.expectTx()
 		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		sale := fx.expectSale(productID, 1)
 
-		err := fx.RemoveProduct(fx.ctx, productID, 1)
+		item, err := fx.RemoveProduct(fx.ctx, productID, 1, nil, false)
 
 		require.NoError(t, err)
+		assert.Equal(t, sale, item)
 	})
 
 	t.Run("should remove 1 product", func(t *testing.T) {
 		fx := newFixture(t)
 
+		fx.expectTx()
 		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		sale := fx.expectSale(productID, 1)
+		fx.expectUnits()
 		articles := []models.ProductArticle{
 			{
 				ID:       product.Articles[0].ID,
@@ -226,17 +754,23 @@ func TestImpl_RemoveProduct(t *testing.T) {
 			},
 		}
 		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, articles).Return(nil)
+		fx.expectArticle(models.Article{ID: product.Articles[0].ID})
+		fx.expectArticle(models.Article{ID: product.Articles[1].ID})
 
-		err := fx.RemoveProduct(fx.ctx, productID, 1)
+		item, err := fx.RemoveProduct(fx.ctx, productID, 1, nil, false)
 
 		require.NoError(t, err)
+		assert.Equal(t, sale, item)
 	})
 
 	t.Run("should remove N product", func(t *testing.T) {
 		fx := newFixture(t)
 
 		quantity := int32(testhelpers.RandomIntRange(2, 10))
+		fx.expectTx()
 		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, quantity)
+		fx.expectUnits()
 		articles := []models.ProductArticle{
 			{
 				ID:       product.Articles[0].ID,
@@ -248,20 +782,603 @@ func TestImpl_RemoveProduct(t *testing.T) {
 			},
 		}
 		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, articles).Return(nil)
+		fx.expectArticle(models.Article{ID: product.Articles[0].ID})
+		fx.expectArticle(models.Article{ID: product.Articles[1].ID})
 
-		err := fx.RemoveProduct(fx.ctx, productID, quantity)
+		_, err := fx.RemoveProduct(fx.ctx, productID, quantity, nil, false)
 
 		require.NoError(t, err)
 	})
+
+	t.Run("should remove articles in article units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		product := models.Product{
+			Articles: []models.ProductArticle{
+				{
+					ID:       2,
+					Quantity: 1,
+					Unit:     "box",
+				},
+			},
+		}
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, 3)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 2, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{{ID: 2, Quantity: 300}}).Return(nil)
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, 3, nil, false)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should assign given and available serials", func(t *testing.T) {
+		fx := newFixture(t)
+
+		product := models.Product{
+			Articles: []models.ProductArticle{
+				{
+					ID:       1,
+					Quantity: 2,
+				},
+				{
+					ID:       2,
+					Quantity: 4,
+				},
+			},
+		}
+		serial := testhelpers.RandomString()
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		sale := fx.expectSale(productID, 1)
+		fx.expectUnits()
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, product.Articles).Return(nil)
+		fx.serialsRepo.EXPECT().GetSerial(fx.ctx, serial).Return(models.ArticleSerial{Serial: serial, ArticleID: 1}, nil)
+		fx.expectArticle(models.Article{ID: 1, Serialized: true})
+		fx.expectArticle(models.Article{ID: 2})
+		fx.serialsRepo.EXPECT().AssignSerials(fx.ctx, sale.ID, int32(1), []string{serial}).Return(nil)
+		fx.serialsRepo.EXPECT().AssignAvailableSerials(fx.ctx, sale.ID, int32(1), int32(1)).Return([]string{testhelpers.RandomString()}, nil)
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, 1, []string{serial}, false)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should sell assembled units first", func(t *testing.T) {
+		fx := newFixture(t)
+
+		product := product
+		product.Assembled = 2
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, 3)
+		fx.productsRepo.EXPECT().TakeAssembled(fx.ctx, productID, int32(3)).Return(int32(2), nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: product.Articles[0].ID})
+		fx.expectArticle(models.Article{ID: product.Articles[1].ID})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: product.Articles[0].ID, Quantity: product.Articles[0].Quantity},
+			{ID: product.Articles[1].ID, Quantity: product.Articles[1].Quantity},
+		}).Return(nil)
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, 3, nil, false)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not remove articles if assembled units suffice", func(t *testing.T) {
+		fx := newFixture(t)
+
+		product := product
+		product.Assembled = 5
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, 3)
+		fx.productsRepo.EXPECT().TakeAssembled(fx.ctx, productID, int32(3)).Return(int32(3), nil)
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, 3, nil, false)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if serial does not belong to product", func(t *testing.T) {
+		fx := newFixture(t)
+
+		serial := testhelpers.RandomString()
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, 1)
+		fx.expectUnits()
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, gomock.Any()).Return(nil)
+		fx.serialsRepo.EXPECT().GetSerial(fx.ctx, serial).Return(models.ArticleSerial{Serial: serial, ArticleID: -1}, nil)
+		fx.expectArticle(models.Article{ID: product.Articles[0].ID})
+		fx.expectArticle(models.Article{ID: product.Articles[1].ID})
+
+		_, err := fx.RemoveProduct(fx.ctx, productID, 1, []string{serial}, false)
+
+		require.ErrorIs(t, err, ErrSerialMismatch)
+	})
+}
+
+func TestImpl_RemoveProductWithBackorder(t *testing.T) {
+	productID := testhelpers.RandomInt32()
+	product := models.Product{
+		ID: productID,
+		Articles: []models.ProductArticle{
+			{ID: 1, Quantity: 2},
+			{ID: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("should backorder units which cannot be built", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		sale := fx.expectSale(productID, 5)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 7, Reserved: 2})
+		fx.expectArticle(models.Article{ID: 2, Stock: 10})
+		fx.backordersRepo.EXPECT().CreateBackorder(fx.ctx, models.Backorder{
+			SaleID:    sale.ID,
+			ProductID: productID,
+			Quantity:  3,
+		}).Return(models.Backorder{ID: 1}, nil)
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 2},
+		}).Return(nil)
+
+		item, err := fx.RemoveProduct(fx.ctx, productID, 5, nil, true)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, item.Backordered)
+	})
+
+	t.Run("should backorder everything if nothing can be built", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		sale := fx.expectSale(productID, 2)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 1})
+		fx.expectArticle(models.Article{ID: 2, Stock: 10})
+		fx.backordersRepo.EXPECT().CreateBackorder(fx.ctx, models.Backorder{
+			SaleID:    sale.ID,
+			ProductID: productID,
+			Quantity:  2,
+		}).Return(models.Backorder{ID: 1}, nil)
+
+		item, err := fx.RemoveProduct(fx.ctx, productID, 2, nil, true)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, item.Backordered)
+	})
+
+	t.Run("should not backorder if stock suffices", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectSale(productID, 2)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 4})
+		fx.expectArticle(models.Article{ID: 2, Stock: 2})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 2},
+		}).Return(nil)
+
+		item, err := fx.RemoveProduct(fx.ctx, productID, 2, nil, true)
+
+		require.NoError(t, err)
+		assert.Zero(t, item.Backordered)
+	})
+}
+
+func TestImpl_FulfilBackorders(t *testing.T) {
+	product := models.Product{
+		ID: 1,
+		Articles: []models.ProductArticle{
+			{ID: 1, Quantity: 2},
+			{ID: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("should fulfil oldest backorders first", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.backordersRepo.EXPECT().GetOpenBackordersForUpdate(fx.ctx).Return([]models.Backorder{
+			{ID: 1, SaleID: 10, ProductID: 1, Quantity: 3, Fulfilled: 1},
+			{ID: 2, SaleID: 11, ProductID: 1, Quantity: 2},
+		}, nil)
+		fx.expectUnits()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(product, nil).Times(2)
+		fx.expectArticle(models.Article{ID: 1, Stock: 6})
+		fx.expectArticle(models.Article{ID: 2, Stock: 5, Serialized: true})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 2},
+		}).Return(nil)
+		fx.serialsRepo.EXPECT().AssignAvailableSerials(fx.ctx, int32(10), int32(2), int32(2)).Return([]string{"A", "B"}, nil)
+		fulfilled := models.Backorder{ID: 1, SaleID: 10, ProductID: 1, Quantity: 3, Fulfilled: 3}
+		fx.backordersRepo.EXPECT().FulfilBackorder(fx.ctx, int32(1), int32(2)).Return(fulfilled, nil)
+		fx.expectArticle(models.Article{ID: 1, Stock: 2})
+		fx.expectArticle(models.Article{ID: 2, Stock: 3, Serialized: true})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 2},
+			{ID: 2, Quantity: 1},
+		}).Return(nil)
+		fx.serialsRepo.EXPECT().AssignAvailableSerials(fx.ctx, int32(11), int32(2), int32(1)).Return([]string{"C"}, nil)
+		partial := models.Backorder{ID: 2, SaleID: 11, ProductID: 1, Quantity: 2, Fulfilled: 1}
+		fx.backordersRepo.EXPECT().FulfilBackorder(fx.ctx, int32(2), int32(1)).Return(partial, nil)
+
+		items, err := fx.FulfilBackorders(fx.ctx, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Backorder{fulfilled, partial}, items)
+	})
+
+	t.Run("should skip products not made of the changed articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.backordersRepo.EXPECT().GetOpenBackordersForUpdate(fx.ctx).Return([]models.Backorder{
+			{ID: 1, SaleID: 10, ProductID: 1, Quantity: 3},
+		}, nil)
+		fx.expectUnits()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, int32(1)).Return(product, nil)
+
+		items, err := fx.FulfilBackorders(fx.ctx, []int32{5})
+
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
+
+func TestImpl_GeneratePickList(t *testing.T) {
+	t.Run("should aggregate articles with shortages", func(t *testing.T) {
+		fx := newFixture(t)
+
+		products := []models.Product{
+			{ID: 1, Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 2, Quantity: 1, Unit: "m"}}},
+			{ID: 2, Articles: []models.ProductArticle{{ID: 1, Quantity: 2}, {ID: 9, Quantity: 1}}},
+			{ID: 3, Articles: []models.ProductArticle{{ID: 1, Quantity: 2, Unit: "m"}}},
+		}
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(products, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return([]models.Article{
+			{ID: 1, Name: "leg", Unit: "pcs", Stock: 12, Reserved: 2},
+			{ID: 2, Name: "rail", Unit: "cm", Stock: 500},
+		}, nil)
+		fx.expectUnits()
+
+		list, err := fx.GeneratePickList(fx.ctx, []models.ProductSale{
+			{ProductID: 2, Quantity: 1},
+			{ProductID: 1, Quantity: 3},
+			{ProductID: 3, Quantity: 5},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.PickListItem{
+			{ArticleID: 1, Name: "leg", Unit: "pcs", Quantity: 14, OnHand: 10, Shortage: 4, ProductIDs: []int32{2, 1}},
+			{ArticleID: 1, Name: "leg", Unit: "m", Quantity: 10, Shortage: 10, Missing: true, ProductIDs: []int32{3}},
+			{ArticleID: 2, Name: "rail", Unit: "cm", Quantity: 300, OnHand: 500, ProductIDs: []int32{1}},
+			{ArticleID: 9, Quantity: 1, Shortage: 1, Missing: true, ProductIDs: []int32{2}},
+		}, list.Items)
+	})
+
+	t.Run("should fail on unknown product", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(nil, nil)
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(nil, nil)
+		fx.expectUnits()
+
+		_, err := fx.GeneratePickList(fx.ctx, []models.ProductSale{{ProductID: 1, Quantity: 1}})
+
+		require.ErrorIs(t, err, productsrepo.ErrNotFound)
+	})
+
+	t.Run("should reject empty list", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.GeneratePickList(fx.ctx, nil)
+
+		require.ErrorIs(t, err, ErrEmptyPickList)
+	})
+}
+
+func TestWritePickList(t *testing.T) {
+	list := models.PickList{
+		Lines: []models.ProductSale{{ProductID: 1, Quantity: 3}},
+		Items: []models.PickListItem{
+			{ArticleID: 1, Name: "leg", Unit: "pcs", Quantity: 12, OnHand: 10, Shortage: 2, ProductIDs: []int32{1, 2}},
+			{ArticleID: 1, Name: "leg", Unit: "m", Quantity: 10, Shortage: 10, Missing: true, ProductIDs: []int32{3}},
+			{ArticleID: 9, Quantity: 1, Shortage: 1, Missing: true, ProductIDs: []int32{2}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WritePickListCSV(&buf, list)
+
+	require.NoError(t, err)
+	assert.Equal(t, "article_id,name,quantity,unit,on_hand,shortage,product_ids,missing\n"+
+		"1,leg,12,pcs,10,2,1 2,false\n"+
+		"1,leg,10,m,0,10,3,true\n"+
+		"9,,1,,0,1,2,true\n", buf.String())
+
+	buf.Reset()
+	err = WritePickListText(&buf, list)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Pick list\n"+
+		"Product 1  x 3\n"+
+		"\n"+
+		"ARTICLE  NAME                     QUANTITY  UNIT  ON HAND  SHORTAGE  PRODUCTS\n"+
+		"1        leg                      12        pcs   10       2         1, 2\n"+
+		"1        leg (incompatible unit)  10        m     0        10        3\n"+
+		"9        (unknown)                1               0        1         2\n", buf.String())
+}
+
+func TestImpl_CreateAssemblyOrder(t *testing.T) {
+	productID := testhelpers.RandomInt32()
+	product := models.Product{
+		ID: productID,
+		Articles: []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 1, Unit: "box"},
+		},
+	}
+
+	t.Run("should consume component articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 8, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Stock: 250, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 8},
+			{ID: 2, Quantity: 200},
+		}).Return(nil)
+		order := models.AssemblyOrder{
+			ID:        testhelpers.RandomInt32(),
+			ProductID: productID,
+			Quantity:  2,
+			Status:    models.AssemblyOrderOpen,
+		}
+		fx.assembliesRepo.EXPECT().CreateAssemblyOrder(fx.ctx, models.AssemblyOrder{ProductID: productID, Quantity: 2}).Return(order, nil)
+
+		item, err := fx.CreateAssemblyOrder(fx.ctx, productID, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, order, item)
+	})
+
+	t.Run("should fail on insufficient stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 8, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Stock: 150, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 8},
+			{ID: 2, Quantity: 200},
+		}).Return(fmt.Errorf("%w: article 2", articlesrepo.ErrInsufficientStock))
+
+		_, err := fx.CreateAssemblyOrder(fx.ctx, productID, 2)
+
+		require.ErrorIs(t, err, articlesrepo.ErrInsufficientStock)
+	})
+
+	t.Run("should sum articles listed more than once", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(models.Product{
+			ID:       productID,
+			Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 1, Quantity: 2}},
+		}, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 5, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 1, Stock: 5, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 6},
+		}).Return(fmt.Errorf("%w: article 1", articlesrepo.ErrInsufficientStock))
+
+		_, err := fx.CreateAssemblyOrder(fx.ctx, productID, 1)
+
+		require.ErrorIs(t, err, articlesrepo.ErrInsufficientStock)
+	})
+
+	t.Run("should not assemble serialized articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Stock: 8, Unit: "pcs", Serialized: true})
+		fx.expectArticle(models.Article{ID: 2, Stock: 250, Unit: "pcs"})
+
+		_, err := fx.CreateAssemblyOrder(fx.ctx, productID, 2)
+
+		require.ErrorIs(t, err, ErrNotAssemblable)
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.CreateAssemblyOrder(fx.ctx, productID, 0)
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestImpl_CompleteAssemblyOrder(t *testing.T) {
+	order := models.AssemblyOrder{
+		ID:        testhelpers.RandomInt32(),
+		ProductID: testhelpers.RandomInt32(),
+		Quantity:  3,
+		Status:    models.AssemblyOrderOpen,
+	}
+
+	t.Run("should add assembled units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		completed := order
+		completed.Status = models.AssemblyOrderCompleted
+		fx.expectTx()
+		fx.assembliesRepo.EXPECT().GetAssemblyOrderForUpdate(fx.ctx, order.ID).Return(order, nil)
+		fx.productsRepo.EXPECT().AddAssembled(fx.ctx, order.ProductID, order.Quantity).Return(nil)
+		fx.assembliesRepo.EXPECT().CompleteAssemblyOrder(fx.ctx, order.ID).Return(completed, nil)
+
+		item, err := fx.CompleteAssemblyOrder(fx.ctx, order.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, completed, item)
+	})
+
+	t.Run("should not complete twice", func(t *testing.T) {
+		fx := newFixture(t)
+
+		completed := order
+		completed.Status = models.AssemblyOrderCompleted
+		fx.expectTx()
+		fx.assembliesRepo.EXPECT().GetAssemblyOrderForUpdate(fx.ctx, order.ID).Return(completed, nil)
+
+		_, err := fx.CompleteAssemblyOrder(fx.ctx, order.ID)
+
+		require.ErrorIs(t, err, ErrAssemblyCompleted)
+	})
+}
+
+func TestImpl_DisassembleProduct(t *testing.T) {
+	productID := testhelpers.RandomInt32()
+	product := models.Product{
+		ID: productID,
+		Articles: []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 1, Unit: "box"},
+		},
+	}
+
+	t.Run("should recover articles less scrap", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().AddArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 7},
+			{ID: 2, Quantity: 150},
+		}).Return(nil)
+		expected := models.Disassembly{
+			ProductID: productID,
+			Quantity:  2,
+			Components: []models.DisassembledComponent{
+				{ArticleID: 1, Expected: 8, Recovered: 7, Scrapped: 1},
+				{ArticleID: 2, Expected: 200, Recovered: 150, Scrapped: 50},
+			},
+		}
+		fx.assembliesRepo.EXPECT().CreateDisassembly(fx.ctx, expected).Return(expected, nil)
+
+		item, err := fx.DisassembleProduct(fx.ctx, productID, 2, false, []models.ProductArticle{
+			{ID: 1, Quantity: 1},
+			{ID: 2, Quantity: 50},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, item)
+	})
+
+	t.Run("should take assembled units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.productsRepo.EXPECT().TakeAssembled(fx.ctx, productID, int32(1)).Return(int32(1), nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Unit: "pcs"})
+		fx.articlesRepo.EXPECT().AddArticles(fx.ctx, []models.ProductArticle{
+			{ID: 1, Quantity: 4},
+			{ID: 2, Quantity: 100},
+		}).Return(nil)
+		fx.assembliesRepo.EXPECT().CreateDisassembly(fx.ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, item models.Disassembly) (models.Disassembly, error) {
+				return item, nil
+			},
+		)
+
+		item, err := fx.DisassembleProduct(fx.ctx, productID, 1, true, nil)
+
+		require.NoError(t, err)
+		assert.True(t, item.FromAssembled)
+	})
+
+	t.Run("should fail without enough assembled units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.productsRepo.EXPECT().TakeAssembled(fx.ctx, productID, int32(2)).Return(int32(1), nil)
+
+		_, err := fx.DisassembleProduct(fx.ctx, productID, 2, true, nil)
+
+		require.ErrorIs(t, err, ErrInsufficientStock)
+	})
+
+	t.Run("should reject scrap exceeding expected quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Unit: "pcs"})
+
+		_, err := fx.DisassembleProduct(fx.ctx, productID, 1, false, []models.ProductArticle{{ID: 1, Quantity: 5}})
+
+		require.ErrorIs(t, err, ErrInvalidScrap)
+	})
+
+	t.Run("should reject scrap of other articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.productsRepo.EXPECT().GetProduct(fx.ctx, productID).Return(product, nil)
+		fx.expectUnits()
+		fx.expectArticle(models.Article{ID: 1, Unit: "pcs"})
+		fx.expectArticle(models.Article{ID: 2, Unit: "pcs"})
+
+		_, err := fx.DisassembleProduct(fx.ctx, productID, 1, false, []models.ProductArticle{{ID: 3, Quantity: 1}})
+
+		require.ErrorIs(t, err, ErrInvalidScrap)
+	})
 }
 
 type fixture struct {
 	Service
 
-	t            *testing.T
-	ctx          context.Context
-	articlesRepo *mockArticlesRepo.MockRepository
-	productsRepo *mockProductsRepo.MockRepository
+	t              *testing.T
+	ctx            context.Context
+	tx             *mockDB.MockTransactor
+	articlesRepo   *mockArticlesRepo.MockRepository
+	productsRepo   *mockProductsRepo.MockRepository
+	salesRepo      *mockSalesRepo.MockRepository
+	serialsRepo    *mockSerialsRepo.MockRepository
+	unitsRepo      *mockUnitsRepo.MockRepository
+	assembliesRepo *mockAssembliesRepo.MockRepository
+	backordersRepo *mockBackordersRepo.MockRepository
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -269,11 +1386,59 @@ func newFixture(t *testing.T) *fixture {
 	ctrl := gomock.NewController(t)
 
 	fx := &fixture{
-		t:            t,
-		ctx:          ctx,
-		articlesRepo: mockArticlesRepo.NewMockRepository(ctrl),
-		productsRepo: mockProductsRepo.NewMockRepository(ctrl),
+		t:              t,
+		ctx:            ctx,
+		tx:             mockDB.NewMockTransactor(ctrl),
+		articlesRepo:   mockArticlesRepo.NewMockRepository(ctrl),
+		productsRepo:   mockProductsRepo.NewMockRepository(ctrl),
+		salesRepo:      mockSalesRepo.NewMockRepository(ctrl),
+		serialsRepo:    mockSerialsRepo.NewMockRepository(ctrl),
+		unitsRepo:      mockUnitsRepo.NewMockRepository(ctrl),
+		assembliesRepo: mockAssembliesRepo.NewMockRepository(ctrl),
+		backordersRepo: mockBackordersRepo.NewMockRepository(ctrl),
 	}
-	fx.Service = NewService(fx.articlesRepo, fx.productsRepo)
+	fx.Service = NewService(
+		fx.tx,
+		fx.articlesRepo,
+		fx.productsRepo,
+		fx.salesRepo,
+		fx.serialsRepo,
+		fx.unitsRepo,
+		fx.assembliesRepo,
+		fx.backordersRepo,
+	)
 	return fx
 }
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
+
+func (fx *fixture) expectSale(productID, quantity int32) models.Sale {
+	sale := models.Sale{
+		ID:        testhelpers.RandomInt32(),
+		ProductID: productID,
+		Quantity:  quantity,
+	}
+	fx.salesRepo.EXPECT().CreateSale(fx.ctx, models.Sale{ProductID: productID, Quantity: quantity}).Return(sale, nil)
+	return sale
+}
+
+func (fx *fixture) expectArticle(article models.Article) {
+	fx.articlesRepo.EXPECT().GetArticle(fx.ctx, article.ID).Return(article, nil)
+}
+
+func (fx *fixture) expectUnits() {
+	units := []models.Unit{
+		{Code: "pcs", Dimension: "count", Factor: 1},
+		{Code: "cm", Dimension: "length", Factor: 10},
+		{Code: "m", Dimension: "length", Factor: 1000},
+	}
+	articleUnits := []models.ArticleUnit{
+		{ArticleID: 2, Code: "box", Factor: 100},
+	}
+	fx.unitsRepo.EXPECT().GetUnits(fx.ctx).Return(units, nil)
+	fx.unitsRepo.EXPECT().GetArticleUnits(fx.ctx).Return(articleUnits, nil)
+}
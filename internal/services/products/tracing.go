@@ -0,0 +1,122 @@
+package products
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"warehouse/internal/models"
+)
+
+const tracerName = "warehouse/internal/services/products"
+
+// tracing wraps every call of the service in a span, queries run by the call become its children
+type tracing struct {
+	Service
+
+	tracer trace.Tracer
+}
+
+func NewTracing(srv Service, tp trace.TracerProvider) Service {
+	return &tracing{
+		Service: srv,
+		tracer:  tp.Tracer(tracerName),
+	}
+}
+
+func (t *tracing) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "products.Service/"+name, trace.WithAttributes(attrs...))
+}
+
+// end records the error of the call and ends the span
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracing) GetProductsWithStock(ctx context.Context) (_ []models.ProductWithStock, err error) {
+	ctx, span := t.start(ctx, "GetProductsWithStock")
+	defer func() { end(span, err) }()
+	return t.Service.GetProductsWithStock(ctx)
+}
+
+func (t *tracing) GetProductsWithStockByID(ctx context.Context, ids []int32) (_ []models.ProductWithStock, err error) {
+	ctx, span := t.start(ctx, "GetProductsWithStockByID", attribute.Int("products.count", len(ids)))
+	defer func() { end(span, err) }()
+	return t.Service.GetProductsWithStockByID(ctx, ids)
+}
+
+func (t *tracing) UpdateProduct(ctx context.Context, item models.Product) (_ models.ProductWithStock, err error) {
+	ctx, span := t.start(ctx, "UpdateProduct", attribute.Int("product.id", int(item.ID)))
+	defer func() { end(span, err) }()
+	return t.Service.UpdateProduct(ctx, item)
+}
+
+func (t *tracing) RemoveProduct(ctx context.Context, id, quantity int32, serials []string, allowBackorder bool) (_ models.Sale, err error) {
+	ctx, span := t.start(ctx, "RemoveProduct",
+		attribute.Int("product.id", int(id)),
+		attribute.Int("product.quantity", int(quantity)),
+		attribute.Bool("product.allow_backorder", allowBackorder),
+	)
+	defer func() { end(span, err) }()
+	return t.Service.RemoveProduct(ctx, id, quantity, serials, allowBackorder)
+}
+
+func (t *tracing) PlanProductionMix(ctx context.Context, objective models.MixObjective, caps map[int32]int32) (_ models.ProductionMix, err error) {
+	ctx, span := t.start(ctx, "PlanProductionMix")
+	defer func() { end(span, err) }()
+	return t.Service.PlanProductionMix(ctx, objective, caps)
+}
+
+func (t *tracing) SimulateAvailability(ctx context.Context, sim models.Simulation) (_ models.SimulationResult, err error) {
+	ctx, span := t.start(ctx, "SimulateAvailability")
+	defer func() { end(span, err) }()
+	return t.Service.SimulateAvailability(ctx, sim)
+}
+
+func (t *tracing) CreateAssemblyOrder(ctx context.Context, productID, quantity int32) (_ models.AssemblyOrder, err error) {
+	ctx, span := t.start(ctx, "CreateAssemblyOrder",
+		attribute.Int("product.id", int(productID)),
+		attribute.Int("product.quantity", int(quantity)),
+	)
+	defer func() { end(span, err) }()
+	return t.Service.CreateAssemblyOrder(ctx, productID, quantity)
+}
+
+func (t *tracing) CompleteAssemblyOrder(ctx context.Context, id int32) (_ models.AssemblyOrder, err error) {
+	ctx, span := t.start(ctx, "CompleteAssemblyOrder", attribute.Int("assembly_order.id", int(id)))
+	defer func() { end(span, err) }()
+	return t.Service.CompleteAssemblyOrder(ctx, id)
+}
+
+func (t *tracing) DisassembleProduct(ctx context.Context, productID, quantity int32, fromAssembled bool, scrap []models.ProductArticle) (_ models.Disassembly, err error) {
+	ctx, span := t.start(ctx, "DisassembleProduct",
+		attribute.Int("product.id", int(productID)),
+		attribute.Int("product.quantity", int(quantity)),
+	)
+	defer func() { end(span, err) }()
+	return t.Service.DisassembleProduct(ctx, productID, quantity, fromAssembled, scrap)
+}
+
+func (t *tracing) GetBackorders(ctx context.Context, openOnly bool) (_ []models.Backorder, err error) {
+	ctx, span := t.start(ctx, "GetBackorders")
+	defer func() { end(span, err) }()
+	return t.Service.GetBackorders(ctx, openOnly)
+}
+
+func (t *tracing) FulfilBackorders(ctx context.Context, articleIDs []int32) (_ []models.Backorder, err error) {
+	ctx, span := t.start(ctx, "FulfilBackorders", attribute.Int("articles.count", len(articleIDs)))
+	defer func() { end(span, err) }()
+	return t.Service.FulfilBackorders(ctx, articleIDs)
+}
+
+func (t *tracing) GeneratePickList(ctx context.Context, lines []models.ProductSale) (_ models.PickList, err error) {
+	ctx, span := t.start(ctx, "GeneratePickList")
+	defer func() { end(span, err) }()
+	return t.Service.GeneratePickList(ctx, lines)
+}
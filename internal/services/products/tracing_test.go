@@ -0,0 +1,58 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/models"
+	"warehouse/internal/services/products/mock"
+)
+
+func TestTracing(t *testing.T) {
+	t.Run("should wrap calls in spans", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		srv := mockProductsSrv.NewMockService(gomock.NewController(t))
+		traced := NewTracing(srv, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		srv.EXPECT().RemoveProduct(gomock.Any(), int32(1), int32(2), nil, false).
+			DoAndReturn(func(ctx context.Context, _, _ int32, _ []string, _ bool) (models.Sale, error) {
+				// the span is passed on for the queries run by the service
+				assert.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+				return models.Sale{ID: 3}, nil
+			})
+
+		sale, err := traced.RemoveProduct(context.Background(), 1, 2, nil, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), sale.ID)
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "products.Service/RemoveProduct", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("product.id", 1))
+	})
+
+	t.Run("should record errors", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		srv := mockProductsSrv.NewMockService(gomock.NewController(t))
+		traced := NewTracing(srv, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		srv.EXPECT().GetProductsWithStock(gomock.Any()).Return(nil, errors.New("timeout"))
+
+		_, err := traced.GetProductsWithStock(context.Background())
+
+		require.Error(t, err)
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+}
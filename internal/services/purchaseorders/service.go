@@ -0,0 +1,219 @@
+package purchaseorders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/purchaseorders"
+	"warehouse/internal/repositories/suppliers"
+	"warehouse/internal/services/articles"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	ErrEmptyOrder      = errors.New("purchase order requires at least one line")
+	ErrInvalidStatus   = errors.New("operation is not allowed in the purchase order status")
+)
+
+type Service interface {
+	CreatePurchaseOrder(ctx context.Context, supplierID int32, lines []models.PurchaseOrderLine) (models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus) ([]models.PurchaseOrder, error)
+	SendPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, id int32, receipts []models.PurchaseOrderReceipt) (models.PurchaseOrder, error)
+	ClosePurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error)
+}
+
+type impl struct {
+	tx                 db.Transactor
+	articlesSrv        articles.Service
+	suppliersRepo      suppliers.Repository
+	purchaseOrdersRepo purchaseorders.Repository
+}
+
+func NewService(
+	tx db.Transactor,
+	articlesSrv articles.Service,
+	supRepo suppliers.Repository,
+	poRepo purchaseorders.Repository,
+) Service {
+	return &impl{
+		tx:                 tx,
+		articlesSrv:        articlesSrv,
+		suppliersRepo:      supRepo,
+		purchaseOrdersRepo: poRepo,
+	}
+}
+
+// CreatePurchaseOrder creates a draft order. Every article must be supplied by the supplier,
+// lines without a price are priced at the supplier price.
+func (srv *impl) CreatePurchaseOrder(ctx context.Context, supplierID int32, lines []models.PurchaseOrderLine) (models.PurchaseOrder, error) {
+	if len(lines) == 0 {
+		return models.PurchaseOrder{}, ErrEmptyOrder
+	}
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return models.PurchaseOrder{}, ErrInvalidQuantity
+		}
+	}
+
+	var order models.PurchaseOrder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		_, err := srv.suppliersRepo.GetSupplier(ctx, supplierID)
+		if err != nil {
+			return fmt.Errorf("failed to get supplier: %w", err)
+		}
+
+		order = models.PurchaseOrder{
+			SupplierID: supplierID,
+			Status:     models.PurchaseOrderDraft,
+			Lines:      make([]models.PurchaseOrderLine, 0, len(lines)),
+		}
+		for _, line := range lines {
+			terms, err := srv.suppliersRepo.GetSupplierArticle(ctx, supplierID, line.ArticleID)
+			if err != nil {
+				return fmt.Errorf("failed to get supplier article %d: %w", line.ArticleID, err)
+			}
+			if line.Price.Currency == "" {
+				line.Price = terms.Price
+			}
+			order.Lines = append(order.Lines, models.PurchaseOrderLine{
+				ArticleID: line.ArticleID,
+				Quantity:  line.Quantity,
+				Price:     line.Price,
+			})
+		}
+
+		order, err = srv.purchaseOrdersRepo.CreatePurchaseOrder(ctx, order)
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return order, nil
+}
+
+func (srv *impl) GetPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error) {
+	order, err := srv.purchaseOrdersRepo.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		return models.PurchaseOrder{}, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+	return order, nil
+}
+
+// GetPurchaseOrders lists orders in the status, all orders if the status is empty
+func (srv *impl) GetPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus) ([]models.PurchaseOrder, error) {
+	items, err := srv.purchaseOrdersRepo.GetPurchaseOrders(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase orders: %w", err)
+	}
+	return items, nil
+}
+
+// SendPurchaseOrder marks a draft order as sent to the supplier, only sent orders can be received
+func (srv *impl) SendPurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error) {
+	return srv.transition(ctx, id, models.PurchaseOrderSent, models.PurchaseOrderDraft)
+}
+
+// ClosePurchaseOrder closes an order which will not be received in full
+func (srv *impl) ClosePurchaseOrder(ctx context.Context, id int32) (models.PurchaseOrder, error) {
+	return srv.transition(ctx, id, models.PurchaseOrderClosed, models.PurchaseOrderSent, models.PurchaseOrderPartiallyReceived)
+}
+
+// ReceivePurchaseOrder receives quantities against the order lines and adds them to the article stock.
+// The order is closed once every line is received in full.
+func (srv *impl) ReceivePurchaseOrder(ctx context.Context, id int32, receipts []models.PurchaseOrderReceipt) (models.PurchaseOrder, error) {
+	if len(receipts) == 0 {
+		return models.PurchaseOrder{}, ErrInvalidQuantity
+	}
+	for _, receipt := range receipts {
+		if receipt.Quantity <= 0 {
+			return models.PurchaseOrder{}, ErrInvalidQuantity
+		}
+	}
+
+	var order models.PurchaseOrder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = srv.purchaseOrdersRepo.GetPurchaseOrderForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase order: %w", err)
+		}
+		if order.Status != models.PurchaseOrderSent && order.Status != models.PurchaseOrderPartiallyReceived {
+			return ErrInvalidStatus
+		}
+
+		lines := make(map[int32]*models.PurchaseOrderLine, len(order.Lines))
+		for i := range order.Lines {
+			lines[order.Lines[i].ID] = &order.Lines[i]
+		}
+		for _, receipt := range receipts {
+			line, ok := lines[receipt.LineID]
+			if !ok {
+				return purchaseorders.ErrLineNotFound
+			}
+			err = srv.purchaseOrdersRepo.ReceiveLine(ctx, id, receipt.LineID, receipt.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to receive line %d: %w", receipt.LineID, err)
+			}
+			err = srv.articlesSrv.AddStock(ctx, line.ArticleID, receipt.Quantity, receipt.Serials)
+			if err != nil {
+				return fmt.Errorf("failed to add stock of article %d: %w", line.ArticleID, err)
+			}
+			line.Received += receipt.Quantity
+		}
+
+		order.Status = models.PurchaseOrderClosed
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				order.Status = models.PurchaseOrderPartiallyReceived
+				break
+			}
+		}
+		err = srv.purchaseOrdersRepo.UpdateStatus(ctx, id, order.Status)
+		if err != nil {
+			return fmt.Errorf("failed to update purchase order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return order, nil
+}
+
+// transition moves the order to the status if it is currently in one of the given statuses
+func (srv *impl) transition(ctx context.Context, id int32, to models.PurchaseOrderStatus, from ...models.PurchaseOrderStatus) (models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = srv.purchaseOrdersRepo.GetPurchaseOrderForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase order: %w", err)
+		}
+		allowed := false
+		for _, status := range from {
+			allowed = allowed || order.Status == status
+		}
+		if !allowed {
+			return ErrInvalidStatus
+		}
+
+		err = srv.purchaseOrdersRepo.UpdateStatus(ctx, id, to)
+		if err != nil {
+			return fmt.Errorf("failed to update purchase order: %w", err)
+		}
+		order.Status = to
+		return nil
+	})
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	return order, nil
+}
@@ -0,0 +1,201 @@
+package purchaseorders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/db/mock"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/purchaseorders/mock"
+	"warehouse/internal/repositories/suppliers/mock"
+	"warehouse/internal/services/articles/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreatePurchaseOrder(t *testing.T) {
+	supplierID := testhelpers.RandomInt32()
+	eur := func(amount int64) models.Money {
+		return models.Money{Amount: amount, Currency: "EUR"}
+	}
+
+	t.Run("should price lines at supplier price", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.suppliersRepo.EXPECT().GetSupplier(fx.ctx, supplierID).Return(models.Supplier{ID: supplierID}, nil)
+		fx.suppliersRepo.EXPECT().GetSupplierArticle(fx.ctx, supplierID, int32(1)).
+			Return(models.SupplierArticle{ArticleID: 1, Price: eur(150)}, nil)
+		fx.suppliersRepo.EXPECT().GetSupplierArticle(fx.ctx, supplierID, int32(2)).
+			Return(models.SupplierArticle{ArticleID: 2, Price: eur(300)}, nil)
+		expected := models.PurchaseOrder{
+			SupplierID: supplierID,
+			Status:     models.PurchaseOrderDraft,
+			Lines: []models.PurchaseOrderLine{
+				{ArticleID: 1, Quantity: 10, Price: eur(150)},
+				{ArticleID: 2, Quantity: 5, Price: eur(250)},
+			},
+		}
+		fx.purchaseOrdersRepo.EXPECT().CreatePurchaseOrder(fx.ctx, expected).Return(expected, nil)
+
+		order, err := fx.CreatePurchaseOrder(fx.ctx, supplierID, []models.PurchaseOrderLine{
+			{ArticleID: 1, Quantity: 10},
+			{ArticleID: 2, Quantity: 5, Price: eur(250)},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, order)
+	})
+
+	t.Run("should reject empty order", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.CreatePurchaseOrder(fx.ctx, supplierID, nil)
+
+		require.ErrorIs(t, err, ErrEmptyOrder)
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.CreatePurchaseOrder(fx.ctx, supplierID, []models.PurchaseOrderLine{{ArticleID: 1}})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestImpl_SendPurchaseOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+
+	t.Run("should send draft", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.purchaseOrdersRepo.EXPECT().GetPurchaseOrderForUpdate(fx.ctx, orderID).
+			Return(models.PurchaseOrder{ID: orderID, Status: models.PurchaseOrderDraft}, nil)
+		fx.purchaseOrdersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.PurchaseOrderSent).Return(nil)
+
+		order, err := fx.SendPurchaseOrder(fx.ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PurchaseOrderSent, order.Status)
+	})
+
+	t.Run("should not send twice", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.purchaseOrdersRepo.EXPECT().GetPurchaseOrderForUpdate(fx.ctx, orderID).
+			Return(models.PurchaseOrder{ID: orderID, Status: models.PurchaseOrderSent}, nil)
+
+		_, err := fx.SendPurchaseOrder(fx.ctx, orderID)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+func TestImpl_ReceivePurchaseOrder(t *testing.T) {
+	orderID := testhelpers.RandomInt32()
+	newOrder := func(status models.PurchaseOrderStatus) models.PurchaseOrder {
+		return models.PurchaseOrder{
+			ID:     orderID,
+			Status: status,
+			Lines: []models.PurchaseOrderLine{
+				{ID: 11, ArticleID: 1, Quantity: 10},
+				{ID: 12, ArticleID: 2, Quantity: 4, Received: 2},
+			},
+		}
+	}
+
+	t.Run("should receive partially", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.purchaseOrdersRepo.EXPECT().GetPurchaseOrderForUpdate(fx.ctx, orderID).Return(newOrder(models.PurchaseOrderSent), nil)
+		fx.purchaseOrdersRepo.EXPECT().ReceiveLine(fx.ctx, orderID, int32(11), int32(6)).Return(nil)
+		fx.articlesSrv.EXPECT().AddStock(fx.ctx, int32(1), int32(6), nil).Return(nil)
+		fx.purchaseOrdersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.PurchaseOrderPartiallyReceived).Return(nil)
+
+		order, err := fx.ReceivePurchaseOrder(fx.ctx, orderID, []models.PurchaseOrderReceipt{{LineID: 11, Quantity: 6}})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PurchaseOrderPartiallyReceived, order.Status)
+		assert.Equal(t, int32(6), order.Lines[0].Received)
+	})
+
+	t.Run("should close when received in full", func(t *testing.T) {
+		fx := newFixture(t)
+
+		serials := []string{testhelpers.RandomString(), testhelpers.RandomString()}
+		fx.expectTx()
+		fx.purchaseOrdersRepo.EXPECT().GetPurchaseOrderForUpdate(fx.ctx, orderID).Return(newOrder(models.PurchaseOrderPartiallyReceived), nil)
+		fx.purchaseOrdersRepo.EXPECT().ReceiveLine(fx.ctx, orderID, int32(11), int32(10)).Return(nil)
+		fx.articlesSrv.EXPECT().AddStock(fx.ctx, int32(1), int32(10), nil).Return(nil)
+		fx.purchaseOrdersRepo.EXPECT().ReceiveLine(fx.ctx, orderID, int32(12), int32(2)).Return(nil)
+		fx.articlesSrv.EXPECT().AddStock(fx.ctx, int32(2), int32(2), serials).Return(nil)
+		fx.purchaseOrdersRepo.EXPECT().UpdateStatus(fx.ctx, orderID, models.PurchaseOrderClosed).Return(nil)
+
+		order, err := fx.ReceivePurchaseOrder(fx.ctx, orderID, []models.PurchaseOrderReceipt{
+			{LineID: 11, Quantity: 10},
+			{LineID: 12, Quantity: 2, Serials: serials},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.PurchaseOrderClosed, order.Status)
+	})
+
+	t.Run("should not receive draft", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.purchaseOrdersRepo.EXPECT().GetPurchaseOrderForUpdate(fx.ctx, orderID).Return(newOrder(models.PurchaseOrderDraft), nil)
+
+		_, err := fx.ReceivePurchaseOrder(fx.ctx, orderID, []models.PurchaseOrderReceipt{{LineID: 11, Quantity: 1}})
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+
+	t.Run("should reject non-positive quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.ReceivePurchaseOrder(fx.ctx, orderID, []models.PurchaseOrderReceipt{{LineID: 11}})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+type fixture struct {
+	Service
+
+	t                  *testing.T
+	ctx                context.Context
+	tx                 *mockDB.MockTransactor
+	articlesSrv        *mockArticlesSrv.MockService
+	suppliersRepo      *mockSuppliersRepo.MockRepository
+	purchaseOrdersRepo *mockPurchaseOrdersRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:                  t,
+		ctx:                ctx,
+		tx:                 mockDB.NewMockTransactor(ctrl),
+		articlesSrv:        mockArticlesSrv.NewMockService(ctrl),
+		suppliersRepo:      mockSuppliersRepo.NewMockRepository(ctrl),
+		purchaseOrdersRepo: mockPurchaseOrdersRepo.NewMockRepository(ctrl),
+	}
+	fx.Service = NewService(fx.tx, fx.articlesSrv, fx.suppliersRepo, fx.purchaseOrdersRepo)
+	return fx
+}
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
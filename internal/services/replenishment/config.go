@@ -0,0 +1,11 @@
+package replenishment
+
+const (
+	defaultWindowDays      = 30
+	defaultSafetyStockDays = 7
+)
+
+type Config struct {
+	WindowDays      int32 `koanf:"window_days"`
+	SafetyStockDays int32 `koanf:"safety_stock_days"`
+}
@@ -0,0 +1,62 @@
+package replenishment
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"warehouse/internal/models"
+)
+
+var csvHeader = []string{
+	"supplier_id", "sku", "article_id", "name", "quantity", "unit", "unit_price", "total", "currency", "lead_time_days",
+}
+
+// WriteCSV writes the suggestions as an order proposal grouped by supplier,
+// articles without a supplier come last with empty supplier columns
+func WriteCSV(w io.Writer, suggestions []models.ReplenishmentSuggestion) error {
+	items := make([]models.ReplenishmentSuggestion, len(suggestions))
+	copy(items, suggestions)
+	sort.SliceStable(items, func(i, j int) bool {
+		return supplierKey(items[i]) < supplierKey(items[j])
+	})
+
+	cw := csv.NewWriter(w)
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		record := []string{
+			"", "",
+			strconv.Itoa(int(item.ArticleID)),
+			item.Name,
+			strconv.Itoa(int(item.Quantity)),
+			item.Unit,
+			"", "", "",
+			strconv.Itoa(int(item.LeadTimeDays)),
+		}
+		if item.Source != nil {
+			record[0] = strconv.Itoa(int(item.Source.SupplierID))
+			record[1] = item.Source.SKU
+			record[6] = item.Source.Price.Decimal()
+			record[7] = item.Source.Price.Mul(int64(item.Quantity)).Decimal()
+			record[8] = item.Source.Price.Currency
+		}
+		err := cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func supplierKey(item models.ReplenishmentSuggestion) int64 {
+	if item.Source == nil {
+		return math.MaxInt64
+	}
+	return int64(item.Source.SupplierID)
+}
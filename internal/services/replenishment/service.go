@@ -0,0 +1,210 @@
+package replenishment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/products"
+	"warehouse/internal/repositories/purchaseorders"
+	"warehouse/internal/repositories/sales"
+	"warehouse/internal/repositories/suppliers"
+	"warehouse/internal/repositories/units"
+	"warehouse/internal/uom"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("window and safety stock days must not be negative")
+)
+
+type Service interface {
+	SuggestReplenishment(ctx context.Context, policy models.ReplenishmentPolicy) (models.Replenishment, error)
+}
+
+type impl struct {
+	cfg                Config
+	articlesRepo       articles.Repository
+	productsRepo       products.Repository
+	salesRepo          sales.Repository
+	suppliersRepo      suppliers.Repository
+	purchaseOrdersRepo purchaseorders.Repository
+	unitsRepo          units.Repository
+	now                func() time.Time
+}
+
+func NewService(
+	cfg Config,
+	aRepo articles.Repository,
+	pRepo products.Repository,
+	sRepo sales.Repository,
+	supRepo suppliers.Repository,
+	poRepo purchaseorders.Repository,
+	uRepo units.Repository,
+) Service {
+	if cfg.WindowDays == 0 {
+		cfg.WindowDays = defaultWindowDays
+	}
+	if cfg.SafetyStockDays == 0 {
+		cfg.SafetyStockDays = defaultSafetyStockDays
+	}
+	return &impl{
+		cfg:                cfg,
+		articlesRepo:       aRepo,
+		productsRepo:       pRepo,
+		salesRepo:          sRepo,
+		suppliersRepo:      supRepo,
+		purchaseOrdersRepo: poRepo,
+		unitsRepo:          uRepo,
+		now:                time.Now,
+	}
+}
+
+// SuggestReplenishment proposes order quantities for the articles whose available stock and open purchase
+// orders do not cover the demand over the lead time plus the safety stock. The daily consumption is averaged
+// over the sales of the window exploded through the product BOMs, products whose BOM cannot be converted
+// to article units are skipped and reported. Zero policy fields use the configured defaults.
+func (srv *impl) SuggestReplenishment(ctx context.Context, policy models.ReplenishmentPolicy) (models.Replenishment, error) {
+	if policy.WindowDays < 0 || policy.SafetyStockDays < 0 {
+		return models.Replenishment{}, ErrInvalidPolicy
+	}
+	if policy.WindowDays == 0 {
+		policy.WindowDays = srv.cfg.WindowDays
+	}
+	if policy.SafetyStockDays == 0 {
+		policy.SafetyStockDays = srv.cfg.SafetyStockDays
+	}
+
+	arts, err := srv.articlesRepo.GetArticles(ctx)
+	if err != nil {
+		return models.Replenishment{}, fmt.Errorf("failed to get articles: %w", err)
+	}
+	consumption, skipped, err := srv.consumption(ctx, arts, policy.WindowDays)
+	if err != nil {
+		return models.Replenishment{}, err
+	}
+
+	onOrder := make(map[int32]int32)
+	items, err := srv.purchaseOrdersRepo.GetOnOrder(ctx)
+	if err != nil {
+		return models.Replenishment{}, fmt.Errorf("failed to get quantities on order: %w", err)
+	}
+	for _, item := range items {
+		onOrder[item.ID] = item.Quantity
+	}
+
+	// sources are ordered by the shortest lead time first
+	sources := make(map[int32]models.SupplierArticle)
+	sourceList, err := srv.suppliersRepo.GetArticleSources(ctx)
+	if err != nil {
+		return models.Replenishment{}, fmt.Errorf("failed to get article sources: %w", err)
+	}
+	for _, source := range sourceList {
+		if _, ok := sources[source.ArticleID]; !ok {
+			sources[source.ArticleID] = source
+		}
+	}
+
+	result := models.Replenishment{
+		SkippedProductIDs: skipped,
+	}
+	// reserved stock is promised to orders and does not cover the demand
+	for _, article := range arts {
+		suggestion := models.ReplenishmentSuggestion{
+			ArticleID:        article.ID,
+			Name:             article.Name,
+			Unit:             article.Unit,
+			Stock:            article.Available(),
+			OnOrder:          onOrder[article.ID],
+			DailyConsumption: float64(consumption[article.ID]) / float64(policy.WindowDays),
+		}
+		if source, ok := sources[article.ID]; ok {
+			suggestion.Source = &source
+			suggestion.LeadTimeDays = source.LeadTimeDays
+		}
+		suggestion.SafetyStock = int32(math.Ceil(suggestion.DailyConsumption * float64(policy.SafetyStockDays)))
+		suggestion.ReorderLevel = int32(math.Ceil(suggestion.DailyConsumption*float64(suggestion.LeadTimeDays))) + suggestion.SafetyStock
+
+		need := suggestion.ReorderLevel - suggestion.Stock - suggestion.OnOrder
+		if need <= 0 {
+			continue
+		}
+		suggestion.Quantity = max(need, article.ReorderQuantity)
+		result.Suggestions = append(result.Suggestions, suggestion)
+	}
+	return result, nil
+}
+
+// consumption sums the article quantities consumed by the product sales of the window, articles unknown
+// to the warehouse are skipped. The IDs of the sold products whose BOM cannot be normalized are returned,
+// their sales are not counted.
+func (srv *impl) consumption(ctx context.Context, arts []models.Article, windowDays int32) (map[int32]int64, []int32, error) {
+	sold, err := srv.salesRepo.GetProductSales(ctx, srv.now().AddDate(0, 0, -int(windowDays)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get product sales: %w", err)
+	}
+	consumed := make(map[int32]int64)
+	if len(sold) == 0 {
+		return consumed, nil, nil
+	}
+
+	prods, err := srv.productsRepo.GetProducts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	conv, err := uom.LoadConverter(ctx, srv.unitsRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	articlesByID := make(map[int32]models.Article, len(arts))
+	for _, article := range arts {
+		articlesByID[article.ID] = article
+	}
+	boms := make(map[int32][]models.ProductArticle, len(prods))
+	var skipped []int32
+	for _, prod := range prods {
+		bom, err := normalizeBOM(conv, articlesByID, prod.Articles)
+		if err != nil {
+			skipped = append(skipped, prod.ID)
+			continue
+		}
+		boms[prod.ID] = bom
+	}
+
+	var skippedSold []int32
+	for _, sale := range sold {
+		if slices.Contains(skipped, sale.ProductID) {
+			if !slices.Contains(skippedSold, sale.ProductID) {
+				skippedSold = append(skippedSold, sale.ProductID)
+			}
+			continue
+		}
+		for _, line := range boms[sale.ProductID] {
+			consumed[line.ID] += int64(line.Quantity) * int64(sale.Quantity)
+		}
+	}
+	slices.Sort(skippedSold)
+	return consumed, skippedSold, nil
+}
+
+// normalizeBOM converts the BOM lines to article units, lines of unknown articles are left out
+func normalizeBOM(conv *uom.Converter, articlesByID map[int32]models.Article, lines []models.ProductArticle) ([]models.ProductArticle, error) {
+	bom := make([]models.ProductArticle, 0, len(lines))
+	for _, line := range lines {
+		article, ok := articlesByID[line.ID]
+		if !ok {
+			continue
+		}
+		required, err := conv.Normalize(article, line)
+		if err != nil {
+			return nil, err
+		}
+		bom = append(bom, required)
+	}
+	return bom, nil
+}
@@ -0,0 +1,215 @@
+package replenishment
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/products/mock"
+	"warehouse/internal/repositories/purchaseorders/mock"
+	"warehouse/internal/repositories/sales/mock"
+	"warehouse/internal/repositories/suppliers/mock"
+	"warehouse/internal/repositories/units/mock"
+)
+
+func TestImpl_SuggestReplenishment(t *testing.T) {
+	arts := []models.Article{
+		{ID: 1, Name: "leg", Stock: 26, Reserved: 6, Unit: "pcs", ReorderQuantity: 50},
+		{ID: 2, Name: "rail", Stock: 500, Unit: "cm"},
+		{ID: 3, Name: "screw", Stock: 1000, Unit: "pcs"},
+	}
+	prods := []models.Product{
+		{ID: 1, Articles: []models.ProductArticle{{ID: 1, Quantity: 4}, {ID: 2, Quantity: 1, Unit: "m"}, {ID: 9, Quantity: 1}}},
+	}
+	source := models.SupplierArticle{
+		SupplierID:   7,
+		ArticleID:    1,
+		SKU:          "LEG-1",
+		Price:        models.Money{Amount: 250, Currency: "EUR"},
+		LeadTimeDays: 5,
+	}
+
+	t.Run("should suggest articles below reorder level", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(arts, nil)
+		// 30 units sold in 10 days, 12 legs and 300 cm of rail a day
+		fx.salesRepo.EXPECT().GetProductSales(fx.ctx, fx.now.AddDate(0, 0, -10)).
+			Return([]models.ProductSale{{ProductID: 1, Quantity: 30}}, nil)
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(prods, nil)
+		fx.expectUnits()
+		fx.purchaseOrdersRepo.EXPECT().GetOnOrder(fx.ctx).Return([]models.ProductArticle{{ID: 2, Quantity: 50}}, nil)
+		fx.suppliersRepo.EXPECT().GetArticleSources(fx.ctx).Return([]models.SupplierArticle{
+			source,
+			{SupplierID: 8, ArticleID: 1, LeadTimeDays: 9},
+		}, nil)
+
+		result, err := fx.SuggestReplenishment(fx.ctx, models.ReplenishmentPolicy{WindowDays: 10, SafetyStockDays: 2})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.SkippedProductIDs)
+		assert.Equal(t, []models.ReplenishmentSuggestion{
+			{
+				ArticleID:        1,
+				Name:             "leg",
+				Unit:             "pcs",
+				Stock:            20,
+				DailyConsumption: 12,
+				LeadTimeDays:     5,
+				SafetyStock:      24,
+				ReorderLevel:     84,
+				Quantity:         64,
+				Source:           &source,
+			},
+			{
+				ArticleID:        2,
+				Name:             "rail",
+				Unit:             "cm",
+				Stock:            500,
+				OnOrder:          50,
+				DailyConsumption: 300,
+				SafetyStock:      600,
+				ReorderLevel:     600,
+				Quantity:         50,
+			},
+		}, result.Suggestions)
+	})
+
+	t.Run("should use the reorder quantity as the minimum", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(arts[:1], nil)
+		fx.salesRepo.EXPECT().GetProductSales(fx.ctx, fx.now.AddDate(0, 0, -30)).
+			Return([]models.ProductSale{{ProductID: 1, Quantity: 30}}, nil)
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(prods, nil)
+		fx.expectUnits()
+		fx.purchaseOrdersRepo.EXPECT().GetOnOrder(fx.ctx).Return(nil, nil)
+		fx.suppliersRepo.EXPECT().GetArticleSources(fx.ctx).Return([]models.SupplierArticle{source}, nil)
+
+		result, err := fx.SuggestReplenishment(fx.ctx, models.ReplenishmentPolicy{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Suggestions, 1)
+		assert.Equal(t, int32(48), result.Suggestions[0].ReorderLevel)
+		assert.Equal(t, int32(50), result.Suggestions[0].Quantity)
+	})
+
+	t.Run("should skip and report products with incompatible units", func(t *testing.T) {
+		fx := newFixture(t)
+
+		prods := append(prods, models.Product{
+			ID:       2,
+			Articles: []models.ProductArticle{{ID: 1, Quantity: 1}, {ID: 3, Quantity: 1, Unit: "m"}},
+		})
+		fx.articlesRepo.EXPECT().GetArticles(fx.ctx).Return(arts, nil)
+		fx.salesRepo.EXPECT().GetProductSales(fx.ctx, fx.now.AddDate(0, 0, -10)).
+			Return([]models.ProductSale{{ProductID: 2, Quantity: 100}, {ProductID: 1, Quantity: 30}, {ProductID: 2, Quantity: 5}}, nil)
+		fx.productsRepo.EXPECT().GetProducts(fx.ctx).Return(prods, nil)
+		fx.expectUnits()
+		fx.purchaseOrdersRepo.EXPECT().GetOnOrder(fx.ctx).Return(nil, nil)
+		fx.suppliersRepo.EXPECT().GetArticleSources(fx.ctx).Return(nil, nil)
+
+		result, err := fx.SuggestReplenishment(fx.ctx, models.ReplenishmentPolicy{WindowDays: 10, SafetyStockDays: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int32{2}, result.SkippedProductIDs)
+		require.Len(t, result.Suggestions, 2)
+		assert.Equal(t, float64(12), result.Suggestions[0].DailyConsumption)
+	})
+
+	t.Run("should reject negative policy", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.SuggestReplenishment(fx.ctx, models.ReplenishmentPolicy{SafetyStockDays: -1})
+
+		require.ErrorIs(t, err, ErrInvalidPolicy)
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	suggestions := []models.ReplenishmentSuggestion{
+		{ArticleID: 2, Name: "rail", Unit: "cm", Quantity: 100},
+		{
+			ArticleID:    1,
+			Name:         "leg",
+			Unit:         "pcs",
+			Quantity:     64,
+			LeadTimeDays: 5,
+			Source: &models.SupplierArticle{
+				SupplierID:   7,
+				SKU:          "LEG-1",
+				Price:        models.Money{Amount: 250, Currency: "EUR"},
+				LeadTimeDays: 5,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, suggestions)
+
+	require.NoError(t, err)
+	assert.Equal(t, "supplier_id,sku,article_id,name,quantity,unit,unit_price,total,currency,lead_time_days\n"+
+		"7,LEG-1,1,leg,64,pcs,2.50,160.00,EUR,5\n"+
+		",,2,rail,100,cm,,,,0\n", buf.String())
+}
+
+type fixture struct {
+	*impl
+
+	t                  *testing.T
+	ctx                context.Context
+	now                time.Time
+	articlesRepo       *mockArticlesRepo.MockRepository
+	productsRepo       *mockProductsRepo.MockRepository
+	salesRepo          *mockSalesRepo.MockRepository
+	suppliersRepo      *mockSuppliersRepo.MockRepository
+	purchaseOrdersRepo *mockPurchaseOrdersRepo.MockRepository
+	unitsRepo          *mockUnitsRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:                  t,
+		ctx:                ctx,
+		now:                time.Now(),
+		articlesRepo:       mockArticlesRepo.NewMockRepository(ctrl),
+		productsRepo:       mockProductsRepo.NewMockRepository(ctrl),
+		salesRepo:          mockSalesRepo.NewMockRepository(ctrl),
+		suppliersRepo:      mockSuppliersRepo.NewMockRepository(ctrl),
+		purchaseOrdersRepo: mockPurchaseOrdersRepo.NewMockRepository(ctrl),
+		unitsRepo:          mockUnitsRepo.NewMockRepository(ctrl),
+	}
+	fx.impl = NewService(
+		Config{},
+		fx.articlesRepo,
+		fx.productsRepo,
+		fx.salesRepo,
+		fx.suppliersRepo,
+		fx.purchaseOrdersRepo,
+		fx.unitsRepo,
+	).(*impl)
+	fx.impl.now = func() time.Time {
+		return fx.now
+	}
+	return fx
+}
+
+func (fx *fixture) expectUnits() {
+	units := []models.Unit{
+		{Code: "pcs", Dimension: "count", Factor: 1},
+		{Code: "cm", Dimension: "length", Factor: 10},
+		{Code: "m", Dimension: "length", Factor: 1000},
+	}
+	fx.unitsRepo.EXPECT().GetUnits(fx.ctx).Return(units, nil)
+	fx.unitsRepo.EXPECT().GetArticleUnits(fx.ctx).Return(nil, nil)
+}
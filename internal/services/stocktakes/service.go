@@ -0,0 +1,230 @@
+package stocktakes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/stocktakes"
+)
+
+var (
+	ErrEmptyStocktake        = errors.New("stocktake requires at least one article")
+	ErrSerializedArticle     = errors.New("serialized articles cannot be counted in a stocktake")
+	ErrInvalidStatus         = errors.New("operation is not allowed in the stocktake status")
+	ErrInvalidCounter        = errors.New("counter is required")
+	ErrInvalidQuantity       = errors.New("counted quantity must not be negative")
+	ErrArticleNotInStocktake = errors.New("article is not part of the stocktake")
+)
+
+type Service interface {
+	OpenStocktake(ctx context.Context, articleIDs []int32) (models.Stocktake, error)
+	GetStocktake(ctx context.Context, id int32) (models.Stocktake, error)
+	SubmitCounts(ctx context.Context, id int32, counter string, counts []models.StocktakeCount) (models.Stocktake, error)
+	ApproveStocktake(ctx context.Context, id int32) (models.Stocktake, error)
+	CancelStocktake(ctx context.Context, id int32) (models.Stocktake, error)
+}
+
+type impl struct {
+	tx             db.Transactor
+	articlesRepo   articles.Repository
+	stocktakesRepo stocktakes.Repository
+}
+
+func NewService(tx db.Transactor, aRepo articles.Repository, sRepo stocktakes.Repository) Service {
+	return &impl{
+		tx:             tx,
+		articlesRepo:   aRepo,
+		stocktakesRepo: sRepo,
+	}
+}
+
+// OpenStocktake starts a count of the articles, the current stock is frozen as the expected quantity
+func (srv *impl) OpenStocktake(ctx context.Context, articleIDs []int32) (models.Stocktake, error) {
+	if len(articleIDs) == 0 {
+		return models.Stocktake{}, ErrEmptyStocktake
+	}
+	ids := slices.Clone(articleIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var res models.Stocktake
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		item := models.Stocktake{Status: models.StocktakeOpen}
+		for _, id := range ids {
+			article, err := srv.articlesRepo.GetArticle(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get article %d: %w", id, err)
+			}
+			if article.Serialized {
+				return fmt.Errorf("%w: article %d", ErrSerializedArticle, id)
+			}
+			item.Articles = append(item.Articles, models.StocktakeArticle{
+				ArticleID: id,
+				Expected:  article.Stock,
+				Stock:     article.Stock,
+			})
+		}
+
+		var err error
+		res, err = srv.stocktakesRepo.CreateStocktake(ctx, item)
+		if err != nil {
+			return fmt.Errorf("failed to create stocktake: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	return res, nil
+}
+
+// GetStocktake returns the stocktake with the counts and current stock of every article to review the variance
+func (srv *impl) GetStocktake(ctx context.Context, id int32) (models.Stocktake, error) {
+	item, err := srv.stocktakesRepo.GetStocktake(ctx, id)
+	if err != nil {
+		return models.Stocktake{}, fmt.Errorf("failed to get stocktake: %w", err)
+	}
+	return item, nil
+}
+
+// SubmitCounts records the quantities counted by the counter, replacing its previous counts of the same articles
+func (srv *impl) SubmitCounts(
+	ctx context.Context,
+	id int32,
+	counter string,
+	counts []models.StocktakeCount,
+) (models.Stocktake, error) {
+	if counter == "" {
+		return models.Stocktake{}, ErrInvalidCounter
+	}
+
+	var res models.Stocktake
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		item, err := srv.stocktakesRepo.GetStocktakeForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get stocktake: %w", err)
+		}
+		if item.Status != models.StocktakeOpen {
+			return fmt.Errorf("%w: %s", ErrInvalidStatus, item.Status)
+		}
+
+		items := make([]models.StocktakeCount, 0, len(counts))
+		for _, count := range counts {
+			if count.Quantity < 0 {
+				return fmt.Errorf("%w: article %d", ErrInvalidQuantity, count.ArticleID)
+			}
+			included := slices.ContainsFunc(item.Articles, func(a models.StocktakeArticle) bool {
+				return a.ArticleID == count.ArticleID
+			})
+			if !included {
+				return fmt.Errorf("%w: article %d", ErrArticleNotInStocktake, count.ArticleID)
+			}
+			count.Counter = counter
+			items = append(items, count)
+		}
+
+		err = srv.stocktakesRepo.SaveCounts(ctx, id, items)
+		if err != nil {
+			return fmt.Errorf("failed to save counts: %w", err)
+		}
+		res, err = srv.stocktakesRepo.GetStocktake(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get stocktake: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	return res, nil
+}
+
+// ApproveStocktake posts the variance of every counted article as a stock adjustment.
+// The variance is applied to the current stock, so stock received or removed after the last count is kept.
+// Articles nobody counted are left unchanged.
+func (srv *impl) ApproveStocktake(ctx context.Context, id int32) (models.Stocktake, error) {
+	var res models.Stocktake
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		item, err := srv.stocktakesRepo.GetStocktakeForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get stocktake: %w", err)
+		}
+		if item.Status != models.StocktakeOpen {
+			return fmt.Errorf("%w: %s", ErrInvalidStatus, item.Status)
+		}
+
+		var added, removed []models.ProductArticle
+		for _, article := range item.Articles {
+			variance := article.Variance()
+			switch {
+			case variance > 0:
+				added = append(added, models.ProductArticle{ID: article.ArticleID, Quantity: variance})
+			case variance < 0:
+				removed = append(removed, models.ProductArticle{ID: article.ArticleID, Quantity: -variance})
+			default:
+				continue
+			}
+			err = srv.stocktakesRepo.SetAdjustment(ctx, id, article.ArticleID, variance)
+			if err != nil {
+				return fmt.Errorf("failed to set adjustment of article %d: %w", article.ArticleID, err)
+			}
+		}
+		if len(added) > 0 {
+			err = srv.articlesRepo.AddArticles(ctx, added)
+			if err != nil {
+				return fmt.Errorf("failed to add articles: %w", err)
+			}
+		}
+		if len(removed) > 0 {
+			err = srv.articlesRepo.RemoveArticles(ctx, removed)
+			if err != nil {
+				return fmt.Errorf("failed to remove articles: %w", err)
+			}
+		}
+
+		res, err = srv.close(ctx, id, models.StocktakeApproved)
+		return err
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	return res, nil
+}
+
+// CancelStocktake closes an open stocktake without adjusting stock
+func (srv *impl) CancelStocktake(ctx context.Context, id int32) (models.Stocktake, error) {
+	var res models.Stocktake
+	err := srv.tx.InTx(ctx, func(ctx context.Context) error {
+		item, err := srv.stocktakesRepo.GetStocktakeForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get stocktake: %w", err)
+		}
+		if item.Status != models.StocktakeOpen {
+			return fmt.Errorf("%w: %s", ErrInvalidStatus, item.Status)
+		}
+
+		res, err = srv.close(ctx, id, models.StocktakeCancelled)
+		return err
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+	return res, nil
+}
+
+func (srv *impl) close(ctx context.Context, id int32, status models.StocktakeStatus) (models.Stocktake, error) {
+	err := srv.stocktakesRepo.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return models.Stocktake{}, fmt.Errorf("failed to update stocktake status: %w", err)
+	}
+	item, err := srv.stocktakesRepo.GetStocktake(ctx, id)
+	if err != nil {
+		return models.Stocktake{}, fmt.Errorf("failed to get stocktake: %w", err)
+	}
+	return item, nil
+}
@@ -0,0 +1,233 @@
+package stocktakes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/db/mock"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/stocktakes/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_OpenStocktake(t *testing.T) {
+	t.Run("should freeze current stock", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(1)).Return(models.Article{ID: 1, Stock: 4}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(2)).Return(models.Article{ID: 2, Stock: 9}, nil)
+		expected := models.Stocktake{
+			Status: models.StocktakeOpen,
+			Articles: []models.StocktakeArticle{
+				{ArticleID: 1, Expected: 4, Stock: 4},
+				{ArticleID: 2, Expected: 9, Stock: 9},
+			},
+		}
+		fx.stocktakesRepo.EXPECT().CreateStocktake(fx.ctx, expected).Return(expected, nil)
+
+		item, err := fx.OpenStocktake(fx.ctx, []int32{2, 1, 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, item)
+	})
+
+	t.Run("should reject serialized articles", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, int32(1)).Return(models.Article{ID: 1, Serialized: true}, nil)
+
+		_, err := fx.OpenStocktake(fx.ctx, []int32{1})
+
+		require.ErrorIs(t, err, ErrSerializedArticle)
+	})
+
+	t.Run("should reject empty stocktake", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.OpenStocktake(fx.ctx, nil)
+
+		require.ErrorIs(t, err, ErrEmptyStocktake)
+	})
+}
+
+func TestImpl_SubmitCounts(t *testing.T) {
+	id := testhelpers.RandomInt32()
+	open := models.Stocktake{
+		ID:       id,
+		Status:   models.StocktakeOpen,
+		Articles: []models.StocktakeArticle{{ArticleID: 1, Expected: 4}},
+	}
+
+	t.Run("should save counts of the counter", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(open, nil)
+		fx.stocktakesRepo.EXPECT().SaveCounts(fx.ctx, id, []models.StocktakeCount{
+			{ArticleID: 1, Counter: "alice", Quantity: 3},
+		}).Return(nil)
+		fx.stocktakesRepo.EXPECT().GetStocktake(fx.ctx, id).Return(open, nil)
+
+		_, err := fx.SubmitCounts(fx.ctx, id, "alice", []models.StocktakeCount{{ArticleID: 1, Quantity: 3}})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject articles not in the stocktake", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(open, nil)
+
+		_, err := fx.SubmitCounts(fx.ctx, id, "alice", []models.StocktakeCount{{ArticleID: 2, Quantity: 3}})
+
+		require.ErrorIs(t, err, ErrArticleNotInStocktake)
+	})
+
+	t.Run("should reject negative quantity", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(open, nil)
+
+		_, err := fx.SubmitCounts(fx.ctx, id, "alice", []models.StocktakeCount{{ArticleID: 1, Quantity: -1}})
+
+		require.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+
+	t.Run("should reject closed stocktake", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(models.Stocktake{
+			ID:     id,
+			Status: models.StocktakeApproved,
+		}, nil)
+
+		_, err := fx.SubmitCounts(fx.ctx, id, "alice", []models.StocktakeCount{{ArticleID: 1, Quantity: 3}})
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+
+	t.Run("should require counter", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.SubmitCounts(fx.ctx, id, "", nil)
+
+		require.ErrorIs(t, err, ErrInvalidCounter)
+	})
+}
+
+func TestImpl_ApproveStocktake(t *testing.T) {
+	id := testhelpers.RandomInt32()
+
+	t.Run("should post variance on top of current stock", func(t *testing.T) {
+		fx := newFixture(t)
+		now := time.Now()
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(models.Stocktake{
+			ID:     id,
+			Status: models.StocktakeOpen,
+			Articles: []models.StocktakeArticle{
+				// 2 units were received between the counts, 8 counted against 6 at the last count
+				{ArticleID: 1, Expected: 4, Stock: 6, Counts: []models.StocktakeCount{
+					{ArticleID: 1, Counter: "alice", Quantity: 4, Stock: 6, CountedAt: now},
+					{ArticleID: 1, Counter: "bob", Quantity: 4, Stock: 4, CountedAt: now.Add(-time.Hour)},
+				}},
+				// 10 units were sold before the count, 90 counted against 90 is no variance
+				{ArticleID: 2, Expected: 100, Stock: 90, Counts: []models.StocktakeCount{
+					{ArticleID: 2, Counter: "alice", Quantity: 90, Stock: 90, CountedAt: now},
+				}},
+				{ArticleID: 3, Expected: 9, Stock: 9, Counts: []models.StocktakeCount{
+					{ArticleID: 3, Counter: "alice", Quantity: 7, Stock: 9, CountedAt: now},
+				}},
+				{ArticleID: 4, Expected: 1, Stock: 1},
+			},
+		}, nil)
+		fx.stocktakesRepo.EXPECT().SetAdjustment(fx.ctx, id, int32(1), int32(2)).Return(nil)
+		fx.stocktakesRepo.EXPECT().SetAdjustment(fx.ctx, id, int32(3), int32(-2)).Return(nil)
+		fx.articlesRepo.EXPECT().AddArticles(fx.ctx, []models.ProductArticle{{ID: 1, Quantity: 2}}).Return(nil)
+		fx.articlesRepo.EXPECT().RemoveArticles(fx.ctx, []models.ProductArticle{{ID: 3, Quantity: 2}}).Return(nil)
+		fx.stocktakesRepo.EXPECT().UpdateStatus(fx.ctx, id, models.StocktakeApproved).Return(nil)
+		fx.stocktakesRepo.EXPECT().GetStocktake(fx.ctx, id).Return(models.Stocktake{ID: id, Status: models.StocktakeApproved}, nil)
+
+		item, err := fx.ApproveStocktake(fx.ctx, id)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.StocktakeApproved, item.Status)
+	})
+
+	t.Run("should reject closed stocktake", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(models.Stocktake{
+			ID:     id,
+			Status: models.StocktakeCancelled,
+		}, nil)
+
+		_, err := fx.ApproveStocktake(fx.ctx, id)
+
+		require.ErrorIs(t, err, ErrInvalidStatus)
+	})
+}
+
+func TestImpl_CancelStocktake(t *testing.T) {
+	t.Run("should cancel without adjusting stock", func(t *testing.T) {
+		fx := newFixture(t)
+		id := testhelpers.RandomInt32()
+
+		fx.expectTx()
+		fx.stocktakesRepo.EXPECT().GetStocktakeForUpdate(fx.ctx, id).Return(models.Stocktake{
+			ID:     id,
+			Status: models.StocktakeOpen,
+		}, nil)
+		fx.stocktakesRepo.EXPECT().UpdateStatus(fx.ctx, id, models.StocktakeCancelled).Return(nil)
+		fx.stocktakesRepo.EXPECT().GetStocktake(fx.ctx, id).Return(models.Stocktake{ID: id, Status: models.StocktakeCancelled}, nil)
+
+		item, err := fx.CancelStocktake(fx.ctx, id)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.StocktakeCancelled, item.Status)
+	})
+}
+
+type fixture struct {
+	Service
+
+	t              *testing.T
+	ctx            context.Context
+	tx             *mockDB.MockTransactor
+	articlesRepo   *mockArticlesRepo.MockRepository
+	stocktakesRepo *mockStocktakesRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:              t,
+		ctx:            ctx,
+		tx:             mockDB.NewMockTransactor(ctrl),
+		articlesRepo:   mockArticlesRepo.NewMockRepository(ctrl),
+		stocktakesRepo: mockStocktakesRepo.NewMockRepository(ctrl),
+	}
+	fx.Service = NewService(fx.tx, fx.articlesRepo, fx.stocktakesRepo)
+	return fx
+}
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
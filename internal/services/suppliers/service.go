@@ -0,0 +1,90 @@
+package suppliers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/suppliers"
+)
+
+var (
+	ErrInvalidName  = errors.New("supplier name must not be empty")
+	ErrInvalidTerms = errors.New("supplier article requires a SKU, a price and a non-negative lead time")
+)
+
+type Service interface {
+	CreateSupplier(ctx context.Context, name string) (models.Supplier, error)
+	GetSuppliers(ctx context.Context) ([]models.Supplier, error)
+	SetSupplierArticle(ctx context.Context, item models.SupplierArticle) error
+	GetSupplierArticles(ctx context.Context, supplierID int32) ([]models.SupplierArticle, error)
+}
+
+type impl struct {
+	articlesRepo  articles.Repository
+	suppliersRepo suppliers.Repository
+}
+
+func NewService(aRepo articles.Repository, supRepo suppliers.Repository) Service {
+	return &impl{
+		articlesRepo:  aRepo,
+		suppliersRepo: supRepo,
+	}
+}
+
+func (srv *impl) CreateSupplier(ctx context.Context, name string) (models.Supplier, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Supplier{}, ErrInvalidName
+	}
+	supplier, err := srv.suppliersRepo.CreateSupplier(ctx, models.Supplier{Name: name})
+	if err != nil {
+		return models.Supplier{}, fmt.Errorf("failed to create supplier: %w", err)
+	}
+	return supplier, nil
+}
+
+func (srv *impl) GetSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	items, err := srv.suppliersRepo.GetSuppliers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppliers: %w", err)
+	}
+	return items, nil
+}
+
+// SetSupplierArticle sets the SKU, price and lead time at which the supplier sells the article
+func (srv *impl) SetSupplierArticle(ctx context.Context, item models.SupplierArticle) error {
+	if item.SKU == "" || item.Price.Currency == "" || item.Price.Amount < 0 || item.LeadTimeDays < 0 {
+		return ErrInvalidTerms
+	}
+
+	_, err := srv.suppliersRepo.GetSupplier(ctx, item.SupplierID)
+	if err != nil {
+		return fmt.Errorf("failed to get supplier: %w", err)
+	}
+	_, err = srv.articlesRepo.GetArticle(ctx, item.ArticleID)
+	if err != nil {
+		return fmt.Errorf("failed to get article: %w", err)
+	}
+
+	err = srv.suppliersRepo.SetSupplierArticle(ctx, item)
+	if err != nil {
+		return fmt.Errorf("failed to set supplier article: %w", err)
+	}
+	return nil
+}
+
+func (srv *impl) GetSupplierArticles(ctx context.Context, supplierID int32) ([]models.SupplierArticle, error) {
+	_, err := srv.suppliersRepo.GetSupplier(ctx, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+	items, err := srv.suppliersRepo.GetSupplierArticles(ctx, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier articles: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,91 @@
+package suppliers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/articles"
+	"warehouse/internal/repositories/articles/mock"
+	"warehouse/internal/repositories/suppliers/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateSupplier(t *testing.T) {
+	t.Run("should reject empty name", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.CreateSupplier(fx.ctx, "  ")
+
+		require.ErrorIs(t, err, ErrInvalidName)
+	})
+}
+
+func TestImpl_SetSupplierArticle(t *testing.T) {
+	item := models.SupplierArticle{
+		SupplierID:   testhelpers.RandomInt32(),
+		ArticleID:    testhelpers.RandomInt32(),
+		SKU:          testhelpers.RandomString(),
+		Price:        models.Money{Amount: 120, Currency: "EUR"},
+		LeadTimeDays: 14,
+	}
+
+	t.Run("should set supplier article", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.suppliersRepo.EXPECT().GetSupplier(fx.ctx, item.SupplierID).Return(models.Supplier{ID: item.SupplierID}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, item.ArticleID).Return(models.Article{ID: item.ArticleID}, nil)
+		fx.suppliersRepo.EXPECT().SetSupplierArticle(fx.ctx, item).Return(nil)
+
+		err := fx.SetSupplierArticle(fx.ctx, item)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject unknown article", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.suppliersRepo.EXPECT().GetSupplier(fx.ctx, item.SupplierID).Return(models.Supplier{ID: item.SupplierID}, nil)
+		fx.articlesRepo.EXPECT().GetArticle(fx.ctx, item.ArticleID).Return(models.Article{}, articles.ErrNotFound)
+
+		err := fx.SetSupplierArticle(fx.ctx, item)
+
+		require.ErrorIs(t, err, articles.ErrNotFound)
+	})
+
+	t.Run("should reject negative lead time", func(t *testing.T) {
+		fx := newFixture(t)
+
+		invalid := item
+		invalid.LeadTimeDays = -1
+		err := fx.SetSupplierArticle(fx.ctx, invalid)
+
+		require.ErrorIs(t, err, ErrInvalidTerms)
+	})
+}
+
+type fixture struct {
+	Service
+
+	t             *testing.T
+	ctx           context.Context
+	articlesRepo  *mockArticlesRepo.MockRepository
+	suppliersRepo *mockSuppliersRepo.MockRepository
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:             t,
+		ctx:           ctx,
+		articlesRepo:  mockArticlesRepo.NewMockRepository(ctrl),
+		suppliersRepo: mockSuppliersRepo.NewMockRepository(ctrl),
+	}
+	fx.Service = NewService(fx.articlesRepo, fx.suppliersRepo)
+	return fx
+}
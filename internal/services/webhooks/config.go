@@ -0,0 +1,22 @@
+package webhooks
+
+import "time"
+
+const (
+	defaultBatchSize     = 100
+	defaultMaxAttempts   = 10
+	defaultRetryDelay    = time.Second
+	defaultMaxRetryDelay = time.Hour
+	defaultTimeout       = 5 * time.Second
+)
+
+type Config struct {
+	// PollInterval is the delay between dispatcher runs when there are no pending deliveries
+	PollInterval time.Duration `koanf:"poll_interval"`
+	BatchSize    int           `koanf:"batch_size"`
+	// MaxAttempts is the number of failed attempts after which a delivery becomes a dead letter
+	MaxAttempts   int32         `koanf:"max_attempts"`
+	RetryDelay    time.Duration `koanf:"retry_delay"`
+	MaxRetryDelay time.Duration `koanf:"max_retry_delay"`
+	Timeout       time.Duration
+}
@@ -0,0 +1,282 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"warehouse/internal/db"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/webhooks"
+	"warehouse/internal/services/products"
+)
+
+var (
+	ErrInvalidURL       = errors.New("webhook url must be an absolute http or https url")
+	ErrSecretRequired   = errors.New("webhook secret is required")
+	ErrInvalidEventType = errors.New("unknown webhook event type")
+)
+
+type Service interface {
+	CreateSubscription(ctx context.Context, item models.WebhookSubscription) (models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int32) error
+	GetDeadLetters(ctx context.Context, subscriptionID int32) ([]models.WebhookDelivery, error)
+	ReplayDeliveries(ctx context.Context, subscriptionID int32, ids []int64) (int, error)
+	// Evaluate queues deliveries for the products which went in or out of stock since the last evaluation
+	Evaluate(ctx context.Context) error
+	// Dispatch delivers a batch of pending deliveries and returns the number of deliveries processed
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type impl struct {
+	cfg          Config
+	tx           db.Transactor
+	webhooksRepo webhooks.Repository
+	productsSrv  products.Service
+	client       *http.Client
+	now          func() time.Time
+}
+
+func NewService(cfg Config, tx db.Transactor, wRepo webhooks.Repository, productsSrv products.Service) Service {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = defaultMaxRetryDelay
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &impl{
+		cfg:          cfg,
+		tx:           tx,
+		webhooksRepo: wRepo,
+		productsSrv:  productsSrv,
+		client:       &http.Client{Timeout: cfg.Timeout},
+		now:          time.Now,
+	}
+}
+
+func (srv *impl) CreateSubscription(ctx context.Context, item models.WebhookSubscription) (models.WebhookSubscription, error) {
+	u, err := url.Parse(item.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.WebhookSubscription{}, ErrInvalidURL
+	}
+	if item.Secret == "" {
+		return models.WebhookSubscription{}, ErrSecretRequired
+	}
+	for _, eventType := range item.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, eventType) {
+			return models.WebhookSubscription{}, fmt.Errorf("%w: %q", ErrInvalidEventType, eventType)
+		}
+	}
+	return srv.webhooksRepo.CreateSubscription(ctx, item)
+}
+
+func (srv *impl) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return srv.webhooksRepo.GetSubscriptions(ctx)
+}
+
+func (srv *impl) DeleteSubscription(ctx context.Context, id int32) error {
+	return srv.webhooksRepo.DeleteSubscription(ctx, id)
+}
+
+func (srv *impl) GetDeadLetters(ctx context.Context, subscriptionID int32) ([]models.WebhookDelivery, error) {
+	_, err := srv.webhooksRepo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return srv.webhooksRepo.GetDeadDeliveries(ctx, subscriptionID)
+}
+
+// ReplayDeliveries queues the deliveries of the subscription again, delivered deliveries included,
+// all dead letters of the subscription if no IDs are given
+func (srv *impl) ReplayDeliveries(ctx context.Context, subscriptionID int32, ids []int64) (int, error) {
+	_, err := srv.webhooksRepo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	return srv.webhooksRepo.ReplayDeliveries(ctx, subscriptionID, ids)
+}
+
+// payload is the JSON body of a delivery
+type payload struct {
+	Type       string         `json:"type"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Product    productPayload `json:"product"`
+}
+
+type productPayload struct {
+	ID    int32  `json:"id"`
+	Name  string `json:"name"`
+	Stock int32  `json:"stock"`
+}
+
+// Evaluate compares the product stock with the stored states. The states are locked before the stock is
+// calculated in the same transaction, so that concurrent evaluations, e.g. of other server instances, never
+// store a stock older than the one stored before.
+func (srv *impl) Evaluate(ctx context.Context) error {
+	return srv.tx.InTx(ctx, func(ctx context.Context) error {
+		err := srv.webhooksRepo.LockStockStates(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to lock stock states: %w", err)
+		}
+		items, err := srv.productsSrv.GetProductsWithStock(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get products: %w", err)
+		}
+		inStock := make(map[int32]bool, len(items))
+		byID := make(map[int32]models.ProductWithStock, len(items))
+		for _, item := range items {
+			inStock[item.ID] = item.Stock > 0
+			byID[item.ID] = item
+		}
+
+		changed, err := srv.webhooksRepo.SyncStockStates(ctx, inStock)
+		if err != nil {
+			return fmt.Errorf("failed to sync stock states: %w", err)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		subs, err := srv.webhooksRepo.GetSubscriptions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get subscriptions: %w", err)
+		}
+
+		var deliveries []models.WebhookDelivery
+		now := srv.now()
+		for _, id := range changed {
+			eventType := models.WebhookEventProductOutOfStock
+			if inStock[id] {
+				eventType = models.WebhookEventProductInStock
+			}
+			body, err := json.Marshal(payload{
+				Type:       eventType,
+				OccurredAt: now,
+				Product: productPayload{
+					ID:    id,
+					Name:  byID[id].Name,
+					Stock: byID[id].Stock,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			for _, sub := range subs {
+				if !sub.Matches(eventType, id) {
+					continue
+				}
+				deliveries = append(deliveries, models.WebhookDelivery{
+					SubscriptionID: sub.ID,
+					EventType:      eventType,
+					Payload:        body,
+				})
+			}
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		return srv.webhooksRepo.AddDeliveries(ctx, deliveries)
+	})
+}
+
+// Dispatch posts the pending deliveries to the subscribers. Failed deliveries are retried with an exponential
+// backoff until they failed MaxAttempts times, then they are kept as dead letters until replayed.
+// Deliveries are claimed before they are posted, so no transaction or connection is held during the requests.
+// A claim lasts until the whole batch could have timed out, deliveries of a dispatcher that stopped are
+// posted again once their claim expired.
+func (srv *impl) Dispatch(ctx context.Context) (int, error) {
+	claim := time.Duration(srv.cfg.BatchSize) * srv.cfg.Timeout
+	items, err := srv.webhooksRepo.ClaimDeliveries(ctx, srv.cfg.BatchSize, srv.now().Add(claim))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim pending deliveries: %w", err)
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	subs, err := srv.webhooksRepo.GetSubscriptions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	byID := make(map[int32]models.WebhookSubscription, len(subs))
+	for _, sub := range subs {
+		byID[sub.ID] = sub
+	}
+
+	delivered := make([]int64, 0, len(items))
+	for _, item := range items {
+		sub, ok := byID[item.SubscriptionID]
+		if !ok {
+			// the subscription was deleted after the claim, its deliveries are deleted with it
+			continue
+		}
+		err = srv.post(ctx, sub, item)
+		if err == nil {
+			delivered = append(delivered, item.ID)
+			continue
+		}
+		if item.Attempts+1 >= srv.cfg.MaxAttempts {
+			err = srv.webhooksRepo.MarkDead(ctx, item.ID, err.Error())
+		} else {
+			err = srv.webhooksRepo.MarkFailed(ctx, item.ID, err.Error(), srv.now().Add(srv.backoff(item.Attempts)))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark delivery %d failed: %w", item.ID, err)
+		}
+	}
+	if len(delivered) > 0 {
+		err = srv.webhooksRepo.MarkDelivered(ctx, delivered)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark deliveries delivered: %w", err)
+		}
+	}
+	return len(items), nil
+}
+
+// post sends the signed payload, any status other than 2xx fails the delivery
+func (srv *impl) post(ctx context.Context, sub models.WebhookSubscription, item models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(item.Payload))
+	if err != nil {
+		return err
+	}
+	timestamp := srv.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", strconv.FormatInt(item.ID, 10))
+	req.Header.Set("X-Webhook-Event", item.EventType)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(sub.Secret, timestamp, item.Payload))
+
+	resp, err := srv.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// backoff doubles the retry delay with every failed attempt up to the max retry delay
+func (srv *impl) backoff(attempts int32) time.Duration {
+	delay := srv.cfg.RetryDelay
+	for i := int32(0); i < attempts && delay < srv.cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, srv.cfg.MaxRetryDelay)
+}
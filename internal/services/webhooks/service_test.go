@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"warehouse/internal/db/mock"
+	"warehouse/internal/models"
+	"warehouse/internal/repositories/webhooks/mock"
+	"warehouse/internal/services/products/mock"
+	"warehouse/internal/testhelpers"
+)
+
+func TestImpl_CreateSubscription(t *testing.T) {
+	t.Run("should create subscription", func(t *testing.T) {
+		fx := newFixture(t)
+
+		item := models.WebhookSubscription{
+			URL:        "https://example.com/hooks",
+			Secret:     testhelpers.RandomString(),
+			EventTypes: []string{models.WebhookEventProductOutOfStock},
+		}
+		created := item
+		created.ID = 1
+		fx.webhooksRepo.EXPECT().CreateSubscription(fx.ctx, item).Return(created, nil)
+
+		got, err := fx.CreateSubscription(fx.ctx, item)
+
+		require.NoError(t, err)
+		assert.Equal(t, created, got)
+	})
+
+	t.Run("should validate subscription", func(t *testing.T) {
+		fx := newFixture(t)
+
+		_, err := fx.CreateSubscription(fx.ctx, models.WebhookSubscription{URL: "example.com", Secret: "s"})
+		require.ErrorIs(t, err, ErrInvalidURL)
+		_, err = fx.CreateSubscription(fx.ctx, models.WebhookSubscription{URL: "http://example.com"})
+		require.ErrorIs(t, err, ErrSecretRequired)
+		_, err = fx.CreateSubscription(fx.ctx, models.WebhookSubscription{URL: "http://example.com", Secret: "s", EventTypes: []string{"product.sold"}})
+		require.ErrorIs(t, err, ErrInvalidEventType)
+	})
+}
+
+func TestImpl_Evaluate(t *testing.T) {
+	t.Run("should queue deliveries for matching subscriptions", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		gomock.InOrder(
+			fx.webhooksRepo.EXPECT().LockStockStates(fx.ctx).Return(nil),
+			fx.productsSrv.EXPECT().GetProductsWithStock(fx.ctx).Return([]models.ProductWithStock{
+				{Product: models.Product{ID: 1, Name: "chair"}, Stock: 0},
+				{Product: models.Product{ID: 2, Name: "table"}, Stock: 3},
+			}, nil),
+		)
+		fx.webhooksRepo.EXPECT().SyncStockStates(fx.ctx, map[int32]bool{1: false, 2: true}).Return([]int32{1, 2}, nil)
+		fx.webhooksRepo.EXPECT().GetSubscriptions(fx.ctx).Return([]models.WebhookSubscription{
+			{ID: 1},
+			{ID: 2, EventTypes: []string{models.WebhookEventProductOutOfStock}},
+			{ID: 3, ProductIDs: []int32{2}},
+		}, nil)
+		fx.webhooksRepo.EXPECT().AddDeliveries(fx.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, items []models.WebhookDelivery) error {
+			require.Len(t, items, 4)
+			assert.Equal(t, []int32{1, 2, 1, 3}, []int32{
+				items[0].SubscriptionID, items[1].SubscriptionID, items[2].SubscriptionID, items[3].SubscriptionID,
+			})
+			assert.Equal(t, models.WebhookEventProductOutOfStock, items[0].EventType)
+			assert.Equal(t, models.WebhookEventProductInStock, items[2].EventType)
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(items[2].Payload, &got))
+			assert.Equal(t, map[string]any{
+				"type":        "product.in_stock",
+				"occurred_at": fx.now.Format(time.RFC3339Nano),
+				"product":     map[string]any{"id": float64(2), "name": "table", "stock": float64(3)},
+			}, got)
+			return nil
+		})
+
+		err := fx.Evaluate(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not queue anything without changes", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.expectTx()
+		fx.webhooksRepo.EXPECT().LockStockStates(fx.ctx).Return(nil)
+		fx.productsSrv.EXPECT().GetProductsWithStock(fx.ctx).Return(nil, nil)
+		fx.webhooksRepo.EXPECT().SyncStockStates(fx.ctx, map[int32]bool{}).Return(nil, nil)
+
+		err := fx.Evaluate(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if the states cannot be locked", func(t *testing.T) {
+		fx := newFixture(t)
+
+		expectedErr := errors.New(testhelpers.RandomString())
+		fx.expectTx()
+		fx.webhooksRepo.EXPECT().LockStockStates(fx.ctx).Return(expectedErr)
+
+		err := fx.Evaluate(fx.ctx)
+
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestImpl_Dispatch(t *testing.T) {
+	body := []byte(`{"type": "product.out_of_stock"}`)
+	delivery := models.WebhookDelivery{
+		ID:             7,
+		SubscriptionID: 1,
+		EventType:      models.WebhookEventProductOutOfStock,
+		Payload:        body,
+	}
+
+	t.Run("should post signed payload", func(t *testing.T) {
+		fx := newFixture(t)
+
+		secret := testhelpers.RandomString()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, got)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "7", r.Header.Get("X-Webhook-Id"))
+			assert.Equal(t, models.WebhookEventProductOutOfStock, r.Header.Get("X-Webhook-Event"))
+			timestamp, err := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, fx.now.Unix(), timestamp)
+			assert.True(t, Verify(secret, timestamp, got, r.Header.Get("X-Webhook-Signature")))
+		}))
+		defer server.Close()
+
+		fx.webhooksRepo.EXPECT().ClaimDeliveries(fx.ctx, defaultBatchSize, fx.now.Add(defaultBatchSize*defaultTimeout)).Return([]models.WebhookDelivery{delivery}, nil)
+		fx.webhooksRepo.EXPECT().GetSubscriptions(fx.ctx).Return([]models.WebhookSubscription{{ID: 1, URL: server.URL, Secret: secret}}, nil)
+		fx.webhooksRepo.EXPECT().MarkDelivered(fx.ctx, []int64{7}).Return(nil)
+
+		processed, err := fx.Dispatch(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("should retry failed deliveries with backoff", func(t *testing.T) {
+		fx := newFixture(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		failed := delivery
+		failed.Attempts = 3
+		fx.webhooksRepo.EXPECT().ClaimDeliveries(fx.ctx, defaultBatchSize, fx.now.Add(defaultBatchSize*defaultTimeout)).Return([]models.WebhookDelivery{failed}, nil)
+		fx.webhooksRepo.EXPECT().GetSubscriptions(fx.ctx).Return([]models.WebhookSubscription{{ID: 1, URL: server.URL}}, nil)
+		fx.webhooksRepo.EXPECT().MarkFailed(fx.ctx, int64(7), "webhook responded with 503 Service Unavailable", fx.now.Add(8*defaultRetryDelay)).
+			Return(nil)
+
+		_, err := fx.Dispatch(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should move delivery to dead letters after max attempts", func(t *testing.T) {
+		fx := newFixture(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		failed := delivery
+		failed.Attempts = defaultMaxAttempts - 1
+		fx.webhooksRepo.EXPECT().ClaimDeliveries(fx.ctx, defaultBatchSize, fx.now.Add(defaultBatchSize*defaultTimeout)).Return([]models.WebhookDelivery{failed}, nil)
+		fx.webhooksRepo.EXPECT().GetSubscriptions(fx.ctx).Return([]models.WebhookSubscription{{ID: 1, URL: server.URL}}, nil)
+		fx.webhooksRepo.EXPECT().MarkDead(fx.ctx, int64(7), gomock.Any()).Return(nil)
+
+		_, err := fx.Dispatch(fx.ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should skip deliveries of deleted subscriptions", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.webhooksRepo.EXPECT().ClaimDeliveries(fx.ctx, defaultBatchSize, fx.now.Add(defaultBatchSize*defaultTimeout)).
+			Return([]models.WebhookDelivery{delivery}, nil)
+		fx.webhooksRepo.EXPECT().GetSubscriptions(fx.ctx).Return(nil, nil)
+
+		processed, err := fx.Dispatch(fx.ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("should fail on repo error", func(t *testing.T) {
+		fx := newFixture(t)
+
+		expectedErr := errors.New(testhelpers.RandomString())
+		fx.webhooksRepo.EXPECT().ClaimDeliveries(fx.ctx, defaultBatchSize, fx.now.Add(defaultBatchSize*defaultTimeout)).Return(nil, expectedErr)
+
+		_, err := fx.Dispatch(fx.ctx)
+
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestImpl_ReplayDeliveries(t *testing.T) {
+	t.Run("should replay dead letters", func(t *testing.T) {
+		fx := newFixture(t)
+
+		fx.webhooksRepo.EXPECT().GetSubscription(fx.ctx, int32(1)).Return(models.WebhookSubscription{ID: 1}, nil)
+		fx.webhooksRepo.EXPECT().ReplayDeliveries(fx.ctx, int32(1), nil).Return(2, nil)
+
+		replayed, err := fx.ReplayDeliveries(fx.ctx, 1, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, replayed)
+	})
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{}`))
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("secret", 1700000000, []byte(`{}`), signature))
+	assert.False(t, Verify("other", 1700000000, []byte(`{}`), signature))
+	assert.False(t, Verify("secret", 1700000001, []byte(`{}`), signature))
+}
+
+type fixture struct {
+	*impl
+
+	t            *testing.T
+	ctx          context.Context
+	now          time.Time
+	tx           *mockDB.MockTransactor
+	webhooksRepo *mockWebhooksRepo.MockRepository
+	productsSrv  *mockProductsSrv.MockService
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	fx := &fixture{
+		t:            t,
+		ctx:          ctx,
+		now:          time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		tx:           mockDB.NewMockTransactor(ctrl),
+		webhooksRepo: mockWebhooksRepo.NewMockRepository(ctrl),
+		productsSrv:  mockProductsSrv.NewMockService(ctrl),
+	}
+	fx.impl = NewService(Config{}, fx.tx, fx.webhooksRepo, fx.productsSrv).(*impl)
+	fx.impl.now = func() time.Time {
+		return fx.now
+	}
+	return fx
+}
+
+func (fx *fixture) expectTx() {
+	fx.tx.EXPECT().InTx(fx.ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,22 @@
+package tracing
+
+const (
+	ExporterNone   = ""
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	// Exporter is otlp, stdout or empty to disable tracing
+	Exporter    string
+	ServiceName string `koanf:"service_name"`
+	// SampleRatio is the fraction of traces sampled, all traces are sampled if unset
+	SampleRatio float64 `koanf:"sample_ratio"`
+	OTLP        OTLPConfig
+}
+
+type OTLPConfig struct {
+	// Endpoint is the host and port of the OTLP gRPC collector
+	Endpoint string
+	Insecure bool
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Run("should not trace without exporter", func(t *testing.T) {
+		tp, shutdown, err := NewTracerProvider(context.Background(), Config{})
+
+		require.NoError(t, err)
+		assert.IsType(t, noop.TracerProvider{}, tp)
+		require.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("should create stdout exporter", func(t *testing.T) {
+		tp, shutdown, err := NewTracerProvider(context.Background(), Config{Exporter: ExporterStdout})
+
+		require.NoError(t, err)
+		assert.IsType(t, &sdktrace.TracerProvider{}, tp)
+		require.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("should fail on unknown exporter", func(t *testing.T) {
+		_, _, err := NewTracerProvider(context.Background(), Config{Exporter: "zipkin"})
+
+		require.Error(t, err)
+	})
+}
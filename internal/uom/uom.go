@@ -0,0 +1,165 @@
+// Package uom converts article quantities between units of measure
+package uom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"warehouse/internal/models"
+)
+
+var (
+	ErrInvalidQuantity  = errors.New("invalid quantity")
+	ErrUnknownUnit      = errors.New("unknown unit")
+	ErrIncompatibleUnit = errors.New("incompatible unit")
+	ErrNotIntegral      = errors.New("quantity is not a whole number of article units")
+)
+
+// Quantity is an amount expressed in some unit, empty unit means the article unit
+type Quantity struct {
+	Value *big.Rat
+	Unit  string
+}
+
+// ParseQuantity parses strings like "12", "1.5 m" or "2 box"
+func ParseQuantity(s string) (Quantity, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Quantity{}, fmt.Errorf("%w: %q", ErrInvalidQuantity, s)
+	}
+	value, ok := new(big.Rat).SetString(fields[0])
+	if !ok || value.Sign() < 0 {
+		return Quantity{}, fmt.Errorf("%w: %q", ErrInvalidQuantity, s)
+	}
+	q := Quantity{Value: value}
+	if len(fields) == 2 {
+		q.Unit = fields[1]
+	}
+	return q, nil
+}
+
+// IsIntegral reports whether the value is a whole number
+func (q Quantity) IsIntegral() bool {
+	return q.Value.IsInt()
+}
+
+// Converter normalizes quantities to the article unit, articles are stocked in the base unit of their dimension
+type Converter struct {
+	units map[string]models.Unit
+	// bases maps a dimension to its base unit, the unit with factor one
+	bases        map[string]string
+	articleUnits map[int32]map[string]int64
+}
+
+// Source provides the units of measure, it is implemented by the units repository
+type Source interface {
+	GetUnits(ctx context.Context) ([]models.Unit, error)
+	GetArticleUnits(ctx context.Context) ([]models.ArticleUnit, error)
+}
+
+// LoadConverter creates a converter with the current units of measure
+func LoadConverter(ctx context.Context, src Source) (*Converter, error) {
+	units, err := src.GetUnits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	articleUnits, err := src.GetArticleUnits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article units: %w", err)
+	}
+	return NewConverter(units, articleUnits), nil
+}
+
+func NewConverter(units []models.Unit, articleUnits []models.ArticleUnit) *Converter {
+	c := &Converter{
+		units:        make(map[string]models.Unit, len(units)),
+		bases:        make(map[string]string),
+		articleUnits: make(map[int32]map[string]int64),
+	}
+	for _, u := range units {
+		c.units[u.Code] = u
+		if u.Factor == 1 {
+			c.bases[u.Dimension] = u.Code
+		}
+	}
+	for _, u := range articleUnits {
+		if c.articleUnits[u.ArticleID] == nil {
+			c.articleUnits[u.ArticleID] = make(map[string]int64)
+		}
+		c.articleUnits[u.ArticleID][u.Code] = u.Factor
+	}
+	return c
+}
+
+// Base returns the base unit of the unit's dimension and the number of base units in one unit.
+// Stock kept in the base unit holds any quantity of the dimension as a whole number, e.g. 50 cm of an article
+// declared in m.
+func (c *Converter) Base(unit string) (string, int64, error) {
+	u, ok := c.units[unit]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %q", ErrUnknownUnit, unit)
+	}
+	base, ok := c.bases[u.Dimension]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: no base unit of %q", ErrUnknownUnit, u.Dimension)
+	}
+	return base, u.Factor, nil
+}
+
+// Factor returns the number of article units in one unit
+func (c *Converter) Factor(article models.Article, unit string) (*big.Rat, error) {
+	if unit == "" || unit == article.Unit {
+		return big.NewRat(1, 1), nil
+	}
+	if factor, ok := c.articleUnits[article.ID][unit]; ok {
+		return new(big.Rat).SetInt64(factor), nil
+	}
+
+	from, ok := c.units[unit]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownUnit, unit)
+	}
+	to, ok := c.units[article.Unit]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownUnit, article.Unit)
+	}
+	if from.Dimension != to.Dimension {
+		return nil, fmt.Errorf("%w: %q to %q", ErrIncompatibleUnit, unit, article.Unit)
+	}
+	return big.NewRat(from.Factor, to.Factor), nil
+}
+
+// ToBase converts the quantity to the article unit
+func (c *Converter) ToBase(article models.Article, q Quantity) (int32, error) {
+	factor, err := c.Factor(article, q.Unit)
+	if err != nil {
+		return 0, err
+	}
+	value := new(big.Rat).Mul(q.Value, factor)
+	if !value.IsInt() {
+		return 0, fmt.Errorf("%w: %s %s of article %d", ErrNotIntegral, q.Value.RatString(), q.Unit, article.ID)
+	}
+	if !value.Num().IsInt64() || value.Num().Int64() > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %s %s is too large", ErrInvalidQuantity, q.Value.RatString(), q.Unit)
+	}
+	return int32(value.Num().Int64()), nil
+}
+
+// Normalize converts a BOM line to the article unit
+func (c *Converter) Normalize(article models.Article, item models.ProductArticle) (models.ProductArticle, error) {
+	quantity, err := c.ToBase(article, Quantity{
+		Value: big.NewRat(int64(item.Quantity), 1),
+		Unit:  item.Unit,
+	})
+	if err != nil {
+		return models.ProductArticle{}, err
+	}
+	return models.ProductArticle{
+		ID:       item.ID,
+		Quantity: quantity,
+	}, nil
+}
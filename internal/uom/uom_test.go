@@ -0,0 +1,125 @@
+package uom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"warehouse/internal/models"
+)
+
+func TestParseQuantity(t *testing.T) {
+	t.Run("should parse unitless quantity", func(t *testing.T) {
+		q, err := ParseQuantity("12")
+
+		require.NoError(t, err)
+		assert.Equal(t, "12", q.Value.RatString())
+		assert.Empty(t, q.Unit)
+	})
+
+	t.Run("should parse quantity with unit", func(t *testing.T) {
+		q, err := ParseQuantity("1.5 m")
+
+		require.NoError(t, err)
+		assert.Equal(t, "3/2", q.Value.RatString())
+		assert.Equal(t, "m", q.Unit)
+	})
+
+	t.Run("should fail on invalid quantity", func(t *testing.T) {
+		for _, s := range []string{"", "abc", "-1", "1 m extra"} {
+			_, err := ParseQuantity(s)
+
+			require.ErrorIs(t, err, ErrInvalidQuantity, s)
+		}
+	})
+}
+
+func TestConverter_ToBase(t *testing.T) {
+	units := []models.Unit{
+		{Code: "pcs", Dimension: "count", Factor: 1},
+		{Code: "dozen", Dimension: "count", Factor: 12},
+		{Code: "mm", Dimension: "length", Factor: 1},
+		{Code: "cm", Dimension: "length", Factor: 10},
+		{Code: "m", Dimension: "length", Factor: 1000},
+	}
+	articleUnits := []models.ArticleUnit{
+		{ArticleID: 1, Code: "box", Factor: 100},
+	}
+	c := NewConverter(units, articleUnits)
+	screw := models.Article{ID: 1, Unit: "pcs"}
+	fabric := models.Article{ID: 2, Unit: "cm"}
+
+	cases := []struct {
+		name     string
+		article  models.Article
+		quantity string
+		expected int32
+		err      error
+	}{
+		{name: "article unit", article: screw, quantity: "7", expected: 7},
+		{name: "packaging unit", article: screw, quantity: "3 box", expected: 300},
+		{name: "global unit", article: screw, quantity: "2 dozen", expected: 24},
+		{name: "bigger unit", article: fabric, quantity: "1.5 m", expected: 150},
+		{name: "smaller unit", article: fabric, quantity: "20 mm", expected: 2},
+		{name: "not integral", article: fabric, quantity: "5 mm", err: ErrNotIntegral},
+		{name: "incompatible unit", article: fabric, quantity: "1 dozen", err: ErrIncompatibleUnit},
+		{name: "unknown unit", article: fabric, quantity: "1 box", err: ErrUnknownUnit},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseQuantity(tc.quantity)
+			require.NoError(t, err)
+
+			value, err := c.ToBase(tc.article, q)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestConverter_Base(t *testing.T) {
+	c := NewConverter([]models.Unit{
+		{Code: "mm", Dimension: "length", Factor: 1},
+		{Code: "cm", Dimension: "length", Factor: 10},
+		{Code: "m", Dimension: "length", Factor: 1000},
+		{Code: "yd", Dimension: "imperial", Factor: 36},
+	}, nil)
+
+	t.Run("should return base unit and factor", func(t *testing.T) {
+		base, factor, err := c.Base("m")
+
+		require.NoError(t, err)
+		assert.Equal(t, "mm", base)
+		assert.EqualValues(t, 1000, factor)
+	})
+
+	t.Run("should convert fractions of the declared unit", func(t *testing.T) {
+		base, _, err := c.Base("m")
+		require.NoError(t, err)
+		q, err := ParseQuantity("50 cm")
+		require.NoError(t, err)
+
+		value, err := c.ToBase(models.Article{ID: 1, Unit: base}, q)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 500, value)
+	})
+
+	t.Run("should fail without base unit", func(t *testing.T) {
+		_, _, err := c.Base("yd")
+
+		require.ErrorIs(t, err, ErrUnknownUnit)
+	})
+
+	t.Run("should fail on unknown unit", func(t *testing.T) {
+		_, _, err := c.Base("box")
+
+		require.ErrorIs(t, err, ErrUnknownUnit)
+	})
+}